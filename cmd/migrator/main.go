@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
+	"slices"
 	"url-shortener/internal/config"
+	domain "url-shortener/internal/domain/url"
 	"url-shortener/internal/lib/logger/slogcute"
+	"url-shortener/internal/storage/sqlite"
 
 	"github.com/golang-migrate/migrate/v4"
 
@@ -50,6 +55,17 @@ func main() {
 	}
 
 	log.Info("migrations completed successfully")
+
+	if direction == directionUp {
+		if err := normalizeURLs(log, cfg.StoragePath, cfg.URL.Canonicalize); err != nil {
+			log.Error("url normalization failed", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		if err := reportOwnersWithoutUID(log, cfg.StoragePath); err != nil {
+			log.Error("consistency check failed", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+	}
 }
 
 func setupLogger() *slog.Logger {
@@ -115,3 +131,76 @@ func runMigrations(log *slog.Logger, storagePath, migrationsPath, migrationTable
 
 	return nil
 }
+
+// normalizeURLs canonicalizes the normalized URLs the normalized_url migration
+// copied from the raw destination URLs, so that links created before it are
+// found when deduplicating. It uses the canonicalization settings of the
+// service and does nothing for links already canonicalized.
+func normalizeURLs(log *slog.Logger, storagePath string, cfg config.CanonicalizeConfig) error {
+	storage, err := sqlite.New(storagePath)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer func() {
+		if err := storage.Close(); err != nil {
+			log.Error("failed to close storage", slog.String("error", err.Error()))
+		}
+	}()
+
+	canonicalizer := domain.NewCanonicalizer(domain.CanonicalizeOptions{
+		StripDefaultPort: cfg.StripDefaultPort,
+		StripFragment:    cfg.StripFragment,
+		TrackingParams:   cfg.TrackingParams,
+	})
+
+	count, err := storage.NormalizeURLs(context.Background(), canonicalizer.Canonicalize)
+	if err != nil {
+		return fmt.Errorf("failed to normalize urls: %w", err)
+	}
+
+	log.Info("normalized link urls", slog.Int("count", count))
+
+	return nil
+}
+
+// reportOwnersWithoutUID logs the links whose owner user ID could not be
+// backfilled. They stay matched by owner email until the owner manages one of
+// their links.
+func reportOwnersWithoutUID(log *slog.Logger, storagePath string) error {
+	storage, err := sqlite.New(storagePath)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+	defer func() {
+		if err := storage.Close(); err != nil {
+			log.Error("failed to close storage", slog.String("error", err.Error()))
+		}
+	}()
+
+	owners, err := storage.OwnersWithoutUID(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to count links without owner id: %w", err)
+	}
+
+	if len(owners) == 0 {
+		log.Info("all links have an owner id")
+		return nil
+	}
+
+	total := 0
+	for _, email := range slices.Sorted(maps.Keys(owners)) {
+		count := owners[email]
+		total += count
+		log.Warn("links without owner id",
+			slog.String("owner_email", email),
+			slog.Int("count", count),
+		)
+	}
+
+	log.Warn("some links could not be backfilled with an owner id and are matched by email until their owner next manages one",
+		slog.Int("owners", len(owners)),
+		slog.Int("links", total),
+	)
+
+	return nil
+}
@@ -3,21 +3,44 @@ package main
 import (
 	"context"
 	"log/slog"
+	"net"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"os/signal"
 	"syscall"
 	ssogrpc "url-shortener/internal/client/grpc"
 	"url-shortener/internal/config"
+	domain "url-shortener/internal/domain/url"
 	"url-shortener/internal/http-server/handlers/redirect"
+	"url-shortener/internal/http-server/handlers/url/audit"
 	"url-shortener/internal/http-server/handlers/url/delete"
+	"url-shortener/internal/http-server/handlers/url/folder"
+	"url-shortener/internal/http-server/handlers/url/history"
+	"url-shortener/internal/http-server/handlers/url/list"
 	"url-shortener/internal/http-server/handlers/url/save"
+	"url-shortener/internal/http-server/handlers/url/search"
+	signurl "url-shortener/internal/http-server/handlers/url/sign"
+	"url-shortener/internal/http-server/handlers/url/stats"
+	"url-shortener/internal/http-server/handlers/url/tags"
+	"url-shortener/internal/http-server/handlers/url/transfer"
+	"url-shortener/internal/http-server/handlers/url/trash"
+	"url-shortener/internal/http-server/handlers/url/update"
+	"url-shortener/internal/http-server/handlers/workspace"
+	mwAudit "url-shortener/internal/http-server/middleware/audit"
 	mwAuth "url-shortener/internal/http-server/middleware/auth"
+	"url-shortener/internal/http-server/middleware/clientip"
 	mwLogger "url-shortener/internal/http-server/middleware/logger"
 	mwMetrics "url-shortener/internal/http-server/middleware/metrics"
+	"url-shortener/internal/lib/blocklist"
+	"url-shortener/internal/lib/geoip"
 	"url-shortener/internal/lib/jwt"
 	"url-shortener/internal/lib/logger/slogcute"
+	"url-shortener/internal/lib/sign"
+	"url-shortener/internal/service/healthcheck"
+	"url-shortener/internal/service/purge"
 	"url-shortener/internal/service/url"
+	workspacesvc "url-shortener/internal/service/workspace"
 	"url-shortener/internal/storage"
 	"url-shortener/internal/storage/instrumented"
 	"url-shortener/internal/storage/sqlite"
@@ -60,15 +83,121 @@ func main() {
 		log.Error("Failed to initialize storage", slog.String("error", err.Error()))
 		os.Exit(1)
 	}
+	if !sqlite.FullTextSearch {
+		log.Warn("Built without the sqlite_fts5 tag, link search falls back to pattern matching and scans every link")
+	}
 
 	// Wrap storage with metrics instrumentation
 	var storageInstance storage.Storage = instrumented.New(sqliteStorage)
 
-	urlShortenerService := url.New(log, storageInstance, ssoClient)
+	// Initialize malicious URL blocklist
+	sources := make([]blocklist.Source, 0, len(cfg.Blocklist.Files))
+	for _, file := range cfg.Blocklist.Files {
+		sources = append(sources, blocklist.Source{Path: file.Path, Format: file.Format})
+	}
+
+	urlBlocklist, err := blocklist.New(log, sources)
+	if err != nil {
+		log.Error("Failed to load blocklist", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Context for background workers, cancelled on shutdown
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	go urlBlocklist.Watch(bgCtx, cfg.Blocklist.ReloadInterval)
+
+	// Initialize GeoIP database for country targeting
+	var countryLocator url.CountryLocator
+	if cfg.GeoIP.DatabasePath != "" {
+		geoReader, err := geoip.Open(cfg.GeoIP.DatabasePath)
+		if err != nil {
+			log.Error("Failed to open GeoIP database", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		log.Info("GeoIP database loaded", slog.String("type", geoReader.Metadata().DatabaseType))
+		countryLocator = geoReader
+	}
+
+	// Check the secret for the cookies of unlocked password protected links
+	switch secret := cfg.URL.Password.CookieSecret; {
+	case secret == "":
+		log.Warn("no password cookie secret configured, unlocked links are forgotten on restart")
+	case len(secret) < sign.MinKeyLength:
+		log.Error("password cookie secret is too short", slog.Int("min_length", sign.MinKeyLength))
+		os.Exit(1)
+	}
+
+	// Load the keys of signed redirect URLs
+	var signingKeys *sign.Keyring
+	if len(cfg.URL.SignedURLs.Keys) > 0 {
+		keys := make([]sign.Key, 0, len(cfg.URL.SignedURLs.Keys))
+		for _, key := range cfg.URL.SignedURLs.Keys {
+			keys = append(keys, sign.Key{ID: key.ID, Secret: []byte(key.Secret)})
+		}
+		signingKeys, err = sign.NewKeyring(keys, cfg.URL.SignedURLs.ActiveKey)
+		if err != nil {
+			log.Error("invalid signed url keys", slog.String("error", err.Error()))
+			os.Exit(1)
+		}
+		if cfg.URL.BaseURL == "" {
+			log.Warn("no base url configured, signed urls are minted as paths")
+		}
+	}
+
+	if err := domain.ValidateRedirectType(cfg.URL.DefaultRedirectType); err != nil {
+		log.Error("invalid default redirect type", slog.Int("redirect_type", cfg.URL.DefaultRedirectType), slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	// Keep destinations from pointing back at the shortener itself
+	if len(cfg.URL.Policy.ShortDomains) == 0 {
+		if host := shortDomain(cfg.URL.BaseURL, cfg.HTTPServer.Address); host != "" {
+			cfg.URL.Policy.ShortDomains = []string{host}
+			log.Info("short domains default to the host the shortener is served on", slog.String("host", host))
+		} else {
+			log.Warn("no short domains configured, destinations pointing back at the shortener are not refused; set url.policy.short_domains or url.base_url")
+		}
+	}
+
+	urlShortenerService := url.New(log, storageInstance, ssoClient, urlBlocklist, countryLocator, signingKeys, cfg.URL)
+	workspaceService := workspacesvc.New(log, storageInstance, ssoClient)
+
+	// Start link health checker if enabled
+	if cfg.HealthCheck.Enabled {
+		checker := healthcheck.New(log, storageInstance, healthcheck.Options{
+			Interval:        cfg.HealthCheck.Interval,
+			RecheckAfter:    cfg.HealthCheck.RecheckAfter,
+			BatchSize:       cfg.HealthCheck.BatchSize,
+			Concurrency:     cfg.HealthCheck.Concurrency,
+			HostDelay:       cfg.HealthCheck.HostDelay,
+			Timeout:         cfg.HealthCheck.Timeout,
+			UserAgent:       cfg.HealthCheck.UserAgent,
+			AllowPrivateIPs: cfg.URL.Policy.AllowPrivateIPs,
+		})
+		go checker.Run(bgCtx)
+	}
+
+	// Purge links that stayed in the trash longer than the retention period
+	purger := purge.New(log, storageInstance, purge.Options{
+		Interval:   cfg.URL.Trash.PurgeInterval,
+		Retention:  cfg.URL.Trash.Retention,
+		Quarantine: cfg.URL.Trash.Quarantine,
+		BatchSize:  cfg.URL.Trash.BatchSize,
+	})
+	go purger.Run(bgCtx)
+
+	trustedProxies, err := clientip.ParseTrustedProxies(cfg.HTTPServer.TrustedProxies)
+	if err != nil {
+		log.Error("invalid trusted proxies", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
 
 	router := chi.NewRouter()
 
 	router.Use(middleware.RequestID)
+	router.Use(clientip.New(log, trustedProxies))
 	router.Use(mwMetrics.New())
 	router.Use(mwLogger.New(log))
 	router.Use(middleware.Recoverer)
@@ -83,13 +212,78 @@ func main() {
 	// Protected routes (require JWT)
 	router.Group(func(r chi.Router) {
 		r.Use(mwAuth.New(log, jwtValidator))
+		r.Use(mwAudit.New())
 
 		r.Post("/url", save.New(log, urlShortenerService))
+		r.Get("/url", list.New(log, urlShortenerService))
+		r.Get("/url/search", search.New(log, urlShortenerService))
+		r.Get("/url/{alias}/stats", stats.New(log, urlShortenerService))
+		r.Patch("/url/{alias}", update.New(log, urlShortenerService))
+		r.Post("/url/{alias}/sign", signurl.New(log, urlShortenerService))
 		r.Delete("/{alias}", delete.New(log, urlShortenerService))
+		r.Get("/url/trash", trash.New(log, urlShortenerService))
+		r.Post("/url/trash/{alias}/restore", trash.NewRestore(log, urlShortenerService))
+		r.Delete("/url/trash/{alias}", trash.NewPurge(log, urlShortenerService))
+		r.Get("/{alias}/history", history.New(log, urlShortenerService))
+		r.Post("/{alias}/history/{id}/revert", history.NewRevert(log, urlShortenerService))
+		r.Get("/url/audit", audit.New(log, urlShortenerService))
+		r.Post("/url/{alias}/transfer", transfer.New(log, urlShortenerService))
+		r.Get("/url/transfers", transfer.NewList(log, urlShortenerService))
+		r.Post("/url/transfers/{alias}/accept", transfer.NewAccept(log, urlShortenerService))
+		r.Delete("/url/transfers/{alias}", transfer.NewCancel(log, urlShortenerService))
+		r.Post("/url/transfers/bulk", transfer.NewBulk(log, urlShortenerService))
+		r.Post("/url/folders", folder.New(log, urlShortenerService))
+		r.Get("/url/folders", folder.NewList(log, urlShortenerService))
+		r.Patch("/url/folders/{id}", folder.NewRename(log, urlShortenerService))
+		r.Delete("/url/folders/{id}", folder.NewDelete(log, urlShortenerService))
+		r.Get("/url/tags", tags.New(log, urlShortenerService))
+		r.Post("/url/workspaces", workspace.New(log, workspaceService))
+		r.Get("/url/workspaces", workspace.NewList(log, workspaceService))
+		r.Get("/url/workspaces/{id}/members", workspace.NewMembers(log, workspaceService))
+		r.Put("/url/workspaces/{id}/members/{email}", workspace.NewSetMember(log, workspaceService))
+		r.Delete("/url/workspaces/{id}/members/{email}", workspace.NewRemoveMember(log, workspaceService))
 	})
 
 	// Public routes
-	router.Get("/{alias}", redirect.New(log, urlShortenerService))
+	interstitial, err := redirect.LoadInterstitialTemplate(cfg.Redirect.Interstitial.TemplatePath)
+	if err != nil {
+		log.Error("failed to load interstitial template", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	comingSoon, err := redirect.LoadComingSoonTemplate(cfg.Redirect.ComingSoon.TemplatePath)
+	if err != nil {
+		log.Error("failed to load coming soon template", slog.String("error", err.Error()))
+		os.Exit(1)
+	}
+
+	if code := cfg.Redirect.ComingSoon.StatusCode; code != http.StatusNotFound && code != http.StatusServiceUnavailable {
+		log.Error("coming soon status code must be 404 or 503", slog.Int("status_code", code))
+		os.Exit(1)
+	}
+
+	redirectHandler := redirect.New(log, urlShortenerService, urlShortenerService, redirect.Options{
+		PermanentMaxAge:     cfg.Redirect.PermanentMaxAge,
+		Interstitial:        interstitial,
+		Countdown:           cfg.Redirect.Interstitial.Countdown,
+		VariantCookieMaxAge: cfg.Redirect.VariantCookieMaxAge,
+		ComingSoon:          comingSoon,
+		ComingSoonStatus:    cfg.Redirect.ComingSoon.StatusCode,
+	})
+	// Custom aliases are validated against the fixed routes above, see
+	// domain.ValidateAlias.
+	router.Get("/{alias}", redirectHandler)
+	router.Head("/{alias}", redirectHandler)
+	router.Get("/{alias}/*", redirectHandler)
+	router.Head("/{alias}/*", redirectHandler)
+
+	unlockHandler := redirect.NewUnlock(log, urlShortenerService)
+	router.Post("/{alias}", unlockHandler)
+	router.Post("/{alias}/*", unlockHandler)
+
+	previewHandler := redirect.NewPreview(log, urlShortenerService)
+	router.Get("/{alias}+", previewHandler)
+	router.Get("/{alias}/preview", previewHandler)
 
 	// Start metrics server if enabled
 	if cfg.Metrics.Enabled {
@@ -156,6 +350,9 @@ func main() {
 		log.Info("HTTP server stopped gracefully")
 	}
 
+	// Stop background workers before closing storage
+	stopBackground()
+
 	// Close storage connection
 	if err := storageInstance.Close(); err != nil {
 		log.Error("failed to close storage", slog.String("error", err.Error()))
@@ -166,6 +363,24 @@ func main() {
 	log.Info("application shutdown complete")
 }
 
+// shortDomain returns the host the shortener is served on: the host of the
+// base URL, or else the host of the listen address unless it listens on every
+// interface. It returns "" when neither names a host.
+func shortDomain(baseURL, address string) string {
+	if u, err := neturl.Parse(baseURL); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return ""
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		return ""
+	}
+	return host
+}
+
 func SetupLogger(env string) *slog.Logger {
 	var log *slog.Logger
 
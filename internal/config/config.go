@@ -9,13 +9,18 @@ import (
 )
 
 type Config struct {
-	Env         string           `yaml:"env"`
-	StoragePath string           `yaml:"storage_path" env-required:"true"`
-	HTTPServer  HTTPServerConfig `yaml:"http_server"`
-	Migrations  MigrationsConfig `yaml:"migrations"`
-	Clients     ClientsConfig    `yaml:"clients"`
-	AppSecret   string           `yaml:"app_secret" env-required:"true"`
-	Metrics     MetricsConfig    `yaml:"metrics"`
+	Env         string            `yaml:"env"`
+	StoragePath string            `yaml:"storage_path" env-required:"true"`
+	HTTPServer  HTTPServerConfig  `yaml:"http_server"`
+	Migrations  MigrationsConfig  `yaml:"migrations"`
+	Clients     ClientsConfig     `yaml:"clients"`
+	AppSecret   string            `yaml:"app_secret" env-required:"true"`
+	Metrics     MetricsConfig     `yaml:"metrics"`
+	URL         URLConfig         `yaml:"url"`
+	Blocklist   BlocklistConfig   `yaml:"blocklist"`
+	HealthCheck HealthCheckConfig `yaml:"health_check"`
+	Redirect    RedirectConfig    `yaml:"redirect"`
+	GeoIP       GeoIPConfig       `yaml:"geoip"`
 }
 
 type HTTPServerConfig struct {
@@ -23,6 +28,9 @@ type HTTPServerConfig struct {
 	Timeout         time.Duration `yaml:"timeout" env-default:"5s"`
 	IdleTimeout     time.Duration `yaml:"idle_timeout" env-default:"60s"`
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"10s"`
+	// TrustedProxies are the IPs and CIDR prefixes of reverse proxies whose
+	// X-Forwarded-For header is believed. Empty uses the peer address as is.
+	TrustedProxies []string `yaml:"trusted_proxies"`
 }
 
 type Client struct {
@@ -46,6 +54,176 @@ type MetricsConfig struct {
 	Address string `yaml:"address" env-default:":9090"`
 }
 
+type URLConfig struct {
+	// Dedup makes Shorten return the owner's existing alias for an already
+	// shortened destination unless the request overrides it.
+	Dedup bool `yaml:"dedup" env-default:"false"`
+	// DefaultRedirectType is the redirect status code for links created
+	// without one: 301, 302, 307 or 308.
+	DefaultRedirectType int                `yaml:"default_redirect_type" env-default:"302"`
+	Canonicalize        CanonicalizeConfig `yaml:"canonicalize"`
+	Policy              PolicyConfig       `yaml:"policy"`
+	// WarnDomains sends every link to these domains and their subdomains
+	// through the interstitial page, as if the link was marked "warn".
+	WarnDomains []string       `yaml:"warn_domains"`
+	Password    PasswordConfig `yaml:"password"`
+	// BaseURL is the public URL the shortener is served on, e.g.
+	// "https://sho.rt"; it makes minted signed URLs absolute.
+	BaseURL    string          `yaml:"base_url"`
+	SignedURLs SignedURLConfig `yaml:"signed_urls"`
+	Trash      TrashConfig     `yaml:"trash"`
+	Transfer   TransferConfig  `yaml:"transfer"`
+}
+
+// TransferConfig configures ownership transfers of links.
+type TransferConfig struct {
+	// RequireAcceptance makes transfers wait for the recipient to accept them
+	// unless the request says otherwise.
+	RequireAcceptance bool `yaml:"require_acceptance" env-default:"false"`
+	// RequestTTL is how long a pending transfer can be accepted.
+	RequestTTL time.Duration `yaml:"request_ttl" env-default:"168h"`
+}
+
+// TrashConfig configures the trash deleted links are moved to and the
+// background job that purges it.
+type TrashConfig struct {
+	// Retention is how long deleted links can be restored before they are purged.
+	Retention time.Duration `yaml:"retention" env-default:"720h"`
+	// Quarantine is how long the alias of a deleted link stays reserved after
+	// the deletion, also when the link is purged before.
+	Quarantine time.Duration `yaml:"quarantine" env-default:"2160h"`
+	// PurgeInterval is the pause between two passes of the purge job.
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+	// BatchSize is the maximum number of links purged in one pass.
+	BatchSize int `yaml:"batch_size" env-default:"100"`
+}
+
+// SignedURLConfig configures signed, expiring redirect URLs. To rotate keys,
+// add a new key, make it the active key, and remove the old one once the
+// URLs signed with it have expired.
+type SignedURLConfig struct {
+	// Keys verify signed URLs; empty disables signed URLs.
+	Keys []SigningKey `yaml:"keys"`
+	// ActiveKey is the ID of the key new URLs are signed with; empty selects
+	// the first key.
+	ActiveKey string `yaml:"active_key"`
+	// DefaultTTL is how long minted URLs work unless the request says otherwise.
+	DefaultTTL time.Duration `yaml:"default_ttl" env-default:"72h"`
+	// MaxTTL is the longest lifetime a minted URL may have.
+	MaxTTL time.Duration `yaml:"max_ttl" env-default:"720h"`
+}
+
+// SigningKey is an HMAC key for signed URLs; the secret needs at least 32 bytes.
+type SigningKey struct {
+	ID     string `yaml:"id"`
+	Secret string `yaml:"secret"`
+}
+
+// PasswordConfig configures password protected links.
+type PasswordConfig struct {
+	// CookieSecret signs the cookies that keep protected links unlocked, at
+	// least 32 bytes. Empty uses a random secret, so unlocks are lost on
+	// restart and not shared between instances.
+	CookieSecret string `yaml:"cookie_secret"`
+	// UnlockTTL is how long a link stays unlocked after entering its password.
+	UnlockTTL time.Duration `yaml:"unlock_ttl" env-default:"1h"`
+	// MaxAttempts wrong passwords per link and client address are allowed
+	// within AttemptWindow; further attempts are refused until it ends.
+	// Attempts without a known client address are not limited.
+	MaxAttempts   int           `yaml:"max_attempts" env-default:"5"`
+	AttemptWindow time.Duration `yaml:"attempt_window" env-default:"15m"`
+}
+
+// CanonicalizeConfig controls how destination URLs are normalized for
+// deduplication and for the stored normalized_url column.
+type CanonicalizeConfig struct {
+	StripDefaultPort bool     `yaml:"strip_default_port"`
+	StripFragment    bool     `yaml:"strip_fragment"`
+	TrackingParams   []string `yaml:"tracking_params" env-default:"utm_*,fbclid,gclid,dclid,msclkid,yclid,mc_cid,mc_eid,_ga"`
+}
+
+// PolicyConfig lists the rules destination URLs must satisfy to be shortened.
+// Domain lists match the domain and all of its subdomains.
+type PolicyConfig struct {
+	// ShortDomains are the domains the shortener is served on, which
+	// destinations may not point to. Empty defaults to the host of BaseURL,
+	// or else of the HTTP server address.
+	ShortDomains []string `yaml:"short_domains"`
+	// AllowPrivateIPs accepts private, loopback and link-local IP literals,
+	// which are rejected by default.
+	AllowPrivateIPs bool     `yaml:"allow_private_ips"`
+	DeniedDomains   []string `yaml:"denied_domains"`
+	AllowedDomains  []string `yaml:"allowed_domains"`
+}
+
+// BlocklistConfig lists local files with malicious hosts and URL prefixes.
+// Files are polled every ReloadInterval and reloaded when they change; a zero
+// ReloadInterval disables reloading.
+type BlocklistConfig struct {
+	Files          []BlocklistFile `yaml:"files"`
+	ReloadInterval time.Duration   `yaml:"reload_interval" env-default:"30s"`
+}
+
+type BlocklistFile struct {
+	Path string `yaml:"path"`
+	// Format is "plain" (one host or URL prefix per line) or "hosts" (hosts-file format).
+	Format string `yaml:"format"`
+}
+
+// HealthCheckConfig configures the background worker that checks whether
+// stored destinations still respond.
+type HealthCheckConfig struct {
+	Enabled      bool          `yaml:"enabled" env-default:"false"`
+	Interval     time.Duration `yaml:"interval" env-default:"1m"`
+	RecheckAfter time.Duration `yaml:"recheck_after" env-default:"24h"`
+	BatchSize    int           `yaml:"batch_size" env-default:"100"`
+	Concurrency  int           `yaml:"concurrency" env-default:"8"`
+	HostDelay    time.Duration `yaml:"host_delay" env-default:"1s"`
+	Timeout      time.Duration `yaml:"timeout" env-default:"10s"`
+	UserAgent    string        `yaml:"user_agent" env-default:"url-shortener-healthcheck/1.0"`
+}
+
+// RedirectConfig controls how redirect responses are cached by clients, how
+// the interstitial page for "warn" links and the coming soon page look and how
+// long sticky split variants are remembered.
+type RedirectConfig struct {
+	// PermanentMaxAge is how long clients may cache 301 and 308 redirects.
+	PermanentMaxAge time.Duration      `yaml:"permanent_max_age" env-default:"24h"`
+	Interstitial    InterstitialConfig `yaml:"interstitial"`
+	// VariantCookieMaxAge is how long visitors keep their split variant on
+	// links with sticky variants.
+	VariantCookieMaxAge time.Duration    `yaml:"variant_cookie_max_age" env-default:"720h"`
+	ComingSoon          ComingSoonConfig `yaml:"coming_soon"`
+}
+
+// ComingSoonConfig configures the response for links whose activation window
+// has not started yet.
+type ComingSoonConfig struct {
+	// TemplatePath is an html/template file replacing the built-in page; empty
+	// uses the built-in one.
+	TemplatePath string `yaml:"template_path"`
+	// StatusCode is 404, or 503 to also send Retry-After.
+	StatusCode int `yaml:"status_code" env-default:"404"`
+}
+
+// InterstitialConfig configures the page shown before redirecting to a
+// destination marked "warn".
+type InterstitialConfig struct {
+	// TemplatePath is an html/template file replacing the built-in page; empty
+	// uses the built-in one.
+	TemplatePath string `yaml:"template_path"`
+	// Countdown redirects automatically after this long; zero waits for the
+	// visitor to click through.
+	Countdown time.Duration `yaml:"countdown"`
+}
+
+// GeoIPConfig points to a local country database in the MaxMind DB format,
+// e.g. GeoLite2-Country.mmdb, used by country targeting rules.
+type GeoIPConfig struct {
+	// DatabasePath is the .mmdb file; empty disables country targeting.
+	DatabasePath string `yaml:"database_path"`
+}
+
 func MustLoad() *Config {
 	path := fetchConfigPath()
 	if path == "" {
@@ -0,0 +1,156 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"time"
+)
+
+// Actions recorded in the audit log.
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionRevert  = "revert"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionPurge   = "purge"
+	// ActionTransferRequest records a transfer waiting for the recipient.
+	ActionTransferRequest = "transfer_request"
+	ActionTransfer        = "transfer"
+	// ActionSetMember records a workspace member added or given a new role.
+	ActionSetMember    = "set_member"
+	ActionRemoveMember = "remove_member"
+)
+
+// Subjects of audit log entries. Link entries name the link by alias; the
+// others by ID.
+const (
+	SubjectLink      = "link"
+	SubjectWorkspace = "workspace"
+	SubjectFolder    = "folder"
+)
+
+const (
+	// DefaultLimit is the number of entries returned when a query sets no limit.
+	DefaultLimit = 100
+	// MaxLimit is the largest number of entries a query may ask for.
+	MaxLimit = 1000
+)
+
+// ErrInvalidFilter indicates that an audit log query is malformed.
+var ErrInvalidFilter = errors.New("invalid audit log filter")
+
+// Entry is one record of the append-only audit log. Before and After are
+// JSON snapshots of the subject around the operation; entries without an
+// actor were made by background jobs.
+type Entry struct {
+	ID         int64           `json:"id"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Action     string          `json:"action"`
+	Subject    string          `json:"subject"`
+	SubjectID  int64           `json:"subject_id,omitempty"`
+	Alias      string          `json:"alias,omitempty"`
+	ActorUID   int64           `json:"actor_uid,omitempty"`
+	ActorEmail string          `json:"actor_email,omitempty"`
+	AsAdmin    bool            `json:"as_admin"`
+	RequestID  string          `json:"request_id,omitempty"`
+	ClientIP   string          `json:"client_ip,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+}
+
+// Filter selects audit log entries; zero fields match everything. Entries
+// are returned newest first.
+type Filter struct {
+	ActorUID   int64
+	ActorEmail string
+	Alias      string
+	// Subject and SubjectID select the entries about a workspace or folder.
+	Subject   string
+	SubjectID int64
+	// From and To bound the time of the entries; From is inclusive, To is
+	// exclusive.
+	From *time.Time
+	To   *time.Time
+	// BeforeID continues a previous query below the last ID it returned.
+	BeforeID int64
+	// Limit caps the number of entries; zero means DefaultLimit.
+	Limit int
+}
+
+// Validate checks the filter and applies the default limit.
+func (f *Filter) Validate() error {
+	if f.Limit == 0 {
+		f.Limit = DefaultLimit
+	}
+	if f.Limit < 0 || f.Limit > MaxLimit || f.BeforeID < 0 || f.ActorUID < 0 || f.SubjectID < 0 {
+		return ErrInvalidFilter
+	}
+	switch f.Subject {
+	case "", SubjectLink, SubjectWorkspace, SubjectFolder:
+	default:
+		return ErrInvalidFilter
+	}
+	if f.From != nil && f.To != nil && !f.From.Before(*f.To) {
+		return ErrInvalidFilter
+	}
+	return nil
+}
+
+// RequestInfo describes the request an operation is made for.
+type RequestInfo struct {
+	RequestID  string
+	ClientIP   netip.Addr
+	ActorUID   int64
+	ActorEmail string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns a copy of ctx that carries info.
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFrom returns the request info carried by ctx.
+func RequestInfoFrom(ctx context.Context) (RequestInfo, bool) {
+	info, ok := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info, ok
+}
+
+// NewEntry starts an entry for an action on the link with the given alias,
+// taking the actor from the request info carried by ctx.
+func NewEntry(ctx context.Context, action, alias string) Entry {
+	entry := newEntry(ctx, action, SubjectLink)
+	entry.Alias = alias
+	return entry
+}
+
+// NewSubjectEntry starts an entry for an action on the workspace or folder
+// with the given ID, taking the actor from the request info carried by ctx.
+func NewSubjectEntry(ctx context.Context, action, subject string, subjectID int64) Entry {
+	entry := newEntry(ctx, action, subject)
+	entry.SubjectID = subjectID
+	return entry
+}
+
+func newEntry(ctx context.Context, action, subject string) Entry {
+	entry := Entry{
+		CreatedAt: time.Now(),
+		Action:    action,
+		Subject:   subject,
+	}
+
+	if info, ok := RequestInfoFrom(ctx); ok {
+		entry.ActorUID = info.ActorUID
+		entry.ActorEmail = info.ActorEmail
+		entry.RequestID = info.RequestID
+		if info.ClientIP.IsValid() {
+			entry.ClientIP = info.ClientIP.String()
+		}
+	}
+
+	return entry
+}
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterValidate(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		filter    Filter
+		wantErr   bool
+		wantLimit int
+	}{
+		{name: "Empty filter gets the default limit", filter: Filter{}, wantLimit: DefaultLimit},
+		{name: "Time range", filter: Filter{From: &from, To: &to, Limit: 10}, wantLimit: 10},
+		{name: "Maximum limit", filter: Filter{Limit: MaxLimit}, wantLimit: MaxLimit},
+		{name: "Limit too large", filter: Filter{Limit: MaxLimit + 1}, wantErr: true},
+		{name: "Negative limit", filter: Filter{Limit: -1}, wantErr: true},
+		{name: "Negative before id", filter: Filter{BeforeID: -1}, wantErr: true},
+		{name: "Range ends before it starts", filter: Filter{From: &to, To: &from}, wantErr: true},
+		{name: "Empty range", filter: Filter{From: &from, To: &from}, wantErr: true},
+		{name: "Workspace subject", filter: Filter{Subject: SubjectWorkspace, SubjectID: 3}, wantLimit: DefaultLimit},
+		{name: "Unknown subject", filter: Filter{Subject: "user"}, wantErr: true},
+		{name: "Negative subject id", filter: Filter{Subject: SubjectFolder, SubjectID: -1}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidFilter)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantLimit, tt.filter.Limit)
+		})
+	}
+}
+
+func TestRequestInfo(t *testing.T) {
+	_, ok := RequestInfoFrom(context.Background())
+	require.False(t, ok)
+
+	info := RequestInfo{RequestID: "req-1", ClientIP: netip.MustParseAddr("203.0.113.5"), ActorUID: 7, ActorEmail: "admin@example.com"}
+	got, ok := RequestInfoFrom(WithRequestInfo(context.Background(), info))
+	require.True(t, ok)
+	require.Equal(t, info, got)
+}
+
+func TestNewEntry(t *testing.T) {
+	entry := NewEntry(context.Background(), ActionDelete, "abc")
+	require.Equal(t, ActionDelete, entry.Action)
+	require.Equal(t, "abc", entry.Alias)
+	require.Empty(t, entry.ActorEmail)
+	require.Empty(t, entry.ClientIP)
+	require.False(t, entry.CreatedAt.IsZero())
+
+	ctx := WithRequestInfo(context.Background(), RequestInfo{
+		RequestID:  "req-1",
+		ClientIP:   netip.MustParseAddr("203.0.113.5"),
+		ActorUID:   7,
+		ActorEmail: "admin@example.com",
+	})
+	entry = NewEntry(ctx, ActionUpdate, "abc")
+	require.Equal(t, SubjectLink, entry.Subject)
+	require.Equal(t, "abc", entry.Alias)
+	require.Equal(t, int64(7), entry.ActorUID)
+	require.Equal(t, "admin@example.com", entry.ActorEmail)
+	require.Equal(t, "req-1", entry.RequestID)
+	require.Equal(t, "203.0.113.5", entry.ClientIP)
+
+	entry = NewSubjectEntry(ctx, ActionSetMember, SubjectWorkspace, 3)
+	require.Equal(t, SubjectWorkspace, entry.Subject)
+	require.Equal(t, int64(3), entry.SubjectID)
+	require.Empty(t, entry.Alias)
+	require.Equal(t, int64(7), entry.ActorUID)
+	require.Equal(t, "req-1", entry.RequestID)
+}
@@ -0,0 +1,177 @@
+package url
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// CanonicalizeOptions configures how a Canonicalizer rewrites URLs.
+type CanonicalizeOptions struct {
+	// StripDefaultPort removes :80 from http and :443 from https URLs.
+	StripDefaultPort bool
+	// StripFragment removes the #fragment part.
+	StripFragment bool
+	// TrackingParams lists query parameters to remove, compared case-insensitively.
+	// An entry ending in "*" removes every parameter with that prefix, e.g. "utm_*".
+	TrackingParams []string
+}
+
+// Canonicalizer turns destination URLs into a canonical form used to detect
+// duplicates. The original URL is always stored as given; the canonical form
+// is kept alongside it.
+type Canonicalizer struct {
+	opts           CanonicalizeOptions
+	trackingExact  map[string]struct{}
+	trackingPrefix []string
+}
+
+// NewCanonicalizer creates a Canonicalizer with the given options.
+func NewCanonicalizer(opts CanonicalizeOptions) *Canonicalizer {
+	c := &Canonicalizer{
+		opts:          opts,
+		trackingExact: make(map[string]struct{}, len(opts.TrackingParams)),
+	}
+
+	for _, param := range opts.TrackingParams {
+		param = strings.ToLower(strings.TrimSpace(param))
+		if param == "" {
+			continue
+		}
+		if prefix, ok := strings.CutSuffix(param, "*"); ok {
+			c.trackingPrefix = append(c.trackingPrefix, prefix)
+			continue
+		}
+		c.trackingExact[param] = struct{}{}
+	}
+
+	return c
+}
+
+// Canonicalize returns the canonical form of rawURL: scheme and host are
+// lowercased, IDN hosts are converted to punycode, an empty path becomes "/",
+// tracking parameters are removed and the remaining query parameters are sorted
+// by key. Default ports and fragments are stripped when configured.
+func (c *Canonicalizer) Canonicalize(rawURL string) (string, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+
+	parsedURL.Scheme = strings.ToLower(parsedURL.Scheme)
+
+	host, err := canonicalHost(parsedURL.Hostname())
+	if err != nil {
+		return "", err
+	}
+
+	port := parsedURL.Port()
+	if c.opts.StripDefaultPort && isDefaultPort(parsedURL.Scheme, port) {
+		port = ""
+	}
+
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	parsedURL.Host = host
+
+	if parsedURL.Path == "" {
+		parsedURL.Path = "/"
+		parsedURL.RawPath = ""
+	}
+
+	parsedURL.RawQuery = c.canonicalQuery(parsedURL.RawQuery)
+
+	if c.opts.StripFragment {
+		parsedURL.Fragment = ""
+		parsedURL.RawFragment = ""
+	}
+
+	return parsedURL.String(), nil
+}
+
+// canonicalQuery drops tracking parameters and sorts the rest by key, keeping
+// the relative order of repeated keys.
+func (c *Canonicalizer) canonicalQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	type param struct {
+		key, raw string
+	}
+
+	var params []param
+	for part := range strings.SplitSeq(rawQuery, "&") {
+		if part == "" {
+			continue
+		}
+		key, _, _ := strings.Cut(part, "=")
+		if decoded, err := url.QueryUnescape(key); err == nil {
+			key = decoded
+		}
+		if c.isTrackingParam(key) {
+			continue
+		}
+		params = append(params, param{key: key, raw: part})
+	}
+
+	sort.SliceStable(params, func(i, j int) bool {
+		return params[i].key < params[j].key
+	})
+
+	parts := make([]string, 0, len(params))
+	for _, p := range params {
+		parts = append(parts, p.raw)
+	}
+
+	return strings.Join(parts, "&")
+}
+
+func (c *Canonicalizer) isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+	if _, ok := c.trackingExact[key]; ok {
+		return true
+	}
+	for _, prefix := range c.trackingPrefix {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// canonicalHost lowercases the host and converts internationalized domain
+// names to their ASCII (punycode) form. IP literals are returned unchanged.
+func canonicalHost(host string) (string, error) {
+	host = strings.ToLower(host)
+	if !isNonASCII(host) {
+		return host, nil
+	}
+
+	ascii, err := idna.Lookup.ToASCII(host)
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+
+	return ascii, nil
+}
+
+func isDefaultPort(scheme, port string) bool {
+	return (scheme == "http" && port == "80") || (scheme == "https" && port == "443")
+}
+
+func isNonASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package url
+
+import (
+	"testing"
+)
+
+func TestCanonicalize(t *testing.T) {
+	c := NewCanonicalizer(CanonicalizeOptions{
+		StripDefaultPort: true,
+		StripFragment:    true,
+		TrackingParams:   []string{"utm_*", "fbclid", "GCLID"},
+	})
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{
+			name: "Lowercases scheme and host",
+			url:  "HTTPS://Example.COM/Path",
+			want: "https://example.com/Path",
+		},
+		{
+			name: "Strips default HTTP port",
+			url:  "http://example.com:80/a",
+			want: "http://example.com/a",
+		},
+		{
+			name: "Strips default HTTPS port",
+			url:  "https://example.com:443/a",
+			want: "https://example.com/a",
+		},
+		{
+			name: "Keeps non-default port",
+			url:  "https://example.com:8443/a",
+			want: "https://example.com:8443/a",
+		},
+		{
+			name: "Adds root path",
+			url:  "https://example.com",
+			want: "https://example.com/",
+		},
+		{
+			name: "Strips fragment",
+			url:  "https://example.com/a#section",
+			want: "https://example.com/a",
+		},
+		{
+			name: "Converts IDN host to punycode",
+			url:  "https://Bücher.example/a",
+			want: "https://xn--bcher-kva.example/a",
+		},
+		{
+			name: "Keeps IPv6 literal",
+			url:  "http://[::1]:80/a",
+			want: "http://[::1]/a",
+		},
+		{
+			name: "Sorts query params",
+			url:  "https://example.com/a?z=1&b=2&a=3",
+			want: "https://example.com/a?a=3&b=2&z=1",
+		},
+		{
+			name: "Keeps order of repeated keys",
+			url:  "https://example.com/a?b=2&a=1&b=1",
+			want: "https://example.com/a?a=1&b=2&b=1",
+		},
+		{
+			name: "Removes tracking params",
+			url:  "https://example.com/a?utm_source=x&id=7&fbclid=abc&gclid=q&UTM_Medium=y",
+			want: "https://example.com/a?id=7",
+		},
+		{
+			name: "Removes all query params",
+			url:  "https://example.com/a?utm_source=x",
+			want: "https://example.com/a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := c.Canonicalize(tt.url)
+			if err != nil {
+				t.Fatalf("Canonicalize() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Canonicalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalizeKeepsPortAndFragmentWhenNotConfigured(t *testing.T) {
+	c := NewCanonicalizer(CanonicalizeOptions{})
+
+	got, err := c.Canonicalize("https://example.com:443/a?utm_source=x#top")
+	if err != nil {
+		t.Fatalf("Canonicalize() unexpected error: %v", err)
+	}
+
+	want := "https://example.com:443/a?utm_source=x#top"
+	if got != want {
+		t.Errorf("Canonicalize() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,19 @@
+package url
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrChangeNotFound indicates that a link has no destination change with the
+// requested ID.
+var ErrChangeNotFound = errors.New("destination change not found")
+
+// DestinationChange records one change of the destination URL of a link.
+type DestinationChange struct {
+	ID        int64     `json:"id"`
+	OldURL    string    `json:"old_url"`
+	NewURL    string    `json:"new_url"`
+	ChangedBy string    `json:"changed_by"`
+	ChangedAt time.Time `json:"changed_at"`
+}
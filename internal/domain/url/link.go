@@ -0,0 +1,233 @@
+package url
+
+import (
+	"net/http"
+	"net/netip"
+	"time"
+	"unicode/utf8"
+)
+
+// Link is a stored short link together with its metadata.
+type Link struct {
+	Alias         string `json:"alias"`
+	URL           string `json:"url"`
+	NormalizedURL string `json:"-"`
+	OwnerEmail    string `json:"owner_email"`
+	// OwnerUID is the SSO user ID of the owner; zero for links created before
+	// it was recorded that have not been claimed since.
+	OwnerUID int64 `json:"owner_uid,omitempty"`
+	// WorkspaceID is the workspace the link belongs to; zero for personal
+	// links. Workspace links are managed by the members of the workspace
+	// according to their role.
+	WorkspaceID int64 `json:"workspace_id,omitempty"`
+	// Tags label the link for filtering, sorted; FolderID is the folder the
+	// link is in, zero for none.
+	Tags     []string `json:"tags,omitempty"`
+	FolderID int64    `json:"folder_id,omitempty"`
+	// RedirectType is the HTTP status code used to redirect: 301, 302, 307 or 308.
+	RedirectType int `json:"redirect_type"`
+	// Title is an optional label chosen by the owner, shown on the preview page.
+	Title string `json:"title,omitempty"`
+	// Notes are remarks of the owner for themselves and their workspace; they
+	// are searchable but never shown to visitors.
+	Notes string `json:"notes,omitempty"`
+	// CreatedAt is unknown for links created before it was recorded.
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	// Warn makes the redirect go through an interstitial page that tells the
+	// visitor they are leaving for an external site.
+	Warn bool `json:"warn"`
+	// Passthrough is the passthrough mode; empty disables passthrough.
+	Passthrough string `json:"passthrough,omitempty"`
+	// UTM is the current UTM template, nil when the link has none.
+	UTM        *UTMTemplate `json:"utm,omitempty"`
+	UTMVersion int          `json:"utm_version"`
+	// Targeting sends matching visitors elsewhere; the first matching rule wins
+	// and visitors matching no rule go to URL. URL is also the fallback for
+	// country rules when the visitor's country cannot be determined.
+	Targeting []TargetingRule `json:"targeting,omitempty"`
+	// Variants split visitors matching no targeting rule between several
+	// destinations by weight; URL is not used for redirects while they are set.
+	Variants []Variant `json:"variants,omitempty"`
+	// StickyVariants remembers the variant of a visitor in a cookie.
+	StickyVariants bool `json:"sticky_variants"`
+	// PasswordHash is the bcrypt hash of the link password; empty when the
+	// link is not password protected.
+	PasswordHash string `json:"-"`
+	// Protected reports whether visitors need a password to follow the link.
+	Protected bool `json:"protected"`
+	// MaxClicks is the number of redirects the link allows; zero means
+	// unlimited. ClicksUsed counts the redirects made against the limit.
+	MaxClicks  int `json:"max_clicks,omitempty"`
+	ClicksUsed int `json:"clicks_used,omitempty"`
+	// NotBefore and NotAfter limit when the link redirects; nil leaves that
+	// end of the activation window open.
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	NotAfter  *time.Time `json:"not_after,omitempty"`
+	// SignedOnly rejects visits without a valid, unexpired URL signature.
+	SignedOnly bool `json:"signed_only"`
+	// DeletedAt is when the link was moved to the trash and DeletedBy the
+	// email of who did it; nil for links that are not deleted.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy string     `json:"deleted_by,omitempty"`
+	Health    LinkHealth `json:"health"`
+}
+
+// Exhausted reports whether the link has used up its click limit.
+func (l Link) Exhausted() bool {
+	return l.MaxClicks > 0 && l.ClicksUsed >= l.MaxClicks
+}
+
+// Visit describes the request a short link is resolved for.
+type Visit struct {
+	// ExtraPath is the escaped path after "/{alias}/", without a leading slash.
+	ExtraPath string
+	// RawQuery is the encoded query string of the request, without "?".
+	RawQuery string
+	// UserAgent is the User-Agent header of the request.
+	UserAgent string
+	// ClientIP is the address of the visitor; the zero value when unknown.
+	ClientIP netip.Addr
+	// Variant is the variant the visitor got before, as remembered by a cookie.
+	Variant string
+	// AccessToken is the token of an AccessGrant the visitor got by entering
+	// the password of a protected link.
+	AccessToken string
+	// Follow reports that the visitor is actually sent to the destination,
+	// which uses up a click of a link with a click limit. Previews and HEAD
+	// requests leave it unset.
+	Follow bool
+}
+
+// Redirect describes how to answer a request for a short link.
+type Redirect struct {
+	URL        string
+	StatusCode int
+	// Title and CreatedAt describe the link for the preview page.
+	Title     string
+	CreatedAt *time.Time
+	// Warn asks for an interstitial page instead of a direct redirect.
+	Warn bool
+	// UTMVersion is the UTM template version the URL was composed with.
+	UTMVersion int
+	// Varies reports that the destination depends on the visitor, so the
+	// redirect must not be cached.
+	Varies bool
+	// Variant is the name of the chosen split variant, if any.
+	Variant string
+	// StickyVariant asks to remember Variant for the visitor.
+	StickyVariant bool
+}
+
+// MaxTitleLength is the maximum length of a link title in characters.
+const MaxTitleLength = 200
+
+// ValidateTitle checks that title is not longer than MaxTitleLength.
+func ValidateTitle(title string) error {
+	if utf8.RuneCountInString(title) > MaxTitleLength {
+		return ErrTitleTooLong
+	}
+	return nil
+}
+
+// MaxNotesLength is the maximum length of the notes of a link in characters.
+const MaxNotesLength = 2000
+
+// ValidateNotes checks that notes are not longer than MaxNotesLength.
+func ValidateNotes(notes string) error {
+	if utf8.RuneCountInString(notes) > MaxNotesLength {
+		return ErrNotesTooLong
+	}
+	return nil
+}
+
+// ValidateMaxClicks checks that the click limit is not negative.
+func ValidateMaxClicks(maxClicks int) error {
+	if maxClicks < 0 {
+		return ErrInvalidMaxClicks
+	}
+	return nil
+}
+
+// DefaultRedirectType is used for links created without an explicit redirect type.
+const DefaultRedirectType = http.StatusFound
+
+// ValidateRedirectType checks that code is one of the supported redirect status codes.
+func ValidateRedirectType(code int) error {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return nil
+	}
+	return ErrInvalidRedirectType
+}
+
+// IsPermanentRedirect reports whether code tells clients the link never changes,
+// which makes the redirect cacheable.
+func IsPermanentRedirect(code int) bool {
+	return code == http.StatusMovedPermanently || code == http.StatusPermanentRedirect
+}
+
+// LinkHealth is the result of the last destination health check of a link.
+type LinkHealth struct {
+	// StatusCode is the HTTP status the destination answered with, or 0 when
+	// the request failed before a response was received.
+	StatusCode int        `json:"status_code"`
+	CheckedAt  *time.Time `json:"checked_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	Broken     bool       `json:"broken"`
+}
+
+// IsBrokenStatus reports whether a health check status code means the
+// destination is broken: no response at all or a 4xx/5xx answer.
+func IsBrokenStatus(statusCode int) bool {
+	return statusCode == 0 || statusCode >= 400
+}
+
+// Click is a single followed redirect of a link.
+type Click struct {
+	Alias      string
+	UTMVersion int
+	// Variant is the split variant the visitor was sent to, if any.
+	Variant   string
+	ClickedAt time.Time
+}
+
+// LinkStats is a link together with its click counts.
+type LinkStats struct {
+	Link
+	Clicks int `json:"clicks"`
+	// UTMVersions breaks clicks down by the UTM template version in use at the
+	// time of the click, oldest version first.
+	UTMVersions []UTMVersion `json:"utm_versions,omitempty"`
+	// VariantClicks breaks clicks down by split variant, current variants first.
+	VariantClicks []VariantStats `json:"variant_clicks,omitempty"`
+}
+
+// LinkUpdate lists the changes to apply to a link; nil fields are left as they are.
+type LinkUpdate struct {
+	// URL repoints the link to a new destination, which is recorded in the
+	// destination history.
+	URL *string
+	// UTM replaces the UTM template, creating a new template version. An empty
+	// template removes the UTM parameters.
+	UTM *UTMTemplate
+	// Targeting replaces the targeting rules; an empty list removes them.
+	Targeting *[]TargetingRule
+	// Variants replaces the split variants; an empty list removes them.
+	Variants *[]Variant
+	// StickyVariants turns sticky variants on or off.
+	StickyVariants *bool
+	// SignedOnly turns the signed only mode on or off.
+	SignedOnly *bool
+	// Tags replaces the tags; an empty list removes them.
+	Tags *[]string
+	// FolderID moves the link into the folder; zero takes it out of its folder.
+	FolderID *int64
+	// Notes replaces the notes; an empty string removes them.
+	Notes *string
+}
+
+// IsEmpty reports whether the update changes nothing.
+func (u LinkUpdate) IsEmpty() bool {
+	return u.URL == nil && u.UTM == nil && u.Targeting == nil && u.Variants == nil && u.StickyVariants == nil && u.SignedOnly == nil &&
+		u.Tags == nil && u.FolderID == nil && u.Notes == nil
+}
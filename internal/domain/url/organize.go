@@ -0,0 +1,106 @@
+package url
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrInvalidTag indicates that a tag is empty, too long or has unsupported characters
+	ErrInvalidTag = errors.New("tags must be 1 to 50 letters, digits, '-', '_' or '.'")
+	// ErrTooManyTags indicates that a link has more than MaxTags tags
+	ErrTooManyTags = errors.New("a link can have at most 20 tags")
+	// ErrInvalidFolderName indicates that the folder name is empty or too long
+	ErrInvalidFolderName = errors.New("folder name must be 1 to 100 characters")
+	// ErrFolderNotFound indicates that the folder does not exist or belongs to someone else
+	ErrFolderNotFound = errors.New("folder not found")
+)
+
+const (
+	// MaxTags is the maximum number of tags of a link.
+	MaxTags = 20
+	// MaxTagLength is the maximum length of a tag in characters.
+	MaxTagLength = 50
+	// MaxFolderNameLength is the maximum length of a folder name in characters.
+	MaxFolderNameLength = 100
+)
+
+// Folder groups links of one owner. A link is in at most one folder, which
+// has to belong to the owner of the link.
+type Folder struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	OwnerEmail  string    `json:"owner_email"`
+	OwnerUID    int64     `json:"owner_uid,omitempty"`
+	WorkspaceID int64     `json:"workspace_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Owner returns the owner of the folder.
+func (f Folder) Owner() Owner {
+	return Owner{UID: f.OwnerUID, Email: f.OwnerEmail, WorkspaceID: f.WorkspaceID}
+}
+
+// ListFilter narrows down a link listing. Zero fields do not filter.
+type ListFilter struct {
+	// WorkspaceID lists the links of the workspace instead of personal links.
+	WorkspaceID int64
+	// Tags keeps the links that have all of the tags.
+	Tags []string
+	// FolderID keeps the links in the folder.
+	FolderID int64
+}
+
+// TagStats aggregates the live links with a tag and their clicks.
+type TagStats struct {
+	Tag    string `json:"tag"`
+	Links  int    `json:"links"`
+	Clicks int    `json:"clicks"`
+}
+
+// NormalizeTags trims and lowercases the tags, drops duplicates and sorts
+// them. Tags are made of letters, digits, '-', '_' and '.'.
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !validTag(tag) {
+			return nil, ErrInvalidTag
+		}
+		normalized = append(normalized, tag)
+	}
+
+	slices.Sort(normalized)
+	normalized = slices.Compact(normalized)
+
+	if len(normalized) > MaxTags {
+		return nil, ErrTooManyTags
+	}
+
+	return normalized, nil
+}
+
+func validTag(tag string) bool {
+	if tag == "" || utf8.RuneCountInString(tag) > MaxTagLength {
+		return false
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateFolderName checks that the folder name is not blank and not longer
+// than MaxFolderNameLength.
+func ValidateFolderName(name string) error {
+	if strings.TrimSpace(name) == "" || utf8.RuneCountInString(name) > MaxFolderNameLength {
+		return ErrInvalidFolderName
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package url
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tooMany := make([]string, MaxTags+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag%d", i)
+	}
+
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr error
+	}{
+		{name: "Empty", tags: nil, want: []string{}},
+		{name: "Sorted and deduplicated", tags: []string{"Promo", "q3", " promo "}, want: []string{"promo", "q3"}},
+		{name: "Punctuation", tags: []string{"spring-sale_2026.v2"}, want: []string{"spring-sale_2026.v2"}},
+		{name: "Letters", tags: []string{"Ünïcode"}, want: []string{"ünïcode"}},
+		{name: "Max length", tags: []string{strings.Repeat("a", MaxTagLength)}, want: []string{strings.Repeat("a", MaxTagLength)}},
+		{name: "Blank", tags: []string{"  "}, wantErr: ErrInvalidTag},
+		{name: "Space inside", tags: []string{"spring sale"}, wantErr: ErrInvalidTag},
+		{name: "Too long", tags: []string{strings.Repeat("a", MaxTagLength+1)}, wantErr: ErrInvalidTag},
+		{name: "Too many", tags: tooMany, wantErr: ErrTooManyTags},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NormalizeTags(tt.tags)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateFolderName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "Valid", input: "Campaigns"},
+		{name: "Max length", input: strings.Repeat("я", MaxFolderNameLength)},
+		{name: "Blank", input: " ", wantErr: true},
+		{name: "Too long", input: strings.Repeat("a", MaxFolderNameLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFolderName(tt.input)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidFolderName)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
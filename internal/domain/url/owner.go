@@ -0,0 +1,40 @@
+package url
+
+// Owner identifies the user or workspace a link belongs to. Users are matched
+// by their SSO user ID, which survives email changes; links without a
+// recorded ID fall back to the email. A link with a WorkspaceID belongs to
+// the workspace, and UID and Email only name who created it.
+type Owner struct {
+	UID         int64
+	Email       string
+	WorkspaceID int64
+}
+
+// Owner returns the owner of the link.
+func (l Link) Owner() Owner {
+	return Owner{UID: l.OwnerUID, Email: l.OwnerEmail, WorkspaceID: l.WorkspaceID}
+}
+
+// OwnedBy reports whether the user with the given email and ID owns the link.
+// Nobody owns a workspace link by themselves; its members manage it by role.
+func (o Owner) OwnedBy(email string, uid int64) bool {
+	if o.WorkspaceID != 0 {
+		return false
+	}
+	if o.UID != 0 {
+		return o.UID == uid
+	}
+	return o.Email != "" && o.Email == email
+}
+
+// SameAs reports whether o and other are the same owner: the same workspace,
+// or the same user matched as in OwnedBy.
+func (o Owner) SameAs(other Owner) bool {
+	if o.WorkspaceID != 0 || other.WorkspaceID != 0 {
+		return o.WorkspaceID == other.WorkspaceID
+	}
+	if o.UID != 0 && other.UID != 0 {
+		return o.UID == other.UID
+	}
+	return o.Email != "" && o.Email == other.Email
+}
@@ -0,0 +1,52 @@
+package url
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOwnedBy(t *testing.T) {
+	tests := []struct {
+		name  string
+		owner Owner
+		email string
+		uid   int64
+		want  bool
+	}{
+		{name: "Same user", owner: Owner{UID: 7, Email: "owner@example.com"}, email: "owner@example.com", uid: 7, want: true},
+		{name: "Owner changed email", owner: Owner{UID: 7, Email: "old@example.com"}, email: "new@example.com", uid: 7, want: true},
+		{name: "Email taken over by another user", owner: Owner{UID: 7, Email: "owner@example.com"}, email: "owner@example.com", uid: 8},
+		{name: "No uid, same email", owner: Owner{Email: "owner@example.com"}, email: "owner@example.com", uid: 8, want: true},
+		{name: "No uid, other email", owner: Owner{Email: "owner@example.com"}, email: "other@example.com", uid: 8},
+		{name: "No owner", owner: Owner{}, email: "", uid: 0},
+		{name: "Workspace link creator", owner: Owner{UID: 7, Email: "owner@example.com", WorkspaceID: 3}, email: "owner@example.com", uid: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.owner.OwnedBy(tt.email, tt.uid))
+		})
+	}
+}
+
+func TestSameAs(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Owner
+		want bool
+	}{
+		{name: "Same user by uid", a: Owner{UID: 7, Email: "old@example.com"}, b: Owner{UID: 7, Email: "new@example.com"}, want: true},
+		{name: "Other user", a: Owner{UID: 7, Email: "owner@example.com"}, b: Owner{UID: 8, Email: "owner@example.com"}},
+		{name: "Same email without uid", a: Owner{Email: "owner@example.com"}, b: Owner{UID: 7, Email: "owner@example.com"}, want: true},
+		{name: "Same workspace", a: Owner{WorkspaceID: 3, Email: "a@example.com"}, b: Owner{WorkspaceID: 3, Email: "b@example.com"}, want: true},
+		{name: "Workspace and user", a: Owner{WorkspaceID: 3, Email: "owner@example.com"}, b: Owner{Email: "owner@example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.a.SameAs(tt.b))
+			require.Equal(t, tt.want, tt.b.SameAs(tt.a))
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package url
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+var (
+	// ErrInvalidPassthrough indicates that the passthrough mode is not supported
+	ErrInvalidPassthrough = errors.New("passthrough must be one of preserve, override, append")
+	// ErrInvalidPassthroughRequest indicates that the forwarded path or query cannot be applied
+	ErrInvalidPassthroughRequest = errors.New("invalid passthrough path or query")
+)
+
+// Passthrough modes. With passthrough enabled, extra path segments after the
+// alias are appended to the destination path and the query string of the
+// request is merged into the destination query. The modes differ only in how
+// query keys present in both are merged; keys are compared after decoding and
+// are case-sensitive.
+const (
+	// PassthroughOff ignores the request query and rejects extra path segments.
+	PassthroughOff = ""
+	// PassthroughPreserve keeps the destination values of conflicting keys and
+	// drops the request ones. Other request parameters are appended in order.
+	PassthroughPreserve = "preserve"
+	// PassthroughOverride removes destination parameters whose key appears in
+	// the request, then appends all request parameters in order.
+	PassthroughOverride = "override"
+	// PassthroughAppend keeps every destination parameter and appends every
+	// request parameter, so conflicting keys end up with several values.
+	PassthroughAppend = "append"
+)
+
+// ValidatePassthrough checks that mode is one of the supported passthrough modes.
+func ValidatePassthrough(mode string) error {
+	switch mode {
+	case PassthroughOff, PassthroughPreserve, PassthroughOverride, PassthroughAppend:
+		return nil
+	}
+	return ErrInvalidPassthrough
+}
+
+// ApplyPassthrough forwards the extra path and query of a visit onto destination
+// according to mode. Every segment of extraPath is re-encoded so that it stays a
+// single segment; "." and ".." segments are rejected. The result always keeps
+// the scheme and host of destination and passes ValidateURL.
+func ApplyPassthrough(destination, mode string, visit Visit) (string, error) {
+	if mode == PassthroughOff {
+		if visit.ExtraPath != "" {
+			return "", ErrInvalidPassthroughRequest
+		}
+		return destination, nil
+	}
+	if err := ValidatePassthrough(mode); err != nil {
+		return "", err
+	}
+
+	parsedURL, err := url.Parse(destination)
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+	scheme, host := parsedURL.Scheme, parsedURL.Host
+
+	if visit.ExtraPath != "" {
+		extraPath, err := escapeSegments(visit.ExtraPath)
+		if err != nil {
+			return "", err
+		}
+
+		rawPath := strings.TrimSuffix(parsedURL.EscapedPath(), "/") + "/" + extraPath
+		path, err := url.PathUnescape(rawPath)
+		if err != nil {
+			return "", ErrInvalidPassthroughRequest
+		}
+		parsedURL.Path = path
+		parsedURL.RawPath = rawPath
+	}
+
+	if visit.RawQuery != "" {
+		query, err := mergeQuery(parsedURL.RawQuery, visit.RawQuery, mode)
+		if err != nil {
+			return "", err
+		}
+		parsedURL.RawQuery = query
+		parsedURL.ForceQuery = false
+	}
+
+	result := parsedURL.String()
+
+	// Guard against anything in the forwarded parts changing where the
+	// redirect points to.
+	check, err := url.Parse(result)
+	if err != nil || check.Scheme != scheme || check.Host != host {
+		return "", ErrInvalidPassthroughRequest
+	}
+	if err = ValidateURL(result); err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+// escapeSegments decodes each segment of an escaped path and encodes it again
+// with url.PathEscape, so an encoded slash stays part of its segment.
+func escapeSegments(rawPath string) (string, error) {
+	segments := strings.Split(rawPath, "/")
+	for i, segment := range segments {
+		decoded, err := url.PathUnescape(segment)
+		if err != nil {
+			return "", ErrInvalidPassthroughRequest
+		}
+		if decoded == "." || decoded == ".." {
+			return "", ErrInvalidPassthroughRequest
+		}
+		segments[i] = url.PathEscape(decoded)
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+type queryParam struct {
+	key, raw string
+}
+
+// mergeQuery merges the request query into the destination query. Destination
+// parameters are kept as encoded; request parameters are re-encoded.
+func mergeQuery(destQuery, reqQuery, mode string) (string, error) {
+	var dest []queryParam
+	for part := range strings.SplitSeq(destQuery, "&") {
+		if part == "" {
+			continue
+		}
+		key, _, _ := strings.Cut(part, "=")
+		if decoded, err := url.QueryUnescape(key); err == nil {
+			key = decoded
+		}
+		dest = append(dest, queryParam{key: key, raw: part})
+	}
+
+	var req []queryParam
+	for part := range strings.SplitSeq(reqQuery, "&") {
+		if part == "" {
+			continue
+		}
+		rawKey, rawValue, hasValue := strings.Cut(part, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			return "", ErrInvalidPassthroughRequest
+		}
+		value, err := url.QueryUnescape(rawValue)
+		if err != nil {
+			return "", ErrInvalidPassthroughRequest
+		}
+		raw := url.QueryEscape(key)
+		if hasValue {
+			raw += "=" + url.QueryEscape(value)
+		}
+		req = append(req, queryParam{key: key, raw: raw})
+	}
+
+	keys := func(params []queryParam) map[string]struct{} {
+		set := make(map[string]struct{}, len(params))
+		for _, p := range params {
+			set[p.key] = struct{}{}
+		}
+		return set
+	}
+
+	var merged []queryParam
+	switch mode {
+	case PassthroughPreserve:
+		destKeys := keys(dest)
+		merged = dest
+		for _, p := range req {
+			if _, ok := destKeys[p.key]; !ok {
+				merged = append(merged, p)
+			}
+		}
+	case PassthroughOverride:
+		reqKeys := keys(req)
+		for _, p := range dest {
+			if _, ok := reqKeys[p.key]; !ok {
+				merged = append(merged, p)
+			}
+		}
+		merged = append(merged, req...)
+	default:
+		merged = append(dest, req...)
+	}
+
+	parts := make([]string, 0, len(merged))
+	for _, p := range merged {
+		parts = append(parts, p.raw)
+	}
+
+	return strings.Join(parts, "&"), nil
+}
@@ -0,0 +1,211 @@
+package url
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyPassthrough(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		mode        string
+		visit       Visit
+		want        string
+		wantErr     error
+	}{
+		{
+			name:        "Off ignores the query",
+			destination: "https://example.com/landing?a=1",
+			mode:        PassthroughOff,
+			visit:       Visit{RawQuery: "b=2"},
+			want:        "https://example.com/landing?a=1",
+		},
+		{
+			name:        "Off rejects extra path",
+			destination: "https://example.com/landing",
+			mode:        PassthroughOff,
+			visit:       Visit{ExtraPath: "extra"},
+			wantErr:     ErrInvalidPassthroughRequest,
+		},
+		{
+			name:        "Appends path to destination without path",
+			destination: "https://example.com",
+			mode:        PassthroughPreserve,
+			visit:       Visit{ExtraPath: "a/b"},
+			want:        "https://example.com/a/b",
+		},
+		{
+			name:        "Joins paths with a single slash",
+			destination: "https://example.com/docs/",
+			mode:        PassthroughPreserve,
+			visit:       Visit{ExtraPath: "guide/intro"},
+			want:        "https://example.com/docs/guide/intro",
+		},
+		{
+			name:        "Keeps destination fragment",
+			destination: "https://example.com/docs#top",
+			mode:        PassthroughPreserve,
+			visit:       Visit{ExtraPath: "a", RawQuery: "x=1"},
+			want:        "https://example.com/docs/a?x=1#top",
+		},
+		{
+			name:        "Preserve keeps destination value on conflict",
+			destination: "https://example.com/?utm_source=site&id=7",
+			mode:        PassthroughPreserve,
+			visit:       Visit{RawQuery: "utm_source=x&utm_medium=email"},
+			want:        "https://example.com/?utm_source=site&id=7&utm_medium=email",
+		},
+		{
+			name:        "Override replaces every destination value on conflict",
+			destination: "https://example.com/?tag=a&id=7&tag=b",
+			mode:        PassthroughOverride,
+			visit:       Visit{RawQuery: "tag=c&new=1"},
+			want:        "https://example.com/?id=7&tag=c&new=1",
+		},
+		{
+			name:        "Append keeps both values",
+			destination: "https://example.com/?tag=a",
+			mode:        PassthroughAppend,
+			visit:       Visit{RawQuery: "tag=b"},
+			want:        "https://example.com/?tag=a&tag=b",
+		},
+		{
+			name:        "Conflicts are detected after decoding keys",
+			destination: "https://example.com/?a%20b=1",
+			mode:        PassthroughOverride,
+			visit:       Visit{RawQuery: "a+b=2"},
+			want:        "https://example.com/?a+b=2",
+		},
+		{
+			name:        "Keys are case-sensitive",
+			destination: "https://example.com/?ID=1",
+			mode:        PassthroughOverride,
+			visit:       Visit{RawQuery: "id=2"},
+			want:        "https://example.com/?ID=1&id=2",
+		},
+		{
+			name:        "Request query is re-encoded",
+			destination: "https://example.com/",
+			mode:        PassthroughPreserve,
+			visit:       Visit{RawQuery: "q=a%26b%3Dc&name=J%C3%BCrgen&flag"},
+			want:        "https://example.com/?q=a%26b%3Dc&name=J%C3%BCrgen&flag",
+		},
+		{
+			name:        "Destination query encoding is kept",
+			destination: "https://example.com/?sig=abc%2Fdef%3D",
+			mode:        PassthroughPreserve,
+			visit:       Visit{RawQuery: "x=1"},
+			want:        "https://example.com/?sig=abc%2Fdef%3D&x=1",
+		},
+		{
+			name:        "Encoded slash stays inside its segment",
+			destination: "https://example.com/files",
+			mode:        PassthroughPreserve,
+			visit:       Visit{ExtraPath: "a%2Fb/c"},
+			want:        "https://example.com/files/a%2Fb/c",
+		},
+		{
+			name:        "Spaces and unicode in path are escaped",
+			destination: "https://example.com/",
+			mode:        PassthroughPreserve,
+			visit:       Visit{ExtraPath: "über%20uns"},
+			want:        "https://example.com/%C3%BCber%20uns",
+		},
+		{
+			name:        "Question mark in path does not start a query",
+			destination: "https://example.com/",
+			mode:        PassthroughPreserve,
+			visit:       Visit{ExtraPath: "a%3Fb=1"},
+			want:        "https://example.com/a%3Fb=1",
+		},
+		{
+			name:        "Dot-dot segment is rejected",
+			destination: "https://example.com/campaign/",
+			mode:        PassthroughPreserve,
+			visit:       Visit{ExtraPath: "../admin"},
+			wantErr:     ErrInvalidPassthroughRequest,
+		},
+		{
+			name:        "Encoded dot-dot segment is rejected",
+			destination: "https://example.com/campaign/",
+			mode:        PassthroughPreserve,
+			visit:       Visit{ExtraPath: "%2e%2E/admin"},
+			wantErr:     ErrInvalidPassthroughRequest,
+		},
+		{
+			name:        "Invalid path escape is rejected",
+			destination: "https://example.com/",
+			mode:        PassthroughPreserve,
+			visit:       Visit{ExtraPath: "a%zz"},
+			wantErr:     ErrInvalidPassthroughRequest,
+		},
+		{
+			name:        "Invalid query escape is rejected",
+			destination: "https://example.com/",
+			mode:        PassthroughPreserve,
+			visit:       Visit{RawQuery: "a=%zz"},
+			wantErr:     ErrInvalidPassthroughRequest,
+		},
+		{
+			name:        "Protocol-relative path cannot change the host",
+			destination: "https://example.com",
+			mode:        PassthroughPreserve,
+			visit:       Visit{ExtraPath: "/evil.example/phish"},
+			want:        "https://example.com//evil.example/phish",
+		},
+		{
+			name:        "Userinfo-like path cannot change the host",
+			destination: "https://example.com",
+			mode:        PassthroughPreserve,
+			visit:       Visit{ExtraPath: "@evil.example"},
+			want:        "https://example.com/@evil.example",
+		},
+		{
+			name:        "Backslash path cannot change the host",
+			destination: "https://example.com",
+			mode:        PassthroughPreserve,
+			visit:       Visit{ExtraPath: `%5C%5Cevil.example`},
+			want:        "https://example.com/%5C%5Cevil.example",
+		},
+		{
+			name:        "Redirect target in query stays a parameter",
+			destination: "https://example.com/login",
+			mode:        PassthroughPreserve,
+			visit:       Visit{RawQuery: "next=javascript:alert(1)"},
+			want:        "https://example.com/login?next=javascript%3Aalert%281%29",
+		},
+		{
+			name:        "Unknown mode",
+			destination: "https://example.com/",
+			mode:        "merge",
+			visit:       Visit{RawQuery: "a=1"},
+			wantErr:     ErrInvalidPassthrough,
+		},
+		{
+			name:        "Stored javascript URL is still rejected",
+			destination: "javascript:alert(1)",
+			mode:        PassthroughPreserve,
+			visit:       Visit{RawQuery: "a=1"},
+			wantErr:     ErrInvalidScheme,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyPassthrough(tt.destination, tt.mode, tt.visit)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ApplyPassthrough() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ApplyPassthrough() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ApplyPassthrough() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
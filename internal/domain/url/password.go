@@ -0,0 +1,57 @@
+package url
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrInvalidPassword indicates that a link password is too short or too long
+	ErrInvalidPassword = errors.New("password must be 6 to 72 bytes")
+	// ErrPasswordRequired indicates that the link is password protected and the visitor has not unlocked it
+	ErrPasswordRequired = errors.New("password required")
+	// ErrWrongPassword indicates that the password given to unlock a link is wrong
+	ErrWrongPassword = errors.New("wrong password")
+	// ErrTooManyAttempts indicates that unlocking a link failed too often and is throttled
+	ErrTooManyAttempts = errors.New("too many password attempts")
+)
+
+const (
+	// MinPasswordLength is the minimum length of a link password in bytes.
+	MinPasswordLength = 6
+	// MaxPasswordLength is the maximum length of a link password in bytes,
+	// the most bcrypt takes into account.
+	MaxPasswordLength = 72
+)
+
+// ValidatePassword checks the length of a link password. An empty password
+// leaves the link unprotected.
+func ValidatePassword(password string) error {
+	if password == "" {
+		return nil
+	}
+	if len(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return ErrInvalidPassword
+	}
+	return nil
+}
+
+// ThrottledError reports that unlocking a link is throttled and when to try again.
+type ThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("%s: retry after %s", ErrTooManyAttempts, e.RetryAfter.Round(time.Second))
+}
+
+func (e *ThrottledError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
+
+// AccessGrant lets a visitor follow a password protected link until it expires.
+type AccessGrant struct {
+	Token     string
+	ExpiresAt time.Time
+}
@@ -0,0 +1,47 @@
+package url
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		wantErr  error
+	}{
+		{name: "No password", password: ""},
+		{name: "Shortest password", password: "secret"},
+		{name: "Longest password", password: strings.Repeat("p", MaxPasswordLength)},
+		{name: "Too short", password: "abc", wantErr: ErrInvalidPassword},
+		{name: "Too long", password: strings.Repeat("p", MaxPasswordLength+1), wantErr: ErrInvalidPassword},
+		{name: "Length in bytes", password: strings.Repeat("ü", 37), wantErr: ErrInvalidPassword},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePassword(tt.password)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestThrottledError(t *testing.T) {
+	var err error = &ThrottledError{RetryAfter: 90 * time.Second}
+
+	require.ErrorIs(t, err, ErrTooManyAttempts)
+	require.Equal(t, "too many password attempts: retry after 1m30s", err.Error())
+
+	var throttled *ThrottledError
+	require.True(t, errors.As(err, &throttled))
+	require.Equal(t, 90*time.Second, throttled.RetryAfter)
+}
@@ -0,0 +1,244 @@
+package url
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrPolicyViolation indicates that a destination URL was rejected by the destination policy.
+var ErrPolicyViolation = errors.New("destination rejected by policy")
+
+// Policy rule names reported in PolicyViolationError.
+const (
+	RuleSelfReference = "self_reference"
+	RulePrivateIP     = "private_ip"
+	RuleDeniedDomain  = "denied_domain"
+	RuleNotAllowed    = "not_in_allowlist"
+)
+
+// PolicyViolationError describes which policy rule rejected a destination.
+type PolicyViolationError struct {
+	Rule string
+	Host string
+}
+
+func (e *PolicyViolationError) Error() string {
+	return fmt.Sprintf("%s: rule %s matched host %q", ErrPolicyViolation, e.Rule, e.Host)
+}
+
+func (e *PolicyViolationError) Is(target error) bool {
+	return target == ErrPolicyViolation
+}
+
+// PolicyOptions configures a destination Policy. Domain lists match the
+// domain itself and all of its subdomains.
+type PolicyOptions struct {
+	// ShortDomains are the domains the shortener itself is served on.
+	// Destinations on them would redirect back to us and are rejected.
+	ShortDomains []string
+	// BlockPrivateIPs rejects private (RFC 1918, unique local), loopback,
+	// link-local and unspecified IP literals as well as localhost.
+	BlockPrivateIPs bool
+	// DeniedDomains are always rejected.
+	DeniedDomains []string
+	// AllowedDomains, when not empty, is the only set of domains accepted.
+	AllowedDomains []string
+}
+
+// Policy decides whether a destination URL may be shortened.
+type Policy struct {
+	opts PolicyOptions
+}
+
+// NewPolicy creates a Policy with the given options. Domains are normalized
+// the same way destination hosts are.
+func NewPolicy(opts PolicyOptions) *Policy {
+	return &Policy{
+		opts: PolicyOptions{
+			ShortDomains:    normalizeDomains(opts.ShortDomains),
+			BlockPrivateIPs: opts.BlockPrivateIPs,
+			DeniedDomains:   normalizeDomains(opts.DeniedDomains),
+			AllowedDomains:  normalizeDomains(opts.AllowedDomains),
+		},
+	}
+}
+
+// Validate checks rawURL with ValidateURL and then applies the policy rules.
+// A rejection is reported as a *PolicyViolationError naming the matched rule.
+func (p *Policy) Validate(rawURL string) error {
+	if err := ValidateURL(rawURL); err != nil {
+		return err
+	}
+
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return ErrInvalidURL
+	}
+
+	host, err := canonicalHost(parsedURL.Hostname())
+	if err != nil {
+		return err
+	}
+	host = strings.TrimSuffix(host, ".")
+
+	if matchesDomain(host, p.opts.ShortDomains) {
+		return &PolicyViolationError{Rule: RuleSelfReference, Host: host}
+	}
+
+	if p.opts.BlockPrivateIPs && isInternalHost(host) {
+		return &PolicyViolationError{Rule: RulePrivateIP, Host: host}
+	}
+
+	if matchesDomain(host, p.opts.DeniedDomains) {
+		return &PolicyViolationError{Rule: RuleDeniedDomain, Host: host}
+	}
+
+	if len(p.opts.AllowedDomains) > 0 && !matchesDomain(host, p.opts.AllowedDomains) {
+		return &PolicyViolationError{Rule: RuleNotAllowed, Host: host}
+	}
+
+	return nil
+}
+
+// matchesDomain reports whether host equals one of domains or is a subdomain of it.
+func matchesDomain(host string, domains []string) bool {
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// DomainList is a set of domains that matches URLs on those domains or any of
+// their subdomains.
+type DomainList []string
+
+// NewDomainList creates a DomainList, normalizing domains the same way
+// destination hosts are.
+func NewDomainList(domains []string) DomainList {
+	return normalizeDomains(domains)
+}
+
+// MatchURL reports whether the host of rawURL is on the list.
+func (l DomainList) MatchURL(rawURL string) bool {
+	if len(l) == 0 {
+		return false
+	}
+
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	host, err := canonicalHost(parsedURL.Hostname())
+	if err != nil {
+		return false
+	}
+
+	return matchesDomain(strings.TrimSuffix(host, "."), l)
+}
+
+func normalizeDomains(domains []string) []string {
+	res := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.TrimSuffix(strings.TrimSpace(domain), ".")
+		if domain == "" {
+			continue
+		}
+		if ascii, err := canonicalHost(domain); err == nil {
+			domain = ascii
+		}
+		res = append(res, domain)
+	}
+	return res
+}
+
+// isInternalHost reports whether host is localhost or an IP literal that
+// points into a private, loopback, link-local or unspecified range.
+func isInternalHost(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		ip = parseLooseIPv4(host)
+	}
+	if ip == nil {
+		return false
+	}
+
+	return ip.IsPrivate() ||
+		ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified()
+}
+
+// parseLooseIPv4 parses the IPv4 notations browsers accept besides the dotted
+// decimal form, such as "2130706433", "0x7f.1" or "0177.0.0.1", so that they
+// cannot be used to sneak past the private IP rule.
+func parseLooseIPv4(host string) net.IP {
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return nil
+	}
+
+	nums := make([]uint64, len(parts))
+	for i, part := range parts {
+		n, ok := parseIPv4Part(part)
+		if !ok {
+			return nil
+		}
+		nums[i] = n
+	}
+
+	// All parts but the last one are single bytes; the last one fills the
+	// remaining bytes of the address.
+	var addr uint64
+	for i, n := range nums[:len(nums)-1] {
+		if n > 0xff {
+			return nil
+		}
+		addr |= n << (8 * (3 - i))
+	}
+
+	last := nums[len(nums)-1]
+	if last >= 1<<(8*(5-len(nums))) {
+		return nil
+	}
+	addr |= last
+
+	return net.IPv4(byte(addr>>24), byte(addr>>16), byte(addr>>8), byte(addr))
+}
+
+func parseIPv4Part(part string) (uint64, bool) {
+	if part == "" {
+		return 0, false
+	}
+
+	base := 10
+	switch {
+	case strings.HasPrefix(part, "0x") || strings.HasPrefix(part, "0X"):
+		base = 16
+		part = part[2:]
+		if part == "" {
+			return 0, true
+		}
+	case len(part) > 1 && part[0] == '0':
+		base = 8
+		part = part[1:]
+	}
+
+	n, err := strconv.ParseUint(part, base, 32)
+	if err != nil {
+		return 0, false
+	}
+
+	return n, true
+}
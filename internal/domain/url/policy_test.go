@@ -0,0 +1,124 @@
+package url
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	policy := NewPolicy(PolicyOptions{
+		ShortDomains:    []string{"sho.rt"},
+		BlockPrivateIPs: true,
+		DeniedDomains:   []string{"evil.example", "Bad.Example."},
+	})
+
+	tests := []struct {
+		name     string
+		url      string
+		wantErr  error
+		wantRule string
+	}{
+		{name: "Public domain", url: "https://example.com/a"},
+		{name: "Public IP", url: "http://93.184.216.34/"},
+		{name: "Invalid scheme", url: "ftp://example.com", wantErr: ErrInvalidScheme},
+		{name: "Own short domain", url: "https://sho.rt/abc", wantRule: RuleSelfReference},
+		{name: "Own short domain uppercase", url: "https://SHO.RT./abc", wantRule: RuleSelfReference},
+		{name: "Subdomain of short domain", url: "https://www.sho.rt/abc", wantRule: RuleSelfReference},
+		{name: "Similar but different domain", url: "https://notsho.rt/abc"},
+		{name: "RFC1918 10/8", url: "http://10.1.2.3/", wantRule: RulePrivateIP},
+		{name: "RFC1918 172.16/12", url: "http://172.20.0.1/", wantRule: RulePrivateIP},
+		{name: "RFC1918 192.168/16", url: "http://192.168.0.1:8080/", wantRule: RulePrivateIP},
+		{name: "Loopback", url: "http://127.0.0.1/", wantRule: RulePrivateIP},
+		{name: "Loopback IPv6", url: "http://[::1]/", wantRule: RulePrivateIP},
+		{name: "IPv4-mapped loopback", url: "http://[::ffff:127.0.0.1]/", wantRule: RulePrivateIP},
+		{name: "Link-local", url: "http://169.254.169.254/latest/meta-data", wantRule: RulePrivateIP},
+		{name: "Link-local IPv6", url: "http://[fe80::1]/", wantRule: RulePrivateIP},
+		{name: "Unspecified", url: "http://0.0.0.0/", wantRule: RulePrivateIP},
+		{name: "Localhost", url: "http://localhost:8080/", wantRule: RulePrivateIP},
+		{name: "Decimal loopback", url: "http://2130706433/", wantRule: RulePrivateIP},
+		{name: "Hex loopback", url: "http://0x7f.1/", wantRule: RulePrivateIP},
+		{name: "Octal loopback", url: "http://0177.0.0.1/", wantRule: RulePrivateIP},
+		{name: "Denied domain", url: "https://evil.example/", wantRule: RuleDeniedDomain},
+		{name: "Subdomain of denied domain", url: "https://a.b.evil.example/", wantRule: RuleDeniedDomain},
+		{name: "Denied domain normalized", url: "https://bad.example/", wantRule: RuleDeniedDomain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := policy.Validate(tt.url)
+
+			switch {
+			case tt.wantRule != "":
+				var violation *PolicyViolationError
+				if !errors.As(err, &violation) {
+					t.Fatalf("Validate() error = %v, want policy violation %s", err, tt.wantRule)
+				}
+				if violation.Rule != tt.wantRule {
+					t.Errorf("Validate() rule = %s, want %s", violation.Rule, tt.wantRule)
+				}
+				if !errors.Is(err, ErrPolicyViolation) {
+					t.Errorf("Validate() error does not match ErrPolicyViolation")
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			default:
+				if err != nil {
+					t.Errorf("Validate() unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestPolicyAllowlist(t *testing.T) {
+	policy := NewPolicy(PolicyOptions{
+		AllowedDomains: []string{"example.com"},
+	})
+
+	if err := policy.Validate("https://docs.example.com/page"); err != nil {
+		t.Errorf("Validate() unexpected error for allowed subdomain: %v", err)
+	}
+
+	var violation *PolicyViolationError
+	err := policy.Validate("https://other.org/")
+	if !errors.As(err, &violation) || violation.Rule != RuleNotAllowed {
+		t.Errorf("Validate() error = %v, want rule %s", err, RuleNotAllowed)
+	}
+
+	// Private IPs are only rejected when configured.
+	policy = NewPolicy(PolicyOptions{})
+	if err = policy.Validate("http://10.0.0.1/"); err != nil {
+		t.Errorf("Validate() unexpected error with private IP rule disabled: %v", err)
+	}
+}
+
+func TestDomainListMatchURL(t *testing.T) {
+	list := NewDomainList([]string{"Example.COM.", "bücher.de", " "})
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://example.com/", true},
+		{"https://shop.example.com/cart", true},
+		{"https://EXAMPLE.com:8443/", true},
+		{"https://notexample.com/", false},
+		{"https://xn--bcher-kva.de/", true},
+		{"https://www.bücher.de/", true},
+		{"https://other.org/", false},
+		{"::not a url", false},
+	}
+
+	for _, tt := range tests {
+		if got := list.MatchURL(tt.url); got != tt.want {
+			t.Errorf("MatchURL(%q) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+
+	if NewDomainList(nil).MatchURL("https://example.com/") {
+		t.Errorf("empty list matched")
+	}
+}
@@ -0,0 +1,81 @@
+package url
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrInvalidSearchQuery indicates that a search query has no words or too many
+	ErrInvalidSearchQuery = errors.New("search query must have 1 to 10 words of letters or digits")
+	// ErrInvalidPage indicates that the limit or offset of a search is out of range
+	ErrInvalidPage = errors.New("limit must be 1 to 100 and offset must not be negative")
+)
+
+const (
+	// DefaultSearchLimit is the number of links returned when a search sets no limit.
+	DefaultSearchLimit = 20
+	// MaxSearchLimit is the largest number of links a search may ask for.
+	MaxSearchLimit = 100
+	// MaxSearchTerms is the largest number of words in a search query.
+	MaxSearchTerms = 10
+	// MaxSearchQueryLength is the maximum length of a search query in characters.
+	MaxSearchQueryLength = 200
+)
+
+// Search is a full-text search over the alias, destination URL, title, tags
+// and notes of the links of one owner.
+type Search struct {
+	// Query holds the words to look for; every word has to match, as the
+	// prefix of a word of the link.
+	Query string
+	// WorkspaceID searches the links of the workspace instead of personal links.
+	WorkspaceID int64
+	// Limit caps the number of links; zero means DefaultSearchLimit.
+	Limit int
+	// Offset skips that many of the best matching links.
+	Offset int
+}
+
+// SearchPage is one page of search results, best match first.
+type SearchPage struct {
+	URLs []Link `json:"urls"`
+	// NextOffset continues the search with the next page; zero when there is
+	// none.
+	NextOffset int `json:"next_offset,omitempty"`
+}
+
+// Validate checks the search and applies the default limit.
+func (s *Search) Validate() error {
+	if s.Limit == 0 {
+		s.Limit = DefaultSearchLimit
+	}
+	if s.Limit < 0 || s.Limit > MaxSearchLimit || s.Offset < 0 {
+		return ErrInvalidPage
+	}
+
+	terms := s.Terms()
+	if len(terms) == 0 || len(terms) > MaxSearchTerms || utf8.RuneCountInString(s.Query) > MaxSearchQueryLength {
+		return ErrInvalidSearchQuery
+	}
+
+	return nil
+}
+
+// Terms splits the query into lowercase words of letters and digits, without
+// duplicates. Everything else separates words.
+func (s *Search) Terms() []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, term := range strings.FieldsFunc(strings.ToLower(s.Query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
@@ -0,0 +1,41 @@
+package url
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		search    Search
+		wantLimit int
+		wantTerms []string
+		wantErr   error
+	}{
+		{name: "Default limit", search: Search{Query: "launch"}, wantLimit: DefaultSearchLimit, wantTerms: []string{"launch"}},
+		{name: "Words split on punctuation", search: Search{Query: "Spring-Sale example.com", Limit: 5}, wantLimit: 5, wantTerms: []string{"spring", "sale", "example", "com"}},
+		{name: "Duplicates dropped", search: Search{Query: "promo PROMO q3"}, wantLimit: DefaultSearchLimit, wantTerms: []string{"promo", "q3"}},
+		{name: "Letters", search: Search{Query: "Ünïcode"}, wantLimit: DefaultSearchLimit, wantTerms: []string{"ünïcode"}},
+		{name: "No words", search: Search{Query: " -*- "}, wantErr: ErrInvalidSearchQuery},
+		{name: "Too many words", search: Search{Query: "a b c d e f g h i j k"}, wantErr: ErrInvalidSearchQuery},
+		{name: "Too long", search: Search{Query: strings.Repeat("a", MaxSearchQueryLength+1)}, wantErr: ErrInvalidSearchQuery},
+		{name: "Limit too large", search: Search{Query: "a", Limit: MaxSearchLimit + 1}, wantErr: ErrInvalidPage},
+		{name: "Negative offset", search: Search{Query: "a", Offset: -1}, wantErr: ErrInvalidPage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.search.Validate()
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.wantLimit, tt.search.Limit)
+			require.Equal(t, tt.wantTerms, tt.search.Terms())
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package url
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrSignatureRequired indicates that a signed only link was requested without a valid signature
+	ErrSignatureRequired = errors.New("valid signature required")
+	// ErrSignatureExpired indicates that the signature of a signed URL has expired
+	ErrSignatureExpired = errors.New("signature has expired")
+	// ErrInvalidSignatureExpiry indicates that a signed URL would expire in the past or too far in the future
+	ErrInvalidSignatureExpiry = errors.New("expires_at must be in the future and within the configured maximum")
+	// ErrInvalidSignedPath indicates that the path or query to sign is malformed
+	ErrInvalidSignedPath = errors.New("invalid path or query to sign")
+	// ErrSigningDisabled indicates that no signing keys are configured
+	ErrSigningDisabled = errors.New("signed URLs are not configured")
+)
+
+// Query parameters carrying the signature of a signed URL. They are removed
+// from the query before it is forwarded to passthrough destinations.
+const (
+	SignatureParam        = "sig"
+	SignatureExpiresParam = "sig_exp"
+	SignatureKeyParam     = "sig_kid"
+)
+
+// URLSignature is the signature of a signed URL as read from its query.
+type URLSignature struct {
+	KeyID     string
+	Signature string
+	ExpiresAt time.Time
+}
+
+// SignOptions describes the signed URL to mint for a link.
+type SignOptions struct {
+	// ExpiresAt is when the URL stops working; nil selects the configured default.
+	ExpiresAt *time.Time
+	// ExtraPath and RawQuery are signed together with the alias, in the escaped
+	// and encoded form of Visit, for passthrough links.
+	ExtraPath string
+	RawQuery  string
+}
+
+// SignedURL is a minted signed URL of a link.
+type SignedURL struct {
+	// URL is absolute when a base URL is configured and a path otherwise.
+	URL       string
+	KeyID     string
+	ExpiresAt time.Time
+}
+
+// SplitSignature removes the signature parameters from an encoded query and
+// returns them with the rest of the query in its original order and encoding.
+// ok is false, and rest the unchanged query, unless each signature parameter
+// appears exactly once and the expiry is a Unix time.
+func SplitSignature(rawQuery string) (sig URLSignature, rest string, ok bool) {
+	if rawQuery == "" {
+		return URLSignature{}, rawQuery, false
+	}
+
+	var (
+		kept    []string
+		expires string
+		seen    = make(map[string]int, 3)
+	)
+	for _, part := range strings.Split(rawQuery, "&") {
+		rawKey, rawValue, _ := strings.Cut(part, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			kept = append(kept, part)
+			continue
+		}
+
+		switch key {
+		case SignatureParam, SignatureExpiresParam, SignatureKeyParam:
+			value, err := url.QueryUnescape(rawValue)
+			if err != nil {
+				return URLSignature{}, rawQuery, false
+			}
+			seen[key]++
+			switch key {
+			case SignatureParam:
+				sig.Signature = value
+			case SignatureExpiresParam:
+				expires = value
+			case SignatureKeyParam:
+				sig.KeyID = value
+			}
+		default:
+			kept = append(kept, part)
+		}
+	}
+
+	if seen[SignatureParam] != 1 || seen[SignatureExpiresParam] != 1 || seen[SignatureKeyParam] != 1 {
+		return URLSignature{}, rawQuery, false
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return URLSignature{}, rawQuery, false
+	}
+	sig.ExpiresAt = time.Unix(unix, 0)
+
+	return sig, strings.Join(kept, "&"), true
+}
+
+// AppendSignature adds the signature parameters to an encoded query.
+func AppendSignature(rawQuery string, sig URLSignature) string {
+	params := url.Values{}
+	params.Set(SignatureExpiresParam, strconv.FormatInt(sig.ExpiresAt.Unix(), 10))
+	params.Set(SignatureKeyParam, sig.KeyID)
+	params.Set(SignatureParam, sig.Signature)
+
+	if rawQuery == "" {
+		return params.Encode()
+	}
+	return rawQuery + "&" + params.Encode()
+}
+
+// SignatureMessage returns the message signed for a URL of alias with the
+// given extra path and query, without signature parameters, and expiry.
+func SignatureMessage(alias, extraPath, rawQuery string, expiresAt time.Time) string {
+	return strings.Join([]string{"redirect", alias, extraPath, rawQuery, strconv.FormatInt(expiresAt.Unix(), 10)}, "\x00")
+}
@@ -0,0 +1,83 @@
+package url
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitSignature(t *testing.T) {
+	expiresAt := time.Unix(1893456000, 0)
+
+	tests := []struct {
+		name     string
+		rawQuery string
+		wantSig  URLSignature
+		wantRest string
+		wantOK   bool
+	}{
+		{
+			name:     "Only signature",
+			rawQuery: "sig_exp=1893456000&sig_kid=k1&sig=abc",
+			wantSig:  URLSignature{KeyID: "k1", Signature: "abc", ExpiresAt: expiresAt},
+			wantOK:   true,
+		},
+		{
+			name:     "Other parameters keep order and encoding",
+			rawQuery: "b=2&sig_exp=1893456000&a=x%20y&sig_kid=k1&sig=abc&c",
+			wantSig:  URLSignature{KeyID: "k1", Signature: "abc", ExpiresAt: expiresAt},
+			wantRest: "b=2&a=x%20y&c",
+			wantOK:   true,
+		},
+		{
+			name:     "Encoded parameter names",
+			rawQuery: "sig%5Fexp=1893456000&sig_kid=k1&sig=a%2Db",
+			wantSig:  URLSignature{KeyID: "k1", Signature: "a-b", ExpiresAt: expiresAt},
+			wantOK:   true,
+		},
+		{name: "No query", rawQuery: ""},
+		{name: "Missing key id", rawQuery: "sig_exp=1893456000&sig=abc", wantRest: "sig_exp=1893456000&sig=abc"},
+		{name: "Duplicate signature", rawQuery: "sig_exp=1893456000&sig_kid=k1&sig=abc&sig=def", wantRest: "sig_exp=1893456000&sig_kid=k1&sig=abc&sig=def"},
+		{name: "Invalid expiry", rawQuery: "sig_exp=tomorrow&sig_kid=k1&sig=abc", wantRest: "sig_exp=tomorrow&sig_kid=k1&sig=abc"},
+		{name: "Unrelated query", rawQuery: "q=go", wantRest: "q=go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig, rest, ok := SplitSignature(tt.rawQuery)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.wantRest, rest)
+			if tt.wantOK {
+				require.Equal(t, tt.wantSig.KeyID, sig.KeyID)
+				require.Equal(t, tt.wantSig.Signature, sig.Signature)
+				require.True(t, tt.wantSig.ExpiresAt.Equal(sig.ExpiresAt))
+			}
+		})
+	}
+}
+
+func TestAppendSignatureRoundTrip(t *testing.T) {
+	sig := URLSignature{KeyID: "2025-01", Signature: "c2lnbmF0dXJl", ExpiresAt: time.Unix(1893456000, 0)}
+
+	for _, rawQuery := range []string{"", "utm_source=mail&q=a%26b"} {
+		signed := AppendSignature(rawQuery, sig)
+
+		got, rest, ok := SplitSignature(signed)
+		require.True(t, ok)
+		require.Equal(t, rawQuery, rest)
+		require.Equal(t, sig.KeyID, got.KeyID)
+		require.Equal(t, sig.Signature, got.Signature)
+		require.True(t, sig.ExpiresAt.Equal(got.ExpiresAt))
+	}
+}
+
+func TestSignatureMessage(t *testing.T) {
+	expiresAt := time.Unix(1893456000, 0)
+
+	base := SignatureMessage("promo", "", "", expiresAt)
+	require.NotEqual(t, base, SignatureMessage("promo2", "", "", expiresAt))
+	require.NotEqual(t, base, SignatureMessage("promo", "x", "", expiresAt))
+	require.NotEqual(t, base, SignatureMessage("promo", "", "x=1", expiresAt))
+	require.NotEqual(t, base, SignatureMessage("promo", "", "", expiresAt.Add(time.Second)))
+}
@@ -0,0 +1,107 @@
+package url
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidTargeting indicates that the targeting rules of a link are malformed
+var ErrInvalidTargeting = errors.New("each targeting rule needs a known platform, device or country, at most 20 rules")
+
+// MaxTargetingRules is the maximum number of targeting rules per link.
+const MaxTargetingRules = 20
+
+// Platforms a targeting rule can match.
+var targetingPlatforms = []string{"ios", "android", "windows", "macos", "linux", "chromeos", "other"}
+
+// Devices a targeting rule can match.
+var targetingDevices = []string{"mobile", "tablet", "desktop", "bot", "unknown"}
+
+// TargetingRule sends visitors matching every condition set on the rule to URL.
+// Unset conditions match any visitor, but a rule must set at least one.
+type TargetingRule struct {
+	Platform string `json:"platform,omitempty"`
+	Device   string `json:"device,omitempty"`
+	// Country is an ISO 3166-1 alpha-2 code such as "DE", compared
+	// case-insensitively. It never matches visitors whose country is unknown.
+	Country string `json:"country,omitempty"`
+	URL     string `json:"url"`
+}
+
+// Audience describes the visitor a link is resolved for.
+type Audience struct {
+	Platform string
+	Device   string
+	// Country is empty when it could not be determined.
+	Country string
+}
+
+// Matches reports whether the audience satisfies every condition of the rule.
+func (r TargetingRule) Matches(a Audience) bool {
+	if r.Platform != "" && r.Platform != a.Platform {
+		return false
+	}
+	if r.Device != "" && r.Device != a.Device {
+		return false
+	}
+	if r.Country != "" && !strings.EqualFold(r.Country, a.Country) {
+		return false
+	}
+	return true
+}
+
+// HasCountryRules reports whether any rule depends on the visitor's country.
+func HasCountryRules(rules []TargetingRule) bool {
+	return slices.ContainsFunc(rules, func(r TargetingRule) bool {
+		return r.Country != ""
+	})
+}
+
+// ValidateTargeting checks the conditions of every rule. Rule URLs are checked
+// by the caller with the same policy as the main destination.
+func ValidateTargeting(rules []TargetingRule) error {
+	if len(rules) > MaxTargetingRules {
+		return ErrInvalidTargeting
+	}
+
+	for _, rule := range rules {
+		if rule.Platform == "" && rule.Device == "" && rule.Country == "" {
+			return ErrInvalidTargeting
+		}
+		if rule.Platform != "" && !slices.Contains(targetingPlatforms, rule.Platform) {
+			return ErrInvalidTargeting
+		}
+		if rule.Device != "" && !slices.Contains(targetingDevices, rule.Device) {
+			return ErrInvalidTargeting
+		}
+		if rule.Country != "" && !isCountryCode(rule.Country) {
+			return ErrInvalidTargeting
+		}
+	}
+
+	return nil
+}
+
+// isCountryCode checks the shape of an ISO 3166-1 alpha-2 code.
+func isCountryCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, c := range code {
+		if (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') {
+			return false
+		}
+	}
+	return true
+}
+
+// SelectTarget returns the URL of the first rule matching the audience.
+func SelectTarget(rules []TargetingRule, a Audience) (string, bool) {
+	for _, rule := range rules {
+		if rule.Matches(a) {
+			return rule.URL, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,127 @@
+package url
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateTargeting(t *testing.T) {
+	tooMany := make([]TargetingRule, MaxTargetingRules+1)
+	for i := range tooMany {
+		tooMany[i] = TargetingRule{Device: "mobile", URL: "https://example.com"}
+	}
+
+	tests := []struct {
+		name    string
+		rules   []TargetingRule
+		wantErr error
+	}{
+		{name: "No rules"},
+		{
+			name:  "Platform and device",
+			rules: []TargetingRule{{Platform: "ios", Device: "tablet", URL: "https://example.com"}},
+		},
+		{
+			name:  "Country in either case",
+			rules: []TargetingRule{{Country: "DE", URL: "https://example.de"}, {Country: "fr", URL: "https://example.fr"}},
+		},
+		{
+			name:    "Country is not a two-letter code",
+			rules:   []TargetingRule{{Country: "DEU", URL: "https://example.de"}},
+			wantErr: ErrInvalidTargeting,
+		},
+		{
+			name:    "Country with non-letters",
+			rules:   []TargetingRule{{Country: "D1", URL: "https://example.de"}},
+			wantErr: ErrInvalidTargeting,
+		},
+		{
+			name:    "Rule without conditions",
+			rules:   []TargetingRule{{URL: "https://example.com"}},
+			wantErr: ErrInvalidTargeting,
+		},
+		{
+			name:    "Unknown platform",
+			rules:   []TargetingRule{{Platform: "iOS", URL: "https://example.com"}},
+			wantErr: ErrInvalidTargeting,
+		},
+		{
+			name:    "Unknown device",
+			rules:   []TargetingRule{{Device: "watch", URL: "https://example.com"}},
+			wantErr: ErrInvalidTargeting,
+		},
+		{
+			name:    "Too many rules",
+			rules:   tooMany,
+			wantErr: ErrInvalidTargeting,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateTargeting(tt.rules); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateTargeting() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSelectTarget(t *testing.T) {
+	rules := []TargetingRule{
+		{Country: "de", Device: "mobile", URL: "https://example.de/mobile"},
+		{Platform: "ios", Device: "tablet", URL: "https://example.com/ipad"},
+		{Platform: "ios", URL: "https://example.com/ios"},
+		{Device: "mobile", URL: "https://example.com/mobile"},
+	}
+
+	tests := []struct {
+		name     string
+		audience Audience
+		want     string
+		wantOK   bool
+	}{
+		{
+			name:     "Most specific rule listed first wins",
+			audience: Audience{Platform: "ios", Device: "tablet"},
+			want:     "https://example.com/ipad",
+			wantOK:   true,
+		},
+		{
+			name:     "Platform only rule",
+			audience: Audience{Platform: "ios", Device: "mobile"},
+			want:     "https://example.com/ios",
+			wantOK:   true,
+		},
+		{
+			name:     "Device only rule",
+			audience: Audience{Platform: "android", Device: "mobile"},
+			want:     "https://example.com/mobile",
+			wantOK:   true,
+		},
+		{
+			name:     "Country and device",
+			audience: Audience{Platform: "android", Device: "mobile", Country: "DE"},
+			want:     "https://example.de/mobile",
+			wantOK:   true,
+		},
+		{
+			name:     "Unknown country skips country rules",
+			audience: Audience{Platform: "android", Device: "mobile"},
+			want:     "https://example.com/mobile",
+			wantOK:   true,
+		},
+		{
+			name:     "No match",
+			audience: Audience{Platform: "windows", Device: "desktop"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SelectTarget(rules, tt.audience)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("SelectTarget() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
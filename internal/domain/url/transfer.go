@@ -0,0 +1,46 @@
+package url
+
+import (
+	"errors"
+	"net/mail"
+	"time"
+)
+
+var (
+	// ErrInvalidRecipient indicates that a link cannot be transferred to the given user
+	ErrInvalidRecipient = errors.New("recipient must be a valid email address other than the current owner")
+	// ErrTransferNotFound indicates that a link has no pending transfer for the requester
+	ErrTransferNotFound = errors.New("transfer not found")
+)
+
+// Transfer is a request to hand a link over to another owner. A pending
+// transfer waits for the recipient to accept it.
+type Transfer struct {
+	Alias       string    `json:"alias"`
+	FromEmail   string    `json:"from_email"`
+	ToEmail     string    `json:"to_email"`
+	RequestedBy string    `json:"requested_by"`
+	RequestedAt time.Time `json:"requested_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	Pending     bool      `json:"pending"`
+}
+
+// TransferOptions are the optional parameters of an ownership transfer.
+type TransferOptions struct {
+	// RequireAcceptance makes the transfer wait for the recipient to accept
+	// it; nil falls back to the configured default.
+	RequireAcceptance *bool
+}
+
+// ValidateRecipient checks that a link owned by ownerEmail can be handed over
+// to recipientEmail, which has to be a bare email address.
+func ValidateRecipient(ownerEmail, recipientEmail string) error {
+	addr, err := mail.ParseAddress(recipientEmail)
+	if err != nil || addr.Address != recipientEmail || addr.Name != "" {
+		return ErrInvalidRecipient
+	}
+	if recipientEmail == ownerEmail {
+		return ErrInvalidRecipient
+	}
+	return nil
+}
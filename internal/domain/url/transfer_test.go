@@ -0,0 +1,33 @@
+package url
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRecipient(t *testing.T) {
+	tests := []struct {
+		name      string
+		recipient string
+		wantErr   bool
+	}{
+		{name: "Other user", recipient: "new@example.com"},
+		{name: "Current owner", recipient: "owner@example.com", wantErr: true},
+		{name: "Empty", recipient: "", wantErr: true},
+		{name: "Not an email", recipient: "new-owner", wantErr: true},
+		{name: "Display name", recipient: "New Owner <new@example.com>", wantErr: true},
+		{name: "Surrounding spaces", recipient: " new@example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRecipient("owner@example.com", tt.recipient)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidRecipient)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package url
 import (
 	"errors"
 	"net/url"
+	"time"
 )
 
 var (
@@ -14,8 +15,26 @@ var (
 	ErrInvalidScheme = errors.New("only http and https schemes are allowed")
 	// ErrAliasExists indicates that the alias already exists
 	ErrAliasExists = errors.New("alias already exists")
+	// ErrInvalidAlias indicates that a custom alias cannot be used for a link
+	ErrInvalidAlias = errors.New("alias may only contain letters, digits, '-' and '_' and must not be reserved")
 	// ErrPermissionDenied indicates that the user does not have rights to perform the action
 	ErrPermissionDenied = errors.New("permission denied")
+	// ErrURLBlocked indicates that the destination is on the malicious URL blocklist
+	ErrURLBlocked = errors.New("destination is blocklisted")
+	// ErrInvalidRedirectType indicates that the redirect status code is not supported
+	ErrInvalidRedirectType = errors.New("redirect type must be one of 301, 302, 307, 308")
+	// ErrTitleTooLong indicates that the link title exceeds MaxTitleLength
+	ErrTitleTooLong = errors.New("title must be at most 200 characters")
+	// ErrNotesTooLong indicates that the link notes exceed MaxNotesLength
+	ErrNotesTooLong = errors.New("notes must be at most 2000 characters")
+	// ErrEmptyUpdate indicates that a link update does not change anything
+	ErrEmptyUpdate = errors.New("nothing to update")
+	// ErrInvalidMaxClicks indicates that the click limit of a link is negative
+	ErrInvalidMaxClicks = errors.New("max_clicks must not be negative")
+	// ErrLinkExhausted indicates that the link has reached its click limit
+	ErrLinkExhausted = errors.New("link has reached its click limit")
+	// ErrWorkspaceLink indicates that the action is not available for links owned by a workspace
+	ErrWorkspaceLink = errors.New("link belongs to a workspace")
 )
 
 // ValidateURL validates that the URL has correct format and uses http/https scheme
@@ -41,3 +60,85 @@ func ValidateURL(rawURL string) error {
 
 	return nil
 }
+
+// reservedAliases are the first path segments of the fixed routes served next
+// to "/{alias}". A link with one of them as its alias would never be reached,
+// and neither would the extra path of a passthrough link.
+var reservedAliases = map[string]struct{}{
+	"url": {},
+}
+
+// ValidateAlias checks that a custom alias does not collide with a fixed
+// route. Aliases are limited to ASCII letters, digits, '-' and '_', which keeps
+// them a single path segment that no other route claims: "/{alias}+" is the
+// preview page and a '.' would be taken for a format extension.
+func ValidateAlias(alias string) error {
+	for _, r := range alias {
+		if !isAliasRune(r) {
+			return ErrInvalidAlias
+		}
+	}
+	if _, ok := reservedAliases[alias]; ok {
+		return ErrInvalidAlias
+	}
+	return nil
+}
+
+func isAliasRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_'
+}
+
+// ShortenOptions holds optional per-request settings for creating a short link.
+type ShortenOptions struct {
+	// Dedup overrides the configured deduplication mode when set. With dedup
+	// enabled, shortening a destination the owner has already shortened returns
+	// the existing alias instead of minting a new one.
+	Dedup *bool
+	// RedirectType is the redirect status code for the link; zero selects the
+	// configured default.
+	RedirectType int
+	// Title is an optional label for the link, at most MaxTitleLength characters.
+	Title string
+	// Notes are optional remarks of the owner, at most MaxNotesLength
+	// characters.
+	Notes string
+	// Warn shows visitors an interstitial page before redirecting them.
+	Warn bool
+	// Passthrough is the passthrough mode, one of the Passthrough* constants.
+	Passthrough string
+	// UTM is added to the destination at redirect time and can be changed later.
+	UTM *UTMTemplate
+	// Targeting is the ordered list of device and platform targeting rules.
+	Targeting []TargetingRule
+	// Variants split visitors between several destinations by weight.
+	Variants []Variant
+	// StickyVariants keeps returning visitors on the variant they first got.
+	StickyVariants bool
+	// Password protects the link; visitors have to enter it before they are
+	// redirected. Empty leaves the link public.
+	Password string
+	// MaxClicks is the number of redirects after which the link stops
+	// working, 1 for one-time links; zero means unlimited.
+	MaxClicks int
+	// NotBefore and NotAfter schedule when the link starts and stops
+	// redirecting; nil leaves that end open.
+	NotBefore *time.Time
+	NotAfter  *time.Time
+	// SignedOnly only redirects visits with a valid signed URL.
+	SignedOnly bool
+	// WorkspaceID creates the link in the workspace instead of as a personal
+	// link; zero creates a personal link.
+	WorkspaceID int64
+	// Tags label the link; FolderID puts it into a folder of its owner.
+	Tags     []string
+	FolderID int64
+}
+
+// CustomizesLink reports whether the options configure how the link
+// redirects, so that an existing link to the same destination cannot stand in
+// for the new one.
+func (o ShortenOptions) CustomizesLink() bool {
+	return o.RedirectType != 0 || o.Warn || o.Passthrough != PassthroughOff || (o.UTM != nil && !o.UTM.IsZero()) ||
+		len(o.Targeting) > 0 || len(o.Variants) > 0 || o.Password != "" || o.NotBefore != nil || o.NotAfter != nil ||
+		o.SignedOnly || o.MaxClicks > 0
+}
@@ -3,6 +3,7 @@ package url
 import (
 	"errors"
 	"testing"
+	"time"
 )
 
 func TestValidateURL(t *testing.T) {
@@ -87,3 +88,59 @@ func TestValidateURL(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAlias(t *testing.T) {
+	tests := []struct {
+		name    string
+		alias   string
+		wantErr error
+	}{
+		{name: "Custom alias", alias: "docs", wantErr: nil},
+		{name: "Alias starting with a route", alias: "urls", wantErr: nil},
+		{name: "Dashes and underscores", alias: "Spring_sale-2025", wantErr: nil},
+		{name: "Route of the link API", alias: "url", wantErr: ErrInvalidAlias},
+		{name: "Trailing plus of the preview page", alias: "docs+", wantErr: ErrInvalidAlias},
+		{name: "Slash", alias: "docs/v2", wantErr: ErrInvalidAlias},
+		{name: "Format extension", alias: "docs.json", wantErr: ErrInvalidAlias},
+		{name: "Non-ASCII letter", alias: "café", wantErr: ErrInvalidAlias},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateAlias(tt.alias); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateAlias() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShortenOptionsCustomizesLink(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ShortenOptions
+		want bool
+	}{
+		{name: "No options", opts: ShortenOptions{}, want: false},
+		{name: "Title and tags only", opts: ShortenOptions{Title: "Docs", Tags: []string{"docs"}}, want: false},
+		{name: "Empty UTM template", opts: ShortenOptions{UTM: &UTMTemplate{}}, want: false},
+		{name: "Redirect type", opts: ShortenOptions{RedirectType: 301}, want: true},
+		{name: "Warn", opts: ShortenOptions{Warn: true}, want: true},
+		{name: "Passthrough", opts: ShortenOptions{Passthrough: PassthroughPreserve}, want: true},
+		{name: "UTM template", opts: ShortenOptions{UTM: &UTMTemplate{Source: "newsletter"}}, want: true},
+		{name: "Targeting", opts: ShortenOptions{Targeting: []TargetingRule{{Platform: "ios", URL: "https://example.com"}}}, want: true},
+		{name: "Variants", opts: ShortenOptions{Variants: []Variant{{Name: "a", URL: "https://example.com", Weight: 1}}}, want: true},
+		{name: "Password", opts: ShortenOptions{Password: "secret"}, want: true},
+		{name: "Not before", opts: ShortenOptions{NotBefore: &time.Time{}}, want: true},
+		{name: "Not after", opts: ShortenOptions{NotAfter: &time.Time{}}, want: true},
+		{name: "Signed only", opts: ShortenOptions{SignedOnly: true}, want: true},
+		{name: "Max clicks", opts: ShortenOptions{MaxClicks: 1}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.CustomizesLink(); got != tt.want {
+				t.Errorf("CustomizesLink() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
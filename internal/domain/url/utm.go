@@ -0,0 +1,107 @@
+package url
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrInvalidUTM indicates that a UTM template value is too long or contains control characters
+var ErrInvalidUTM = errors.New("utm values must be at most 200 characters without control characters")
+
+// maxUTMValueLength is the maximum length of a single UTM value in characters.
+const maxUTMValueLength = 200
+
+// UTMTemplate holds the UTM parameters added to the destination at redirect
+// time. Empty fields are not added.
+type UTMTemplate struct {
+	Source   string `json:"source,omitempty"`
+	Medium   string `json:"medium,omitempty"`
+	Campaign string `json:"campaign,omitempty"`
+	Content  string `json:"content,omitempty"`
+	Term     string `json:"term,omitempty"`
+}
+
+// IsZero reports whether the template sets no parameter.
+func (t UTMTemplate) IsZero() bool {
+	return t == UTMTemplate{}
+}
+
+// params returns the query parameters of the template in the conventional order.
+func (t UTMTemplate) params() [][2]string {
+	return [][2]string{
+		{"utm_source", t.Source},
+		{"utm_medium", t.Medium},
+		{"utm_campaign", t.Campaign},
+		{"utm_term", t.Term},
+		{"utm_content", t.Content},
+	}
+}
+
+// ValidateUTM checks the length and characters of every template value.
+func ValidateUTM(t UTMTemplate) error {
+	for _, param := range t.params() {
+		value := param[1]
+		if utf8.RuneCountInString(value) > maxUTMValueLength {
+			return ErrInvalidUTM
+		}
+		if strings.ContainsFunc(value, func(r rune) bool { return r < 0x20 || r == 0x7f }) {
+			return ErrInvalidUTM
+		}
+	}
+	return nil
+}
+
+// ApplyUTM adds the parameters of the template to destination. A parameter set
+// by the template replaces every value of the same key already in destination;
+// parameters the template leaves empty are not touched.
+func ApplyUTM(destination string, t UTMTemplate) (string, error) {
+	if t.IsZero() {
+		return destination, nil
+	}
+
+	parsedURL, err := url.Parse(destination)
+	if err != nil {
+		return "", ErrInvalidURL
+	}
+
+	set := make(map[string]struct{})
+	var added []string
+	for _, param := range t.params() {
+		if param[1] == "" {
+			continue
+		}
+		set[param[0]] = struct{}{}
+		added = append(added, param[0]+"="+url.QueryEscape(param[1]))
+	}
+
+	var parts []string
+	for part := range strings.SplitSeq(parsedURL.RawQuery, "&") {
+		if part == "" {
+			continue
+		}
+		key, _, _ := strings.Cut(part, "=")
+		if decoded, err := url.QueryUnescape(key); err == nil {
+			key = decoded
+		}
+		if _, ok := set[key]; ok {
+			continue
+		}
+		parts = append(parts, part)
+	}
+
+	parsedURL.RawQuery = strings.Join(append(parts, added...), "&")
+
+	return parsedURL.String(), nil
+}
+
+// UTMVersion is one revision of the UTM template of a link. Every change of
+// the template creates a new version; version 0 means no template was ever set.
+type UTMVersion struct {
+	Version   int         `json:"version"`
+	Template  UTMTemplate `json:"template"`
+	CreatedAt *time.Time  `json:"created_at,omitempty"`
+	Clicks    int         `json:"clicks"`
+}
@@ -0,0 +1,70 @@
+package url
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestApplyUTM(t *testing.T) {
+	tests := []struct {
+		name        string
+		destination string
+		utm         UTMTemplate
+		want        string
+	}{
+		{
+			name:        "Empty template keeps destination",
+			destination: "https://example.com/page?utm_source=old",
+			want:        "https://example.com/page?utm_source=old",
+		},
+		{
+			name:        "Adds parameters in conventional order",
+			destination: "https://example.com/page",
+			utm:         UTMTemplate{Content: "banner", Term: "shoes", Campaign: "spring", Medium: "email", Source: "newsletter"},
+			want:        "https://example.com/page?utm_source=newsletter&utm_medium=email&utm_campaign=spring&utm_term=shoes&utm_content=banner",
+		},
+		{
+			name:        "Replaces existing values of set parameters only",
+			destination: "https://example.com/?utm_source=old&id=7&utm_medium=cpc&utm_source=older",
+			utm:         UTMTemplate{Source: "newsletter"},
+			want:        "https://example.com/?id=7&utm_medium=cpc&utm_source=newsletter",
+		},
+		{
+			name:        "Escapes values",
+			destination: "https://example.com/",
+			utm:         UTMTemplate{Campaign: "spring sale & more"},
+			want:        "https://example.com/?utm_campaign=spring+sale+%26+more",
+		},
+		{
+			name:        "Keeps fragment",
+			destination: "https://example.com/#pricing",
+			utm:         UTMTemplate{Source: "x"},
+			want:        "https://example.com/?utm_source=x#pricing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyUTM(tt.destination, tt.utm)
+			if err != nil {
+				t.Fatalf("ApplyUTM() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ApplyUTM() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateUTM(t *testing.T) {
+	if err := ValidateUTM(UTMTemplate{Source: "newsletter", Campaign: "Frühling"}); err != nil {
+		t.Errorf("ValidateUTM() unexpected error: %v", err)
+	}
+	if err := ValidateUTM(UTMTemplate{Medium: "a\r\nb"}); !errors.Is(err, ErrInvalidUTM) {
+		t.Errorf("ValidateUTM() error = %v, want %v", err, ErrInvalidUTM)
+	}
+	if err := ValidateUTM(UTMTemplate{Term: strings.Repeat("x", maxUTMValueLength+1)}); !errors.Is(err, ErrInvalidUTM) {
+		t.Errorf("ValidateUTM() error = %v, want %v", err, ErrInvalidUTM)
+	}
+}
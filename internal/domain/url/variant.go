@@ -0,0 +1,102 @@
+package url
+
+import (
+	"errors"
+	"regexp"
+)
+
+// ErrInvalidVariants indicates that the split destinations of a link are malformed
+var ErrInvalidVariants = errors.New("variants need unique names of letters, digits, '-' or '_', weights from 0 to 1000 with a positive total, at most 10 variants")
+
+const (
+	// MaxVariants is the maximum number of split destinations per link.
+	MaxVariants = 10
+	// MaxVariantWeight is the maximum weight of a single variant.
+	MaxVariantWeight = 1000
+)
+
+var variantNameRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Variant is one destination of an A/B split. Each request picks a variant
+// with probability proportional to its weight; a zero weight pauses it.
+type Variant struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Weight int    `json:"weight"`
+}
+
+// VariantStats is a variant together with the number of clicks it received.
+// Variants removed from the link keep their clicks but have no URL.
+type VariantStats struct {
+	Variant
+	Clicks int `json:"clicks"`
+}
+
+// ValidateVariants checks names and weights and validates every variant URL
+// with ValidateURL.
+func ValidateVariants(variants []Variant) error {
+	if len(variants) == 0 {
+		return nil
+	}
+	if len(variants) > MaxVariants {
+		return ErrInvalidVariants
+	}
+
+	names := make(map[string]struct{}, len(variants))
+	for _, v := range variants {
+		if !variantNameRe.MatchString(v.Name) {
+			return ErrInvalidVariants
+		}
+		if _, dup := names[v.Name]; dup {
+			return ErrInvalidVariants
+		}
+		names[v.Name] = struct{}{}
+
+		if v.Weight < 0 || v.Weight > MaxVariantWeight {
+			return ErrInvalidVariants
+		}
+		if err := ValidateURL(v.URL); err != nil {
+			return err
+		}
+	}
+
+	if TotalWeight(variants) == 0 {
+		return ErrInvalidVariants
+	}
+
+	return nil
+}
+
+// TotalWeight returns the sum of the variant weights.
+func TotalWeight(variants []Variant) int {
+	total := 0
+	for _, v := range variants {
+		total += v.Weight
+	}
+	return total
+}
+
+// FindVariant returns the variant with the given name if it is still active.
+func FindVariant(variants []Variant, name string) (Variant, bool) {
+	for _, v := range variants {
+		if v.Name == name && v.Weight > 0 {
+			return v, true
+		}
+	}
+	return Variant{}, false
+}
+
+// PickVariant maps roll, a number in [0, TotalWeight(variants)), to a variant
+// so that each variant covers a share of the range equal to its weight.
+func PickVariant(variants []Variant, roll int) (Variant, bool) {
+	if roll < 0 {
+		return Variant{}, false
+	}
+	for _, v := range variants {
+		if roll < v.Weight {
+			return v, true
+		}
+		roll -= v.Weight
+	}
+	return Variant{}, false
+}
@@ -0,0 +1,145 @@
+package url
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateVariants(t *testing.T) {
+	tooMany := make([]Variant, MaxVariants+1)
+	for i := range tooMany {
+		tooMany[i] = Variant{Name: string(rune('a' + i)), URL: "https://example.com", Weight: 1}
+	}
+
+	tests := []struct {
+		name     string
+		variants []Variant
+		wantErr  error
+	}{
+		{name: "No variants"},
+		{
+			name: "Two variants",
+			variants: []Variant{
+				{Name: "control", URL: "https://example.com/a", Weight: 90},
+				{Name: "new-page_2", URL: "https://example.com/b", Weight: 10},
+			},
+		},
+		{
+			name: "Paused variant",
+			variants: []Variant{
+				{Name: "a", URL: "https://example.com/a", Weight: 1},
+				{Name: "b", URL: "https://example.com/b", Weight: 0},
+			},
+		},
+		{
+			name:     "Empty name",
+			variants: []Variant{{URL: "https://example.com", Weight: 1}},
+			wantErr:  ErrInvalidVariants,
+		},
+		{
+			name:     "Name with unsupported characters",
+			variants: []Variant{{Name: "a b", URL: "https://example.com", Weight: 1}},
+			wantErr:  ErrInvalidVariants,
+		},
+		{
+			name:     "Name too long",
+			variants: []Variant{{Name: strings.Repeat("a", 33), URL: "https://example.com", Weight: 1}},
+			wantErr:  ErrInvalidVariants,
+		},
+		{
+			name: "Duplicate names",
+			variants: []Variant{
+				{Name: "a", URL: "https://example.com/a", Weight: 1},
+				{Name: "a", URL: "https://example.com/b", Weight: 1},
+			},
+			wantErr: ErrInvalidVariants,
+		},
+		{
+			name:     "Negative weight",
+			variants: []Variant{{Name: "a", URL: "https://example.com", Weight: -1}},
+			wantErr:  ErrInvalidVariants,
+		},
+		{
+			name:     "Weight too large",
+			variants: []Variant{{Name: "a", URL: "https://example.com", Weight: MaxVariantWeight + 1}},
+			wantErr:  ErrInvalidVariants,
+		},
+		{
+			name:     "All weights zero",
+			variants: []Variant{{Name: "a", URL: "https://example.com", Weight: 0}},
+			wantErr:  ErrInvalidVariants,
+		},
+		{
+			name:     "Too many variants",
+			variants: tooMany,
+			wantErr:  ErrInvalidVariants,
+		},
+		{
+			name:     "Invalid URL",
+			variants: []Variant{{Name: "a", URL: "not a url", Weight: 1}},
+			wantErr:  ErrInvalidURL,
+		},
+		{
+			name:     "Unsupported scheme",
+			variants: []Variant{{Name: "a", URL: "javascript:alert(1)", Weight: 1}},
+			wantErr:  ErrInvalidScheme,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateVariants(tt.variants); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateVariants() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPickVariant(t *testing.T) {
+	variants := []Variant{
+		{Name: "a", Weight: 2},
+		{Name: "paused", Weight: 0},
+		{Name: "b", Weight: 1},
+	}
+
+	tests := []struct {
+		roll   int
+		want   string
+		wantOK bool
+	}{
+		{roll: 0, want: "a", wantOK: true},
+		{roll: 1, want: "a", wantOK: true},
+		{roll: 2, want: "b", wantOK: true},
+		{roll: 3},
+		{roll: -1},
+	}
+
+	for _, tt := range tests {
+		got, ok := PickVariant(variants, tt.roll)
+		if got.Name != tt.want || ok != tt.wantOK {
+			t.Errorf("PickVariant(%d) = %q, %v, want %q, %v", tt.roll, got.Name, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	if total := TotalWeight(variants); total != 3 {
+		t.Errorf("TotalWeight() = %d, want 3", total)
+	}
+}
+
+func TestFindVariant(t *testing.T) {
+	variants := []Variant{
+		{Name: "a", Weight: 1},
+		{Name: "paused", Weight: 0},
+	}
+
+	if _, ok := FindVariant(variants, "a"); !ok {
+		t.Error("FindVariant(a) did not find an active variant")
+	}
+	if _, ok := FindVariant(variants, "paused"); ok {
+		t.Error("FindVariant(paused) returned a paused variant")
+	}
+	if _, ok := FindVariant(variants, "gone"); ok {
+		t.Error("FindVariant(gone) returned a removed variant")
+	}
+}
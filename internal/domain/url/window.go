@@ -0,0 +1,50 @@
+package url
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrInvalidActivationWindow indicates that not_after is not later than not_before
+	ErrInvalidActivationWindow = errors.New("not_after must be later than not_before")
+	// ErrLinkNotYetActive indicates that the link only starts redirecting at its not_before time
+	ErrLinkNotYetActive = errors.New("link is not active yet")
+	// ErrLinkExpired indicates that the link stopped redirecting at its not_after time
+	ErrLinkExpired = errors.New("link has expired")
+)
+
+// NotYetActiveError reports when a link that is not active yet starts
+// redirecting.
+type NotYetActiveError struct {
+	NotBefore time.Time
+}
+
+func (e *NotYetActiveError) Error() string {
+	return fmt.Sprintf("%s: active from %s", ErrLinkNotYetActive, e.NotBefore.UTC().Format(time.RFC3339))
+}
+
+func (e *NotYetActiveError) Is(target error) bool {
+	return target == ErrLinkNotYetActive
+}
+
+// ValidateActivationWindow checks that a window with both ends set is not empty.
+func ValidateActivationWindow(notBefore, notAfter *time.Time) error {
+	if notBefore != nil && notAfter != nil && !notAfter.After(*notBefore) {
+		return ErrInvalidActivationWindow
+	}
+	return nil
+}
+
+// CheckActive reports whether the link redirects at now: it returns a
+// *NotYetActiveError before NotBefore and ErrLinkExpired from NotAfter on.
+func (l Link) CheckActive(now time.Time) error {
+	if l.NotBefore != nil && now.Before(*l.NotBefore) {
+		return &NotYetActiveError{NotBefore: *l.NotBefore}
+	}
+	if l.NotAfter != nil && !now.Before(*l.NotAfter) {
+		return ErrLinkExpired
+	}
+	return nil
+}
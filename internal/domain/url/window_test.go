@@ -0,0 +1,61 @@
+package url
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateActivationWindow(t *testing.T) {
+	start := time.Date(2026, time.May, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	require.NoError(t, ValidateActivationWindow(nil, nil))
+	require.NoError(t, ValidateActivationWindow(&start, nil))
+	require.NoError(t, ValidateActivationWindow(nil, &end))
+	require.NoError(t, ValidateActivationWindow(&start, &end))
+	require.ErrorIs(t, ValidateActivationWindow(&end, &start), ErrInvalidActivationWindow)
+	require.ErrorIs(t, ValidateActivationWindow(&start, &start), ErrInvalidActivationWindow)
+}
+
+func TestLinkCheckActive(t *testing.T) {
+	start := time.Date(2026, time.May, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		link    Link
+		now     time.Time
+		wantErr error
+	}{
+		{name: "No window", link: Link{}, now: start},
+		{name: "Before start", link: Link{NotBefore: &start}, now: start.Add(-time.Second), wantErr: ErrLinkNotYetActive},
+		{name: "At start", link: Link{NotBefore: &start, NotAfter: &end}, now: start},
+		{name: "Before end", link: Link{NotBefore: &start, NotAfter: &end}, now: end.Add(-time.Second)},
+		{name: "At end", link: Link{NotBefore: &start, NotAfter: &end}, now: end, wantErr: ErrLinkExpired},
+		{name: "Only end", link: Link{NotAfter: &end}, now: end.Add(time.Hour), wantErr: ErrLinkExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.link.CheckActive(tt.now)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNotYetActiveError(t *testing.T) {
+	start := time.Date(2026, time.May, 1, 9, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	err := Link{NotBefore: &start}.CheckActive(start.Add(-time.Hour))
+
+	var notYetActive *NotYetActiveError
+	require.True(t, errors.As(err, &notYetActive))
+	require.True(t, notYetActive.NotBefore.Equal(start))
+	require.Equal(t, "link is not active yet: active from 2026-05-01T07:00:00Z", err.Error())
+}
@@ -0,0 +1,114 @@
+package workspace
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	// ErrWorkspaceNotFound indicates that the requested workspace does not exist
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	// ErrMemberNotFound indicates that the user is not a member of the workspace
+	ErrMemberNotFound = errors.New("member not found")
+	// ErrInvalidName indicates that the workspace name is empty or too long
+	ErrInvalidName = errors.New("workspace name must be 1 to 100 characters")
+	// ErrInvalidRole indicates that the member role is not supported
+	ErrInvalidRole = errors.New("role must be one of viewer, editor, admin")
+	// ErrInvalidMember indicates that the member is not a bare email address
+	ErrInvalidMember = errors.New("member must be a valid email address")
+	// ErrLastAdmin indicates that a change would leave the workspace without an admin
+	ErrLastAdmin = errors.New("workspace must keep at least one admin")
+)
+
+// Role is the access level of a workspace member. Each role includes the
+// rights of the roles before it.
+type Role string
+
+const (
+	// RoleViewer may list the links of the workspace and see their stats and history.
+	RoleViewer Role = "viewer"
+	// RoleEditor may also create, update, sign and delete links.
+	RoleEditor Role = "editor"
+	// RoleAdmin may also manage the members of the workspace.
+	RoleAdmin Role = "admin"
+)
+
+// rank orders the roles by their rights; unknown roles have none.
+func (r Role) rank() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleEditor:
+		return 2
+	case RoleAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Valid reports whether r is a supported role.
+func (r Role) Valid() bool {
+	return r.rank() > 0
+}
+
+// Allows reports whether a member with role r has the rights of required.
+func (r Role) Allows(required Role) bool {
+	return r.Valid() && r.rank() >= required.rank()
+}
+
+// MaxNameLength is the maximum length of a workspace name in characters.
+const MaxNameLength = 100
+
+// Workspace is a group of users sharing ownership of links.
+type Workspace struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	// Role is the role of the user the workspace was listed for.
+	Role Role `json:"role,omitempty"`
+}
+
+// Member is a user taking part in a workspace. Members are invited by email;
+// UID is the user ID recorded once the member signs in, and stays theirs if
+// the email later changes hands.
+type Member struct {
+	WorkspaceID int64     `json:"workspace_id"`
+	Email       string    `json:"email"`
+	UID         int64     `json:"uid,omitempty"`
+	Role        Role      `json:"role"`
+	AddedBy     string    `json:"added_by"`
+	AddedAt     time.Time `json:"added_at"`
+}
+
+// Is reports whether the member is the user with the given email and ID. A
+// member with a recorded user ID is matched by it; one without is matched by
+// email.
+func (m Member) Is(email string, uid int64) bool {
+	if m.UID != 0 {
+		return m.UID == uid
+	}
+	return m.Email != "" && m.Email == email
+}
+
+// ValidateName checks that the workspace name is not blank and not longer
+// than MaxNameLength.
+func ValidateName(name string) error {
+	if strings.TrimSpace(name) == "" || utf8.RuneCountInString(name) > MaxNameLength {
+		return ErrInvalidName
+	}
+	return nil
+}
+
+// ValidateMember checks that email is a bare email address.
+func ValidateMember(email string) error {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || addr.Name != "" {
+		return ErrInvalidMember
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package workspace
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		name     string
+		role     Role
+		required Role
+		want     bool
+	}{
+		{name: "Viewer views", role: RoleViewer, required: RoleViewer, want: true},
+		{name: "Viewer cannot edit", role: RoleViewer, required: RoleEditor},
+		{name: "Editor views", role: RoleEditor, required: RoleViewer, want: true},
+		{name: "Editor edits", role: RoleEditor, required: RoleEditor, want: true},
+		{name: "Editor cannot administer", role: RoleEditor, required: RoleAdmin},
+		{name: "Admin administers", role: RoleAdmin, required: RoleAdmin, want: true},
+		{name: "Unknown role", role: Role("owner"), required: RoleViewer},
+		{name: "Empty role", role: "", required: RoleViewer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.role.Allows(tt.required))
+		})
+	}
+}
+
+func TestMemberIs(t *testing.T) {
+	tests := []struct {
+		name   string
+		member Member
+		email  string
+		uid    int64
+		want   bool
+	}{
+		{name: "Same user ID", member: Member{Email: "old@example.com", UID: 7}, email: "new@example.com", uid: 7, want: true},
+		{name: "Other user ID with the same email", member: Member{Email: "member@example.com", UID: 7}, email: "member@example.com", uid: 8},
+		{name: "No user ID, same email", member: Member{Email: "member@example.com"}, email: "member@example.com", uid: 8, want: true},
+		{name: "No user ID, other email", member: Member{Email: "member@example.com"}, email: "other@example.com", uid: 8},
+		{name: "Empty email", member: Member{}, email: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.member.Is(tt.email, tt.uid))
+		})
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{name: "Valid", input: "Marketing"},
+		{name: "Max length", input: strings.Repeat("я", MaxNameLength)},
+		{name: "Empty", input: "", wantErr: true},
+		{name: "Blank", input: "   ", wantErr: true},
+		{name: "Too long", input: strings.Repeat("a", MaxNameLength+1), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateName(tt.input)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidName)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateMember(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		wantErr bool
+	}{
+		{name: "Valid", email: "member@example.com"},
+		{name: "Empty", email: "", wantErr: true},
+		{name: "Not an email", email: "member", wantErr: true},
+		{name: "Display name", email: "Member <member@example.com>", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMember(tt.email)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidMember)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package redirect
+
+import (
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"time"
+)
+
+// blockedPage is served instead of a redirect when the destination of a link
+// is on the malicious URL blocklist.
+var blockedPage = template.Must(template.New("blocked").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>Link blocked</title>
+<style>
+body { font-family: sans-serif; max-width: 40rem; margin: 4rem auto; padding: 0 1rem; color: #222; }
+h1 { color: #b00020; }
+code { background: #f3f3f3; padding: 0 .25rem; }
+</style>
+</head>
+<body>
+<h1>This link has been blocked</h1>
+<p>The short link <code>{{.Alias}}</code> points to a destination flagged as phishing or malware, so we are not redirecting you.</p>
+<p>If you believe this is a mistake, contact the person who shared the link.</p>
+</body>
+</html>
+`))
+
+type blockedPageData struct {
+	Alias string
+}
+
+// previewPage shows the destination of a link and lets the visitor decide
+// whether to continue.
+var previewPage = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>{{if .Title}}{{.Title}}{{else}}Link preview{{end}}</title>
+<style>
+body { font-family: sans-serif; max-width: 40rem; margin: 4rem auto; padding: 0 1rem; color: #222; }
+code { background: #f3f3f3; padding: 0 .25rem; word-break: break-all; }
+.muted { color: #666; }
+.button { display: inline-block; margin-top: 1rem; padding: .5rem 1rem; background: #1a73e8; color: #fff; text-decoration: none; border-radius: .25rem; }
+</style>
+</head>
+<body>
+<h1>{{if .Title}}{{.Title}}{{else}}Where this link goes{{end}}</h1>
+<p>The short link <code>{{.Alias}}</code> redirects to:</p>
+<p><code>{{.URL}}</code></p>
+{{if .CreatedAt}}<p class="muted">Created on {{.CreatedAt}}.</p>{{end}}
+<a class="button" href="{{.URL}}" rel="noopener noreferrer nofollow">Continue to the destination</a>
+</body>
+</html>
+`))
+
+type previewPageData struct {
+	Alias     string
+	URL       string
+	Title     string
+	CreatedAt string
+}
+
+// passwordPage asks for the password of a protected link. The form posts
+// back to the requested URL unless Action is set, which keeps the extra path
+// and query of passthrough links.
+var passwordPage = template.Must(template.New("password").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>Password required</title>
+<style>
+body { font-family: sans-serif; max-width: 40rem; margin: 4rem auto; padding: 0 1rem; color: #222; }
+code { background: #f3f3f3; padding: 0 .25rem; }
+.error { color: #b00020; }
+input { padding: .5rem; font-size: 1rem; }
+button { margin-left: .5rem; padding: .5rem 1rem; background: #1a73e8; color: #fff; border: 0; border-radius: .25rem; font-size: 1rem; }
+</style>
+</head>
+<body>
+<h1>This link is password protected</h1>
+<p>Enter the password to continue to the destination of <code>{{.Alias}}</code>.</p>
+{{if .Error}}<p class="error">{{.Error}}</p>{{end}}
+<form method="post"{{if .Action}} action="{{.Action}}"{{end}}>
+<input type="password" name="password" autocomplete="current-password" aria-label="Password" required autofocus>
+<button type="submit">Continue</button>
+</form>
+</body>
+</html>
+`))
+
+type passwordPageData struct {
+	Alias  string
+	Action string
+	Error  string
+}
+
+// defaultInterstitialPage is shown before redirecting to a destination marked
+// "warn" unless a custom template is configured.
+var defaultInterstitialPage = template.Must(template.New("interstitial").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>You are leaving this site</title>
+<style>
+body { font-family: sans-serif; max-width: 40rem; margin: 4rem auto; padding: 0 1rem; color: #222; }
+code { background: #f3f3f3; padding: 0 .25rem; word-break: break-all; }
+.button { display: inline-block; margin-top: 1rem; padding: .5rem 1rem; background: #1a73e8; color: #fff; text-decoration: none; border-radius: .25rem; }
+</style>
+</head>
+<body>
+<h1>You are leaving for an external site</h1>
+<p>The short link <code>{{.Alias}}</code> takes you to <strong>{{.Host}}</strong>:</p>
+<p><code>{{.URL}}</code></p>
+<p>Only continue if you trust this destination.</p>
+<a id="continue" class="button" href="{{.URL}}" rel="noopener noreferrer nofollow">Continue</a>
+{{if .Countdown}}<p>Redirecting in <span id="countdown">{{.Countdown}}</span> seconds.</p>
+<script>
+(function () {
+  var left = {{.Countdown}};
+  var counter = document.getElementById("countdown");
+  var timer = setInterval(function () {
+    left--;
+    counter.textContent = left;
+    if (left <= 0) {
+      clearInterval(timer);
+      window.location.href = document.getElementById("continue").href;
+    }
+  }, 1000);
+})();
+</script>{{end}}
+</body>
+</html>
+`))
+
+// interstitialPageData is passed to interstitial templates, including custom ones.
+type interstitialPageData struct {
+	Alias string
+	URL   string
+	Host  string
+	Title string
+	// Countdown is the number of seconds before the automatic redirect, or
+	// zero when it is disabled.
+	Countdown int
+}
+
+// defaultComingSoonPage is shown for links that are not active yet unless a
+// custom template is configured.
+var defaultComingSoonPage = template.Must(template.New("coming-soon").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>Coming soon</title>
+<style>
+body { font-family: sans-serif; max-width: 40rem; margin: 4rem auto; padding: 0 1rem; color: #222; }
+code { background: #f3f3f3; padding: 0 .25rem; }
+</style>
+</head>
+<body>
+<h1>Coming soon</h1>
+<p>The short link <code>{{.Alias}}</code> is not active yet.</p>
+<p>Come back on {{.NotBefore.Format "January 2, 2006 at 15:04 MST"}}.</p>
+</body>
+</html>
+`))
+
+// comingSoonPageData is passed to coming soon templates, including custom ones.
+type comingSoonPageData struct {
+	Alias string
+	// NotBefore is when the link starts redirecting, in UTC.
+	NotBefore time.Time
+}
+
+// LoadInterstitialTemplate parses the html/template file at path to be used as
+// the interstitial page. An empty path returns the built-in page. Templates can
+// use .Alias, .URL, .Host, .Title and .Countdown (seconds, zero when disabled).
+func LoadInterstitialTemplate(path string) (*template.Template, error) {
+	const op = "http-server.handlers.redirect.LoadInterstitialTemplate"
+
+	tmpl, err := loadTemplate(path, defaultInterstitialPage)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tmpl, nil
+}
+
+// LoadComingSoonTemplate parses the html/template file at path to be used as
+// the page for links that are not active yet. An empty path returns the
+// built-in page. Templates can use .Alias and .NotBefore (a time.Time in UTC).
+func LoadComingSoonTemplate(path string) (*template.Template, error) {
+	const op = "http-server.handlers.redirect.LoadComingSoonTemplate"
+
+	tmpl, err := loadTemplate(path, defaultComingSoonPage)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tmpl, nil
+}
+
+// loadTemplate parses the template file at path, or returns fallback for an
+// empty path.
+func loadTemplate(path string, fallback *template.Template) (*template.Template, error) {
+	if path == "" {
+		return fallback, nil
+	}
+
+	return template.New(filepath.Base(path)).ParseFiles(path)
+}
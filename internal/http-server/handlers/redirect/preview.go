@@ -0,0 +1,163 @@
+package redirect
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+	domain "url-shortener/internal/domain/url"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// PreviewResponse is the JSON form of the preview page.
+type PreviewResponse struct {
+	resp.Response
+	Alias     string     `json:"alias"`
+	URL       string     `json:"url"`
+	Title     string     `json:"title,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// NewPreview creates the handler that shows where a short link goes without
+// following it. It renders an HTML page with a continue button, or JSON when
+// the Accept header asks for it. Previews are not counted as clicks. Password
+// protected links are only previewed once unlocked.
+func NewPreview(log *slog.Logger, urlGetter URLGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.redirect.NewPreview"
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.Error("alias parameter is missing")
+			err := resp.RenderJSON(w, http.StatusBadRequest, resp.Error("alias parameter is required"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		wantsJSON := resp.PrefersJSON(r)
+		w.Header().Set("Cache-Control", "no-store")
+
+		redirect, err := urlGetter.RedirectURL(r.Context(), alias, domain.Visit{
+			UserAgent:   r.UserAgent(),
+			ClientIP:    clientAddr(r),
+			AccessToken: accessToken(r),
+		})
+
+		if err != nil {
+			if errors.Is(err, domain.ErrURLNotFound) {
+				log.Info("alias not found", slog.String("alias", alias))
+				err = resp.RenderJSON(w, http.StatusNotFound, resp.Error("not found"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			if errors.Is(err, domain.ErrLinkNotYetActive) {
+				log.Info("link not active yet", slog.String("alias", alias))
+				err = resp.RenderJSON(w, http.StatusNotFound, resp.Error("link is not active yet"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			if errors.Is(err, domain.ErrLinkExpired) || errors.Is(err, domain.ErrLinkExhausted) ||
+				errors.Is(err, domain.ErrSignatureExpired) {
+				log.Info("link expired or click limit reached", slog.String("alias", alias))
+				err = resp.RenderJSON(w, http.StatusGone, resp.Error("link has expired"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			if errors.Is(err, domain.ErrSignatureRequired) {
+				log.Info("valid signature required", slog.String("alias", alias))
+				err = resp.RenderJSON(w, http.StatusForbidden, resp.Error(domain.ErrSignatureRequired.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			if errors.Is(err, domain.ErrPasswordRequired) {
+				log.Info("password required", slog.String("alias", alias))
+				if wantsJSON {
+					err = resp.RenderJSON(w, http.StatusUnauthorized, resp.Error("password required"))
+					if err != nil {
+						log.Error("failed to render JSON response", slog.String("error", err.Error()))
+					}
+					return
+				}
+				// The unlock cookie is scoped to "/{alias}", which covers
+				// "/{alias}/preview" but not "/{alias}+".
+				renderPasswordPage(log, w, http.StatusOK, passwordPageData{
+					Alias:  alias,
+					Action: "/" + url.PathEscape(alias) + "/preview",
+				})
+				return
+			}
+
+			if errors.Is(err, domain.ErrURLBlocked) {
+				log.Warn("destination is blocklisted", slog.String("alias", alias), slog.String("error", err.Error()))
+				if wantsJSON {
+					err = resp.RenderJSON(w, http.StatusForbidden, resp.Error("destination is blocklisted"))
+				} else {
+					err = resp.RenderHTML(w, http.StatusForbidden, blockedPage, blockedPageData{Alias: alias})
+				}
+				if err != nil {
+					log.Error("failed to render response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			log.Error("failed to get url", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("internal error"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		log.Info("previewed", slog.String("alias", alias), slog.String("original_url", redirect.URL))
+
+		if wantsJSON {
+			err = resp.RenderJSON(w, http.StatusOK, PreviewResponse{
+				Response:  resp.OK(),
+				Alias:     alias,
+				URL:       redirect.URL,
+				Title:     redirect.Title,
+				CreatedAt: redirect.CreatedAt,
+			})
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		data := previewPageData{
+			Alias: alias,
+			URL:   redirect.URL,
+			Title: redirect.Title,
+		}
+		if redirect.CreatedAt != nil {
+			data.CreatedAt = redirect.CreatedAt.UTC().Format("January 2, 2006")
+		}
+
+		err = resp.RenderHTML(w, http.StatusOK, previewPage, data)
+		if err != nil {
+			log.Error("failed to render HTML response", slog.String("error", err.Error()))
+		}
+	}
+}
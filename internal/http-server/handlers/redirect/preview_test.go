@@ -0,0 +1,193 @@
+package redirect_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/handlers/redirect"
+	"url-shortener/internal/http-server/handlers/redirect/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreviewHandler(t *testing.T) {
+	createdAt := time.Date(2026, time.March, 14, 9, 30, 0, 0, time.UTC)
+
+	cases := []struct {
+		name        string
+		alias       string
+		accept      string
+		redirect    domain.Redirect
+		mockError   error
+		statusCode  int
+		contentType string
+		contains    []string
+	}{
+		{
+			name:  "HTML page",
+			alias: "docs",
+			redirect: domain.Redirect{
+				URL:        "https://example.com/docs?a=1&b=2",
+				StatusCode: http.StatusFound,
+				Title:      "Team <docs>",
+				CreatedAt:  &createdAt,
+			},
+			statusCode:  http.StatusOK,
+			contentType: "text/html; charset=utf-8",
+			contains: []string{
+				`href="https://example.com/docs?a=1&amp;b=2"`,
+				"Team &lt;docs&gt;",
+				"Created on March 14, 2026.",
+			},
+		},
+		{
+			name:        "Browser Accept header gets HTML",
+			alias:       "docs",
+			accept:      "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			redirect:    domain.Redirect{URL: "https://example.com", StatusCode: http.StatusFound},
+			statusCode:  http.StatusOK,
+			contentType: "text/html; charset=utf-8",
+			contains:    []string{"Where this link goes"},
+		},
+		{
+			name:   "JSON when asked for",
+			alias:  "docs",
+			accept: "application/json",
+			redirect: domain.Redirect{
+				URL:        "https://example.com",
+				StatusCode: http.StatusFound,
+				Title:      "Docs",
+				CreatedAt:  &createdAt,
+			},
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			contains:    []string{`"url":"https://example.com"`, `"title":"Docs"`, `"created_at":"2026-03-14T09:30:00Z"`},
+		},
+		{
+			name:        "HTML preferred by quality",
+			alias:       "docs",
+			accept:      "application/json;q=0.5, text/html",
+			redirect:    domain.Redirect{URL: "https://example.com", StatusCode: http.StatusFound},
+			statusCode:  http.StatusOK,
+			contentType: "text/html; charset=utf-8",
+		},
+		{
+			name:        "Not found",
+			alias:       "missing",
+			mockError:   domain.ErrURLNotFound,
+			statusCode:  http.StatusNotFound,
+			contentType: "application/json",
+		},
+		{
+			name:        "Blocklisted destination",
+			alias:       "phish",
+			mockError:   domain.ErrURLBlocked,
+			statusCode:  http.StatusForbidden,
+			contentType: "text/html; charset=utf-8",
+			contains:    []string{"This link has been blocked"},
+		},
+		{
+			name:        "Blocklisted destination as JSON",
+			alias:       "phish",
+			accept:      "application/json",
+			mockError:   domain.ErrURLBlocked,
+			statusCode:  http.StatusForbidden,
+			contentType: "application/json",
+		},
+		{
+			name:        "Link not active yet",
+			alias:       "launch",
+			mockError:   &domain.NotYetActiveError{NotBefore: createdAt},
+			statusCode:  http.StatusNotFound,
+			contentType: "application/json",
+			contains:    []string{`"error":"link is not active yet"`},
+		},
+		{
+			name:        "Click limit reached",
+			alias:       "once",
+			mockError:   domain.ErrLinkExhausted,
+			statusCode:  http.StatusGone,
+			contentType: "application/json",
+		},
+		{
+			name:        "Signed only link",
+			alias:       "invite",
+			mockError:   domain.ErrSignatureRequired,
+			statusCode:  http.StatusForbidden,
+			contentType: "application/json",
+			contains:    []string{`"error":"valid signature required"`},
+		},
+		{
+			name:        "Password protected link",
+			alias:       "secret",
+			mockError:   domain.ErrPasswordRequired,
+			statusCode:  http.StatusOK,
+			contentType: "text/html; charset=utf-8",
+			contains:    []string{"This link is password protected", `action="/secret/preview"`},
+		},
+		{
+			name:        "Password protected link as JSON",
+			alias:       "secret",
+			accept:      "application/json",
+			mockError:   domain.ErrPasswordRequired,
+			statusCode:  http.StatusUnauthorized,
+			contentType: "application/json",
+			contains:    []string{`"error":"password required"`},
+		},
+		{
+			name:        "Internal error",
+			alias:       "broken",
+			mockError:   errors.New("database error"),
+			statusCode:  http.StatusInternalServerError,
+			contentType: "application/json",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlGetterMock := mocks.NewMockURLGetter(t)
+			urlGetterMock.On("RedirectURL", mock.Anything, tc.alias, domain.Visit{}).
+				Return(tc.redirect, tc.mockError).
+				Once()
+
+			handler := redirect.NewPreview(slog.New(slog.NewTextHandler(io.Discard, nil)), urlGetterMock)
+
+			req, err := http.NewRequest(http.MethodGet, "/"+tc.alias+"+", nil)
+			require.NoError(t, err)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", tc.alias)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+			require.Equal(t, tc.contentType, rr.Header().Get("Content-Type"))
+			require.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+			require.Empty(t, rr.Header().Get("Location"))
+
+			if tc.contentType == "application/json" {
+				require.True(t, json.Valid(rr.Body.Bytes()))
+			}
+			for _, want := range tc.contains {
+				require.Contains(t, rr.Body.String(), want)
+			}
+		})
+	}
+}
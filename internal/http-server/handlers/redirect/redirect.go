@@ -3,9 +3,18 @@ package redirect
 import (
 	"context"
 	"errors"
+	"fmt"
+	"html/template"
 	"log/slog"
+	"math"
 	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/middleware/clientip"
 	resp "url-shortener/internal/lib/api/response"
 	"url-shortener/internal/lib/metrics"
 
@@ -13,11 +22,63 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 )
 
+//go:generate go run github.com/vektra/mockery/v3
 type URLGetter interface {
-	RedirectURL(ctx context.Context, alias string) (string, error)
+	RedirectURL(ctx context.Context, alias string, visit domain.Visit) (domain.Redirect, error)
 }
 
-func New(log *slog.Logger, urlGetter URLGetter) http.HandlerFunc {
+//go:generate go run github.com/vektra/mockery/v3
+type ClickRecorder interface {
+	RecordClick(ctx context.Context, click domain.Click) error
+}
+
+// Options configures the redirect handler.
+type Options struct {
+	// PermanentMaxAge is how long clients may cache permanent (301, 308) redirects.
+	PermanentMaxAge time.Duration
+	// Interstitial is the page served for links marked "warn"; nil uses the
+	// built-in page.
+	Interstitial *template.Template
+	// Countdown redirects from the interstitial page automatically after this
+	// long; zero disables the automatic redirect.
+	Countdown time.Duration
+	// VariantCookieMaxAge is how long the variant of a link with sticky
+	// variants is remembered; zero keeps it for the browser session.
+	VariantCookieMaxAge time.Duration
+	// ComingSoon is the page served for links that are not active yet; nil
+	// uses the built-in page.
+	ComingSoon *template.Template
+	// ComingSoonStatus is the status code of the coming soon page; zero means
+	// 404 Not Found. With 503 Service Unavailable, Retry-After tells clients
+	// when the link becomes active.
+	ComingSoonStatus int
+}
+
+// variantCookie remembers the split variant a visitor got. It is scoped to
+// the path of the link, so every link has its own.
+const variantCookie = "variant"
+
+// New creates the redirect handler. It serves GET and HEAD; HEAD requests get
+// the same response headers but are not counted as clicks; GET requests are
+// recorded with clickRecorder. Links marked "warn"
+// get the interstitial page instead of a redirect. Mounted on "/{alias}/*", the
+// rest of the path and the query are forwarded to passthrough links. Visitors
+// of links with sticky split variants get a cookie remembering their variant.
+// Password protected links show the password form handled by NewUnlock until
+// the visitor has unlocked them. Links that are not active yet get the coming
+// soon page; expired links and links that reached their click limit answer
+// 410 Gone.
+func New(log *slog.Logger, urlGetter URLGetter, clickRecorder ClickRecorder, opts Options) http.HandlerFunc {
+	if opts.Interstitial == nil {
+		opts.Interstitial = defaultInterstitialPage
+	}
+	if opts.ComingSoon == nil {
+		opts.ComingSoon = defaultComingSoonPage
+	}
+	if opts.ComingSoonStatus == 0 {
+		opts.ComingSoonStatus = http.StatusNotFound
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "http-server.handlers.redirect.New"
 		log = log.With(
@@ -35,7 +96,15 @@ func New(log *slog.Logger, urlGetter URLGetter) http.HandlerFunc {
 			return
 		}
 
-		originalURL, err := urlGetter.RedirectURL(r.Context(), alias)
+		redirect, err := urlGetter.RedirectURL(r.Context(), alias, domain.Visit{
+			ExtraPath:   extraPath(r),
+			RawQuery:    r.URL.RawQuery,
+			UserAgent:   r.UserAgent(),
+			ClientIP:    clientAddr(r),
+			Variant:     rememberedVariant(r),
+			AccessToken: accessToken(r),
+			Follow:      r.Method == http.MethodGet,
+		})
 
 		if err != nil {
 			if errors.Is(err, domain.ErrURLNotFound) {
@@ -47,6 +116,85 @@ func New(log *slog.Logger, urlGetter URLGetter) http.HandlerFunc {
 				return
 			}
 
+			var notYetActive *domain.NotYetActiveError
+			if errors.As(err, &notYetActive) {
+				log.Info("link not active yet", slog.String("alias", alias), slog.Time("not_before", notYetActive.NotBefore))
+				w.Header().Set("Cache-Control", "no-store")
+				w.Header().Set("Expires", "0")
+				if opts.ComingSoonStatus == http.StatusServiceUnavailable {
+					retryAfter := max(int(math.Ceil(time.Until(notYetActive.NotBefore).Seconds())), 0)
+					w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				}
+				err = resp.RenderHTML(w, opts.ComingSoonStatus, opts.ComingSoon, comingSoonPageData{
+					Alias:     alias,
+					NotBefore: notYetActive.NotBefore.UTC(),
+				})
+				if err != nil {
+					log.Error("failed to render HTML response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			if errors.Is(err, domain.ErrLinkExpired) {
+				log.Info("link expired", slog.String("alias", alias))
+				err = resp.RenderJSON(w, http.StatusGone, resp.Error("link has expired"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			if errors.Is(err, domain.ErrLinkExhausted) {
+				log.Info("link click limit reached", slog.String("alias", alias))
+				err = resp.RenderJSON(w, http.StatusGone, resp.Error("link has expired"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			if errors.Is(err, domain.ErrSignatureExpired) {
+				log.Info("signature expired", slog.String("alias", alias))
+				err = resp.RenderJSON(w, http.StatusGone, resp.Error("link has expired"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			if errors.Is(err, domain.ErrSignatureRequired) {
+				log.Info("valid signature required", slog.String("alias", alias))
+				err = resp.RenderJSON(w, http.StatusForbidden, resp.Error(domain.ErrSignatureRequired.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			if errors.Is(err, domain.ErrPasswordRequired) {
+				log.Info("password required", slog.String("alias", alias))
+				renderPasswordPage(log, w, http.StatusOK, passwordPageData{Alias: alias})
+				return
+			}
+
+			if errors.Is(err, domain.ErrURLBlocked) {
+				log.Warn("destination is blocklisted", slog.String("alias", alias), slog.String("error", err.Error()))
+				err = resp.RenderHTML(w, http.StatusForbidden, blockedPage, blockedPageData{Alias: alias})
+				if err != nil {
+					log.Error("failed to render HTML response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			if errors.Is(err, domain.ErrInvalidPassthroughRequest) {
+				log.Info("invalid passthrough request", slog.String("alias", alias), slog.String("error", err.Error()))
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("invalid path or query"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
 			if errors.Is(err, domain.ErrInvalidURL) || errors.Is(err, domain.ErrInvalidScheme) {
 				log.Error("invalid url in storage", slog.String("alias", alias), slog.String("error", err.Error()))
 				err = resp.RenderJSON(w, http.StatusNotFound, resp.Error("internal error: invalid url stored"))
@@ -64,9 +212,100 @@ func New(log *slog.Logger, urlGetter URLGetter) http.HandlerFunc {
 			return
 		}
 
-		http.Redirect(w, r, originalURL, http.StatusFound)
-		log.Info("redirected", slog.String("alias", alias), slog.String("original_url", originalURL))
+		if redirect.StickyVariant {
+			http.SetCookie(w, &http.Cookie{
+				Name:     variantCookie,
+				Value:    redirect.Variant,
+				Path:     "/" + url.PathEscape(alias),
+				MaxAge:   int(opts.VariantCookieMaxAge.Seconds()),
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+
+		if redirect.Warn {
+			w.Header().Set("Cache-Control", "no-store")
+			w.Header().Set("Expires", "0")
+			err = resp.RenderHTML(w, http.StatusOK, opts.Interstitial, interstitialPageData{
+				Alias:     alias,
+				URL:       redirect.URL,
+				Host:      hostOf(redirect.URL),
+				Title:     redirect.Title,
+				Countdown: int(opts.Countdown.Seconds()),
+			})
+			if err != nil {
+				log.Error("failed to render HTML response", slog.String("error", err.Error()))
+			}
+		} else {
+			setCacheHeaders(w, redirect, opts.PermanentMaxAge)
+			http.Redirect(w, r, redirect.URL, redirect.StatusCode)
+		}
+
+		if r.Method == http.MethodHead {
+			log.Debug("answered head request", slog.String("alias", alias), slog.String("original_url", redirect.URL))
+			return
+		}
+
+		if redirect.Warn {
+			log.Info("served interstitial", slog.String("alias", alias), slog.String("original_url", redirect.URL))
+		} else {
+			log.Info("redirected", slog.String("alias", alias), slog.String("original_url", redirect.URL))
+		}
 
 		metrics.RedirectsTotal.Inc()
+
+		err = clickRecorder.RecordClick(r.Context(), domain.Click{
+			Alias:      alias,
+			UTMVersion: redirect.UTMVersion,
+			Variant:    redirect.Variant,
+		})
+		if err != nil {
+			log.Error("failed to record click", slog.String("alias", alias), slog.String("error", err.Error()))
+		}
+	}
+}
+
+// extraPath returns the escaped path after the alias segment. It is cut from
+// the request path instead of read from the "*" route parameter, which
+// middleware.URLFormat strips of file extensions such as ".pdf".
+func extraPath(r *http.Request) string {
+	_, path, _ := strings.Cut(strings.TrimPrefix(r.URL.EscapedPath(), "/"), "/")
+	return path
+}
+
+func hostOf(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return parsedURL.Hostname()
+}
+
+// rememberedVariant returns the split variant stored in the variant cookie.
+func rememberedVariant(r *http.Request) string {
+	cookie, err := r.Cookie(variantCookie)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// clientAddr returns the visitor's address, the zero value when unknown.
+func clientAddr(r *http.Request) netip.Addr {
+	addr, _ := clientip.Addr(r)
+	return addr
+}
+
+// setCacheHeaders lets clients cache permanent redirects for maxAge and
+// forbids caching of redirects whose destination may change or depends on the
+// visitor.
+func setCacheHeaders(w http.ResponseWriter, redirect domain.Redirect, maxAge time.Duration) {
+	if domain.IsPermanentRedirect(redirect.StatusCode) && !redirect.Varies && maxAge > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+		w.Header().Set("Expires", time.Now().Add(maxAge).UTC().Format(http.TimeFormat))
+		return
 	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Expires", "0")
 }
@@ -0,0 +1,507 @@
+package redirect_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/handlers/redirect"
+	"url-shortener/internal/http-server/handlers/redirect/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedirectHandler(t *testing.T) {
+	cases := []struct {
+		name         string
+		method       string
+		alias        string
+		redirect     domain.Redirect
+		mockError    error
+		statusCode   int
+		location     string
+		cacheControl string
+	}{
+		{
+			name:         "Temporary redirect is not cached",
+			method:       http.MethodGet,
+			alias:        "temp",
+			redirect:     domain.Redirect{URL: "https://example.com", StatusCode: http.StatusFound, UTMVersion: 3},
+			statusCode:   http.StatusFound,
+			location:     "https://example.com",
+			cacheControl: "no-store",
+		},
+		{
+			name:         "307 redirect is not cached",
+			method:       http.MethodGet,
+			alias:        "temp",
+			redirect:     domain.Redirect{URL: "https://example.com", StatusCode: http.StatusTemporaryRedirect},
+			statusCode:   http.StatusTemporaryRedirect,
+			location:     "https://example.com",
+			cacheControl: "no-store",
+		},
+		{
+			name:         "Permanent redirect is cacheable",
+			method:       http.MethodGet,
+			alias:        "perm",
+			redirect:     domain.Redirect{URL: "https://example.com", StatusCode: http.StatusMovedPermanently},
+			statusCode:   http.StatusMovedPermanently,
+			location:     "https://example.com",
+			cacheControl: "public, max-age=3600",
+		},
+		{
+			name:         "308 redirect is cacheable",
+			method:       http.MethodGet,
+			alias:        "perm",
+			redirect:     domain.Redirect{URL: "https://example.com", StatusCode: http.StatusPermanentRedirect},
+			statusCode:   http.StatusPermanentRedirect,
+			location:     "https://example.com",
+			cacheControl: "public, max-age=3600",
+		},
+		{
+			name:         "Targeted permanent redirect is not cached",
+			method:       http.MethodGet,
+			alias:        "app",
+			redirect:     domain.Redirect{URL: "https://example.com", StatusCode: http.StatusMovedPermanently, Varies: true},
+			statusCode:   http.StatusMovedPermanently,
+			location:     "https://example.com",
+			cacheControl: "no-store",
+		},
+		{
+			name:         "HEAD gets the same headers",
+			method:       http.MethodHead,
+			alias:        "perm",
+			redirect:     domain.Redirect{URL: "https://example.com", StatusCode: http.StatusMovedPermanently},
+			statusCode:   http.StatusMovedPermanently,
+			location:     "https://example.com",
+			cacheControl: "public, max-age=3600",
+		},
+		{
+			name:       "Not found",
+			method:     http.MethodGet,
+			alias:      "missing",
+			mockError:  domain.ErrURLNotFound,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Click limit reached",
+			method:     http.MethodGet,
+			alias:      "once",
+			mockError:  domain.ErrLinkExhausted,
+			statusCode: http.StatusGone,
+		},
+		{
+			name:       "Signed only link without signature",
+			method:     http.MethodGet,
+			alias:      "invite",
+			mockError:  domain.ErrSignatureRequired,
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Signed URL expired",
+			method:     http.MethodGet,
+			alias:      "invite",
+			mockError:  domain.ErrSignatureExpired,
+			statusCode: http.StatusGone,
+		},
+		{
+			name:         "Password protected link shows the form",
+			method:       http.MethodGet,
+			alias:        "secret",
+			mockError:    domain.ErrPasswordRequired,
+			statusCode:   http.StatusOK,
+			cacheControl: "no-store",
+		},
+		{
+			name:       "Blocklisted destination",
+			method:     http.MethodGet,
+			alias:      "phish",
+			mockError:  domain.ErrURLBlocked,
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Internal error",
+			method:     http.MethodGet,
+			alias:      "broken",
+			mockError:  errors.New("database error"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlGetterMock := mocks.NewMockURLGetter(t)
+			urlGetterMock.On("RedirectURL", mock.Anything, tc.alias, domain.Visit{Follow: tc.method == http.MethodGet}).
+				Return(tc.redirect, tc.mockError).
+				Once()
+
+			// Only followed GET redirects count as clicks.
+			clickRecorderMock := mocks.NewMockClickRecorder(t)
+			if tc.mockError == nil && tc.method == http.MethodGet {
+				clickRecorderMock.On("RecordClick", mock.Anything, domain.Click{Alias: tc.alias, UTMVersion: tc.redirect.UTMVersion}).
+					Return(nil).
+					Once()
+			}
+
+			handler := redirect.New(slog.New(slog.NewTextHandler(io.Discard, nil)), urlGetterMock, clickRecorderMock, redirect.Options{
+				PermanentMaxAge: time.Hour,
+			})
+
+			req, err := http.NewRequest(tc.method, "/"+tc.alias, nil)
+			require.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", tc.alias)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+			require.Equal(t, tc.location, rr.Header().Get("Location"))
+
+			if tc.cacheControl != "" {
+				require.Equal(t, tc.cacheControl, rr.Header().Get("Cache-Control"))
+				require.NotEmpty(t, rr.Header().Get("Expires"))
+			}
+		})
+	}
+}
+
+func TestRedirectInterstitial(t *testing.T) {
+	customPath := filepath.Join(t.TempDir(), "theme.html")
+	require.NoError(t, os.WriteFile(customPath, []byte(`<p>Leaving for {{.Host}} in {{.Countdown}}s</p>`), 0o600))
+
+	custom, err := redirect.LoadInterstitialTemplate(customPath)
+	require.NoError(t, err)
+
+	_, err = redirect.LoadInterstitialTemplate(filepath.Join(t.TempDir(), "missing.html"))
+	require.Error(t, err)
+
+	cases := []struct {
+		name     string
+		method   string
+		opts     redirect.Options
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "Built-in page without countdown",
+			method:   http.MethodGet,
+			contains: []string{"You are leaving for an external site", `href="https://external.example/page"`},
+			excludes: []string{"<script>"},
+		},
+		{
+			name:     "Built-in page with countdown",
+			method:   http.MethodGet,
+			opts:     redirect.Options{Countdown: 5 * time.Second},
+			contains: []string{`<span id="countdown">5</span>`, "<script>"},
+		},
+		{
+			name:     "Custom theme",
+			method:   http.MethodGet,
+			opts:     redirect.Options{Interstitial: custom, Countdown: 3 * time.Second},
+			contains: []string{"<p>Leaving for external.example in 3s</p>"},
+		},
+		{
+			name:   "HEAD gets the page headers",
+			method: http.MethodHead,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlGetterMock := mocks.NewMockURLGetter(t)
+			urlGetterMock.On("RedirectURL", mock.Anything, "careful", domain.Visit{Follow: tc.method == http.MethodGet}).
+				Return(domain.Redirect{URL: "https://external.example/page", StatusCode: http.StatusMovedPermanently, Warn: true}, nil).
+				Once()
+
+			clickRecorderMock := mocks.NewMockClickRecorder(t)
+			if tc.method == http.MethodGet {
+				clickRecorderMock.On("RecordClick", mock.Anything, domain.Click{Alias: "careful"}).
+					Return(nil).
+					Once()
+			}
+
+			handler := redirect.New(slog.New(slog.NewTextHandler(io.Discard, nil)), urlGetterMock, clickRecorderMock, tc.opts)
+
+			req, err := http.NewRequest(tc.method, "/careful", nil)
+			require.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", "careful")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusOK, rr.Code)
+			require.Empty(t, rr.Header().Get("Location"))
+			require.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+			require.Equal(t, "text/html; charset=utf-8", rr.Header().Get("Content-Type"))
+
+			for _, want := range tc.contains {
+				require.Contains(t, rr.Body.String(), want)
+			}
+			for _, unwanted := range tc.excludes {
+				require.NotContains(t, rr.Body.String(), unwanted)
+			}
+		})
+	}
+}
+
+func TestRedirectPassthroughRoute(t *testing.T) {
+	cases := []struct {
+		name      string
+		target    string
+		visit     domain.Visit
+		mockError error
+		status    int
+	}{
+		{
+			name:   "Alias only",
+			target: "/promo",
+			visit:  domain.Visit{},
+			status: http.StatusFound,
+		},
+		{
+			name:   "Extra path and query",
+			target: "/promo/extra/path?utm_source=x&q=a%26b",
+			visit:  domain.Visit{ExtraPath: "extra/path", RawQuery: "utm_source=x&q=a%26b"},
+			status: http.StatusFound,
+		},
+		{
+			name:   "Encoded slash is kept escaped",
+			target: "/promo/a%2Fb/c",
+			visit:  domain.Visit{ExtraPath: "a%2Fb/c"},
+			status: http.StatusFound,
+		},
+		{
+			name:   "Space is escaped",
+			target: "/promo/a%20b",
+			visit:  domain.Visit{ExtraPath: "a%20b"},
+			status: http.StatusFound,
+		},
+		{
+			name:   "File extension is kept",
+			target: "/promo/docs/report.pdf",
+			visit:  domain.Visit{ExtraPath: "docs/report.pdf"},
+			status: http.StatusFound,
+		},
+		{
+			name:   "File extension with query is kept",
+			target: "/promo/archive.tar.gz?sig=abc",
+			visit:  domain.Visit{ExtraPath: "archive.tar.gz", RawQuery: "sig=abc"},
+			status: http.StatusFound,
+		},
+		{
+			name:   "Alias with file extension",
+			target: "/promo.json",
+			visit:  domain.Visit{},
+			status: http.StatusFound,
+		},
+		{
+			name:      "Invalid passthrough request",
+			target:    "/promo/%2e%2e/admin",
+			visit:     domain.Visit{ExtraPath: "%2e%2e/admin"},
+			mockError: domain.ErrInvalidPassthroughRequest,
+			status:    http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// httptest requests come from 192.0.2.1.
+			visit := tc.visit
+			visit.ClientIP = netip.MustParseAddr("192.0.2.1")
+			visit.Follow = true
+
+			urlGetterMock := mocks.NewMockURLGetter(t)
+			urlGetterMock.On("RedirectURL", mock.Anything, "promo", visit).
+				Return(domain.Redirect{URL: "https://example.com/", StatusCode: http.StatusFound}, tc.mockError).
+				Once()
+
+			clickRecorderMock := mocks.NewMockClickRecorder(t)
+			if tc.mockError == nil {
+				clickRecorderMock.On("RecordClick", mock.Anything, domain.Click{Alias: "promo"}).
+					Return(nil).
+					Once()
+			}
+
+			handler := redirect.New(slog.New(slog.NewTextHandler(io.Discard, nil)), urlGetterMock, clickRecorderMock, redirect.Options{})
+
+			// The router uses the middleware of the public routes in main.
+			router := chi.NewRouter()
+			router.Use(middleware.URLFormat)
+			router.Get("/{alias}", handler)
+			router.Get("/{alias}/*", handler)
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, tc.target, nil))
+
+			require.Equal(t, tc.status, rr.Code)
+		})
+	}
+}
+
+func TestRedirectVariantCookie(t *testing.T) {
+	cases := []struct {
+		name       string
+		cookie     string
+		redirect   domain.Redirect
+		wantCookie bool
+	}{
+		{
+			name:       "New visitor gets the variant cookie",
+			redirect:   domain.Redirect{URL: "https://example.com/b", StatusCode: http.StatusFound, Varies: true, Variant: "b", StickyVariant: true},
+			wantCookie: true,
+		},
+		{
+			name:     "Returning visitor keeps the variant",
+			cookie:   "b",
+			redirect: domain.Redirect{URL: "https://example.com/b", StatusCode: http.StatusFound, Varies: true, Variant: "b"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlGetterMock := mocks.NewMockURLGetter(t)
+			urlGetterMock.On("RedirectURL", mock.Anything, "split", domain.Visit{ClientIP: netip.MustParseAddr("192.0.2.1"), Variant: tc.cookie, Follow: true}).
+				Return(tc.redirect, nil).
+				Once()
+
+			clickRecorderMock := mocks.NewMockClickRecorder(t)
+			clickRecorderMock.On("RecordClick", mock.Anything, domain.Click{Alias: "split", Variant: "b"}).
+				Return(nil).
+				Once()
+
+			handler := redirect.New(slog.New(slog.NewTextHandler(io.Discard, nil)), urlGetterMock, clickRecorderMock, redirect.Options{VariantCookieMaxAge: time.Hour})
+
+			req := httptest.NewRequest(http.MethodGet, "/split", nil)
+			if tc.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: "variant", Value: tc.cookie})
+			}
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", "split")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusFound, rr.Code)
+			require.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+
+			cookies := rr.Result().Cookies()
+			if !tc.wantCookie {
+				require.Empty(t, cookies)
+				return
+			}
+			require.Len(t, cookies, 1)
+			require.Equal(t, "variant", cookies[0].Name)
+			require.Equal(t, "b", cookies[0].Value)
+			require.Equal(t, "/split", cookies[0].Path)
+			require.Equal(t, 3600, cookies[0].MaxAge)
+			require.True(t, cookies[0].HttpOnly)
+		})
+	}
+}
+
+func TestRedirectActivationWindow(t *testing.T) {
+	notBefore := time.Date(2030, time.May, 1, 9, 0, 0, 0, time.UTC)
+
+	customPath := filepath.Join(t.TempDir(), "soon.html")
+	require.NoError(t, os.WriteFile(customPath, []byte(`<p>{{.Alias}} launches {{.NotBefore.Format "2006-01-02"}}</p>`), 0o600))
+
+	custom, err := redirect.LoadComingSoonTemplate(customPath)
+	require.NoError(t, err)
+
+	_, err = redirect.LoadComingSoonTemplate(filepath.Join(t.TempDir(), "missing.html"))
+	require.Error(t, err)
+
+	cases := []struct {
+		name       string
+		mockError  error
+		opts       redirect.Options
+		statusCode int
+		retryAfter bool
+		contains   string
+	}{
+		{
+			name:       "Built-in coming soon page",
+			mockError:  fmt.Errorf("url.Service.GetRedirectURL: %w", &domain.NotYetActiveError{NotBefore: notBefore}),
+			statusCode: http.StatusNotFound,
+			contains:   "Come back on May 1, 2030 at 09:00 UTC.",
+		},
+		{
+			name:       "Custom page with 503",
+			mockError:  fmt.Errorf("url.Service.GetRedirectURL: %w", &domain.NotYetActiveError{NotBefore: notBefore}),
+			opts:       redirect.Options{ComingSoon: custom, ComingSoonStatus: http.StatusServiceUnavailable},
+			statusCode: http.StatusServiceUnavailable,
+			retryAfter: true,
+			contains:   "<p>launch launches 2030-05-01</p>",
+		},
+		{
+			name:       "Expired link",
+			mockError:  fmt.Errorf("url.Service.GetRedirectURL: %w", domain.ErrLinkExpired),
+			statusCode: http.StatusGone,
+			contains:   `"error":"link has expired"`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlGetterMock := mocks.NewMockURLGetter(t)
+			urlGetterMock.On("RedirectURL", mock.Anything, "launch", mock.Anything).
+				Return(domain.Redirect{}, tc.mockError).
+				Once()
+
+			handler := redirect.New(slog.New(slog.NewTextHandler(io.Discard, nil)), urlGetterMock, mocks.NewMockClickRecorder(t), tc.opts)
+
+			req := httptest.NewRequest(http.MethodGet, "/launch", nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", "launch")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+			require.Contains(t, rr.Body.String(), tc.contains)
+			require.Empty(t, rr.Header().Get("Location"))
+
+			if tc.retryAfter {
+				retryAfter, err := strconv.Atoi(rr.Header().Get("Retry-After"))
+				require.NoError(t, err)
+				require.Greater(t, retryAfter, 0)
+			} else {
+				require.Empty(t, rr.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
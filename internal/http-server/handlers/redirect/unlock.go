@@ -0,0 +1,131 @@
+package redirect
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"time"
+	domain "url-shortener/internal/domain/url"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+//go:generate go run github.com/vektra/mockery/v3
+type Unlocker interface {
+	Unlock(ctx context.Context, alias, password string, clientIP netip.Addr) (domain.AccessGrant, error)
+}
+
+// unlockCookie keeps a password protected link unlocked. It is scoped to the
+// path of the link, so every link has its own.
+const unlockCookie = "unlock"
+
+// maxUnlockFormSize limits the body of the password form.
+const maxUnlockFormSize = 4 << 10
+
+// NewUnlock creates the handler for the password form of protected links,
+// mounted on POST "/{alias}" and "/{alias}/*". A correct password sets a
+// cookie with a signed access token and redirects back to the link with
+// 303 See Other; wrong passwords get the form again, and too many of them
+// 429 Too Many Requests.
+func NewUnlock(log *slog.Logger, unlocker Unlocker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.redirect.NewUnlock"
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.Error("alias parameter is missing")
+			err := resp.RenderJSON(w, http.StatusBadRequest, resp.Error("alias parameter is required"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxUnlockFormSize)
+		if err := r.ParseForm(); err != nil {
+			log.Info("invalid password form", slog.String("alias", alias), slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("invalid form"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		grant, err := unlocker.Unlock(r.Context(), alias, r.PostForm.Get("password"), clientAddr(r))
+		if err != nil {
+			if errors.Is(err, domain.ErrURLNotFound) {
+				log.Info("alias not found", slog.String("alias", alias))
+				err = resp.RenderJSON(w, http.StatusNotFound, resp.Error("not found"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			if errors.Is(err, domain.ErrWrongPassword) {
+				log.Info("wrong password", slog.String("alias", alias))
+				renderPasswordPage(log, w, http.StatusForbidden, passwordPageData{Alias: alias, Error: "Wrong password, please try again."})
+				return
+			}
+
+			var throttled *domain.ThrottledError
+			if errors.As(err, &throttled) {
+				log.Warn("password attempts throttled", slog.String("alias", alias), slog.String("client_ip", clientAddr(r).String()))
+				w.Header().Set("Retry-After", strconv.Itoa(int(throttled.RetryAfter.Round(time.Second).Seconds())))
+				renderPasswordPage(log, w, http.StatusTooManyRequests, passwordPageData{Alias: alias, Error: "Too many wrong passwords, please try again later."})
+				return
+			}
+
+			log.Error("failed to unlock link", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("internal error"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		log.Info("unlocked", slog.String("alias", alias))
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     unlockCookie,
+			Value:    grant.Token,
+			Path:     "/" + url.PathEscape(alias),
+			Expires:  grant.ExpiresAt,
+			MaxAge:   int(time.Until(grant.ExpiresAt).Seconds()),
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		http.Redirect(w, r, r.URL.RequestURI(), http.StatusSeeOther)
+	}
+}
+
+// renderPasswordPage serves the password form of a protected link.
+func renderPasswordPage(log *slog.Logger, w http.ResponseWriter, status int, data passwordPageData) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Expires", "0")
+	err := resp.RenderHTML(w, status, passwordPage, data)
+	if err != nil {
+		log.Error("failed to render HTML response", slog.String("error", err.Error()))
+	}
+}
+
+// accessToken returns the token stored in the unlock cookie.
+func accessToken(r *http.Request) string {
+	cookie, err := r.Cookie(unlockCookie)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
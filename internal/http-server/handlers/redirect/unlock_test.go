@@ -0,0 +1,158 @@
+package redirect_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/handlers/redirect"
+	"url-shortener/internal/http-server/handlers/redirect/mocks"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnlockHandler(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	cases := []struct {
+		name       string
+		target     string
+		password   string
+		grant      domain.AccessGrant
+		mockError  error
+		statusCode int
+		location   string
+		retryAfter string
+		contains   string
+	}{
+		{
+			name:       "Correct password",
+			target:     "/secret",
+			password:   "hunter22",
+			grant:      domain.AccessGrant{Token: "1700000000.sig", ExpiresAt: expiresAt},
+			statusCode: http.StatusSeeOther,
+			location:   "/secret",
+		},
+		{
+			name:       "Redirects back with path and query",
+			target:     "/secret/docs/page?lang=en",
+			password:   "hunter22",
+			grant:      domain.AccessGrant{Token: "1700000000.sig", ExpiresAt: expiresAt},
+			statusCode: http.StatusSeeOther,
+			location:   "/secret/docs/page?lang=en",
+		},
+		{
+			name:       "Wrong password",
+			target:     "/secret",
+			password:   "guess",
+			mockError:  fmt.Errorf("url.Service.Unlock: %w", domain.ErrWrongPassword),
+			statusCode: http.StatusForbidden,
+			contains:   "Wrong password",
+		},
+		{
+			name:       "Too many attempts",
+			target:     "/secret",
+			password:   "guess",
+			mockError:  fmt.Errorf("url.Service.Unlock: %w", &domain.ThrottledError{RetryAfter: 90 * time.Second}),
+			statusCode: http.StatusTooManyRequests,
+			retryAfter: "90",
+			contains:   "Too many wrong passwords",
+		},
+		{
+			name:       "Not found",
+			target:     "/secret",
+			password:   "hunter22",
+			mockError:  domain.ErrURLNotFound,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "Internal error",
+			target:     "/secret",
+			password:   "hunter22",
+			mockError:  errors.New("database error"),
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			unlockerMock := mocks.NewMockUnlocker(t)
+			unlockerMock.On("Unlock", mock.Anything, "secret", tc.password, netip.MustParseAddr("192.0.2.1")).
+				Return(tc.grant, tc.mockError).
+				Once()
+
+			handler := redirect.NewUnlock(slog.New(slog.NewTextHandler(io.Discard, nil)), unlockerMock)
+
+			form := url.Values{"password": {tc.password}}
+			req := httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", "secret")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+			require.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+			require.Equal(t, tc.location, rr.Header().Get("Location"))
+			require.Equal(t, tc.retryAfter, rr.Header().Get("Retry-After"))
+			require.Contains(t, rr.Body.String(), tc.contains)
+
+			cookies := rr.Result().Cookies()
+			if tc.mockError != nil {
+				require.Empty(t, cookies)
+				return
+			}
+			require.Len(t, cookies, 1)
+			require.Equal(t, "unlock", cookies[0].Name)
+			require.Equal(t, tc.grant.Token, cookies[0].Value)
+			require.Equal(t, "/secret", cookies[0].Path)
+			require.True(t, cookies[0].HttpOnly)
+			require.InDelta(t, time.Hour.Seconds(), cookies[0].MaxAge, 5)
+		})
+	}
+}
+
+func TestRedirectUnlockCookie(t *testing.T) {
+	urlGetterMock := mocks.NewMockURLGetter(t)
+	urlGetterMock.On("RedirectURL", mock.Anything, "secret", domain.Visit{ClientIP: netip.MustParseAddr("192.0.2.1"), AccessToken: "1700000000.sig", Follow: true}).
+		Return(domain.Redirect{URL: "https://example.com/internal", StatusCode: http.StatusFound, Varies: true}, nil).
+		Once()
+
+	clickRecorderMock := mocks.NewMockClickRecorder(t)
+	clickRecorderMock.On("RecordClick", mock.Anything, domain.Click{Alias: "secret"}).
+		Return(nil).
+		Once()
+
+	handler := redirect.New(slog.New(slog.NewTextHandler(io.Discard, nil)), urlGetterMock, clickRecorderMock, redirect.Options{})
+
+	req := httptest.NewRequest(http.MethodGet, "/secret", nil)
+	req.AddCookie(&http.Cookie{Name: "unlock", Value: "1700000000.sig"})
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("alias", "secret")
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	require.Equal(t, http.StatusFound, rr.Code)
+	require.Equal(t, "https://example.com/internal", rr.Header().Get("Location"))
+	require.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+}
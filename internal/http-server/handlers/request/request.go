@@ -0,0 +1,96 @@
+// Package request reads the parameters handlers of authenticated routes share,
+// rendering the error response when one is missing or invalid.
+package request
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"url-shortener/internal/http-server/middleware/auth"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// User returns the email and ID of the authenticated user, rendering 500
+// Internal Server Error when the auth middleware did not set them.
+func User(log *slog.Logger, w http.ResponseWriter, r *http.Request) (string, int64, bool) {
+	userEmail, ok := auth.GetEmail(r.Context())
+	if !ok {
+		log.Error("failed to get user email from context")
+		render(log, w, http.StatusInternalServerError, "failed to get user email")
+		return "", 0, false
+	}
+
+	userID, ok := auth.GetUID(r.Context())
+	if !ok {
+		log.Error("failed to get user id from context")
+		render(log, w, http.StatusInternalServerError, "failed to get user id")
+		return "", 0, false
+	}
+
+	return userEmail, userID, true
+}
+
+// Alias returns the "alias" URL parameter, rendering 400 Bad Request when it
+// is empty.
+func Alias(log *slog.Logger, w http.ResponseWriter, r *http.Request) (string, bool) {
+	alias := chi.URLParam(r, "alias")
+	if alias == "" {
+		log.Error("alias parameter is missing")
+		render(log, w, http.StatusBadRequest, "alias parameter is required")
+		return "", false
+	}
+
+	return alias, true
+}
+
+// ID returns the positive "id" URL parameter, rendering 400 Bad Request when
+// it is invalid. name describes the ID in the log and the response, e.g.
+// "folder id".
+func ID(log *slog.Logger, w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id < 1 {
+		log.Info("invalid "+name, slog.String("id", chi.URLParam(r, "id")))
+		render(log, w, http.StatusBadRequest, "invalid "+name)
+		return 0, false
+	}
+
+	return id, true
+}
+
+// UserAndAlias reads the authenticated user and the alias of the request.
+func UserAndAlias(log *slog.Logger, w http.ResponseWriter, r *http.Request) (string, int64, string, bool) {
+	userEmail, userID, ok := User(log, w, r)
+	if !ok {
+		return "", 0, "", false
+	}
+
+	alias, ok := Alias(log, w, r)
+	if !ok {
+		return "", 0, "", false
+	}
+
+	return userEmail, userID, alias, true
+}
+
+// UserAndID reads the authenticated user and the ID of the request.
+func UserAndID(log *slog.Logger, w http.ResponseWriter, r *http.Request, name string) (string, int64, int64, bool) {
+	userEmail, userID, ok := User(log, w, r)
+	if !ok {
+		return "", 0, 0, false
+	}
+
+	id, ok := ID(log, w, r, name)
+	if !ok {
+		return "", 0, 0, false
+	}
+
+	return userEmail, userID, id, true
+}
+
+func render(log *slog.Logger, w http.ResponseWriter, status int, msg string) {
+	if err := resp.RenderJSON(w, status, resp.Error(msg)); err != nil {
+		log.Error("failed to render JSON response", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,99 @@
+package request_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"url-shortener/internal/http-server/handlers/request"
+	"url-shortener/internal/http-server/middleware/auth"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequest(withEmail, withUID bool, params map[string]string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	rctx := chi.NewRouteContext()
+	for key, value := range params {
+		rctx.URLParams.Add(key, value)
+	}
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	if withEmail {
+		ctx = context.WithValue(ctx, auth.ContextKeyEmail, "user@example.com")
+	}
+	if withUID {
+		ctx = context.WithValue(ctx, auth.ContextKeyUID, int64(123))
+	}
+
+	return req.WithContext(ctx)
+}
+
+func TestUserAndAlias(t *testing.T) {
+	cases := []struct {
+		name         string
+		withoutEmail bool
+		withoutUID   bool
+		alias        string
+		ok           bool
+		statusCode   int
+	}{
+		{name: "Success", alias: "docs", ok: true, statusCode: http.StatusOK},
+		{name: "Missing email", withoutEmail: true, alias: "docs", statusCode: http.StatusInternalServerError},
+		{name: "Missing user id", withoutUID: true, alias: "docs", statusCode: http.StatusInternalServerError},
+		{name: "Missing alias", statusCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+			req := newRequest(!tc.withoutEmail, !tc.withoutUID, map[string]string{"alias": tc.alias})
+			rr := httptest.NewRecorder()
+
+			userEmail, userID, alias, ok := request.UserAndAlias(log, rr, req)
+
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.statusCode, rr.Code)
+			if tc.ok {
+				require.Equal(t, "user@example.com", userEmail)
+				require.Equal(t, int64(123), userID)
+				require.Equal(t, tc.alias, alias)
+			}
+		})
+	}
+}
+
+func TestUserAndID(t *testing.T) {
+	cases := []struct {
+		name       string
+		id         string
+		want       int64
+		ok         bool
+		statusCode int
+		body       string
+	}{
+		{name: "Success", id: "42", want: 42, ok: true, statusCode: http.StatusOK},
+		{name: "Not a number", id: "abc", statusCode: http.StatusBadRequest, body: "invalid folder id"},
+		{name: "Zero", id: "0", statusCode: http.StatusBadRequest, body: "invalid folder id"},
+		{name: "Missing", statusCode: http.StatusBadRequest, body: "invalid folder id"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			log := slog.New(slog.NewTextHandler(io.Discard, nil))
+			req := newRequest(true, true, map[string]string{"id": tc.id})
+			rr := httptest.NewRecorder()
+
+			_, _, id, ok := request.UserAndID(log, rr, req, "folder id")
+
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.statusCode, rr.Code)
+			require.Equal(t, tc.want, id)
+			require.Contains(t, rr.Body.String(), tc.body)
+		})
+	}
+}
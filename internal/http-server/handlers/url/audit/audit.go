@@ -0,0 +1,139 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/middleware/auth"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type Response struct {
+	resp.Response
+	Entries []audit.Entry `json:"entries"`
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type AuditLogReader interface {
+	AuditLog(ctx context.Context, requesterID int64, filter audit.Filter) ([]audit.Entry, error)
+}
+
+// New returns a handler that lists audit log entries, newest first. The
+// entries can be filtered by actor, alias and time range with the actor,
+// actor_uid, alias, from and to query parameters; limit and before_id page
+// through the results. Only admins may read the audit log.
+func New(log *slog.Logger, reader AuditLogReader) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.audit.New"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := auth.GetUID(r.Context())
+		if !ok {
+			log.Error("failed to get user id from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user id"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		filter, err := parseFilter(r.URL.Query())
+		if err != nil {
+			log.Info("invalid audit log query", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(err.Error()))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		entries, err := reader.AuditLog(r.Context(), userID, filter)
+		if err != nil {
+			status, msg := http.StatusInternalServerError, "internal error"
+			switch {
+			case errors.Is(err, audit.ErrInvalidFilter):
+				status, msg = http.StatusBadRequest, "invalid filter"
+			case errors.Is(err, domain.ErrPermissionDenied):
+				status, msg = http.StatusForbidden, "permission denied"
+			default:
+				log.Error("failed to read audit log", slog.String("error", err.Error()))
+			}
+			err = resp.RenderJSON(w, status, resp.Error(msg))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		if entries == nil {
+			entries = []audit.Entry{}
+		}
+
+		err = resp.RenderJSON(w, http.StatusOK, Response{
+			Response: resp.OK(),
+			Entries:  entries,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// parseFilter reads the audit log filter from the query parameters.
+func parseFilter(q url.Values) (audit.Filter, error) {
+	filter := audit.Filter{
+		ActorEmail: q.Get("actor"),
+		Alias:      q.Get("alias"),
+		Subject:    q.Get("subject"),
+	}
+
+	var err error
+	if v := q.Get("actor_uid"); v != "" {
+		if filter.ActorUID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return audit.Filter{}, errors.New("invalid actor_uid")
+		}
+	}
+	if v := q.Get("subject_id"); v != "" {
+		if filter.SubjectID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return audit.Filter{}, errors.New("invalid subject_id")
+		}
+	}
+	if v := q.Get("before_id"); v != "" {
+		if filter.BeforeID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return audit.Filter{}, errors.New("invalid before_id")
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			return audit.Filter{}, errors.New("invalid limit")
+		}
+	}
+	if v := q.Get("from"); v != "" {
+		from, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return audit.Filter{}, errors.New("invalid from: must be an RFC 3339 time")
+		}
+		filter.From = &from
+	}
+	if v := q.Get("to"); v != "" {
+		to, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return audit.Filter{}, errors.New("invalid to: must be an RFC 3339 time")
+		}
+		filter.To = &to
+	}
+
+	return filter, nil
+}
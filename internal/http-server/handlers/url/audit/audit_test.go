@@ -0,0 +1,153 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+	handler "url-shortener/internal/http-server/handlers/url/audit"
+	"url-shortener/internal/http-server/handlers/url/audit/mocks"
+	"url-shortener/internal/http-server/middleware/auth"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditHandler(t *testing.T) {
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name        string
+		query       string
+		withoutUID  bool
+		setupMocks  func(reader *mocks.MockAuditLogReader)
+		statusCode  int
+		wantEntries int
+	}{
+		{
+			name:  "Success - All filters",
+			query: "?actor=owner@example.com&actor_uid=7&alias=abc&subject=link&subject_id=0&from=2026-01-01T00:00:00Z&to=2026-02-01T00:00:00Z&limit=10&before_id=50",
+			setupMocks: func(reader *mocks.MockAuditLogReader) {
+				reader.On("AuditLog", mock.Anything, int64(1), audit.Filter{
+					ActorUID:   7,
+					ActorEmail: "owner@example.com",
+					Alias:      "abc",
+					Subject:    audit.SubjectLink,
+					From:       &from,
+					To:         &to,
+					BeforeID:   50,
+					Limit:      10,
+				}).Return([]audit.Entry{
+					{ID: 49, Action: audit.ActionUpdate, Alias: "abc", ActorEmail: "owner@example.com"},
+					{ID: 12, Action: audit.ActionCreate, Alias: "abc", ActorEmail: "owner@example.com"},
+				}, nil).Once()
+			},
+			statusCode:  http.StatusOK,
+			wantEntries: 2,
+		},
+		{
+			name: "Success - No entries",
+			setupMocks: func(reader *mocks.MockAuditLogReader) {
+				reader.On("AuditLog", mock.Anything, int64(1), audit.Filter{}).Return(nil, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Error - Invalid from",
+			query:      "?from=yesterday",
+			setupMocks: func(reader *mocks.MockAuditLogReader) {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Error - Invalid limit",
+			query:      "?limit=many",
+			setupMocks: func(reader *mocks.MockAuditLogReader) {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Error - Invalid actor uid",
+			query:      "?actor_uid=x",
+			setupMocks: func(reader *mocks.MockAuditLogReader) {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Error - Invalid subject id",
+			query:      "?subject=workspace&subject_id=x",
+			setupMocks: func(reader *mocks.MockAuditLogReader) {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "Error - Filter rejected",
+			query: "?limit=5000",
+			setupMocks: func(reader *mocks.MockAuditLogReader) {
+				reader.On("AuditLog", mock.Anything, int64(1), audit.Filter{Limit: 5000}).
+					Return(nil, fmt.Errorf("wrapped: %w", audit.ErrInvalidFilter)).Once()
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Error - Not an admin",
+			setupMocks: func(reader *mocks.MockAuditLogReader) {
+				reader.On("AuditLog", mock.Anything, int64(1), audit.Filter{}).
+					Return(nil, fmt.Errorf("wrapped: %w", domain.ErrPermissionDenied)).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name: "Error - Read fails",
+			setupMocks: func(reader *mocks.MockAuditLogReader) {
+				reader.On("AuditLog", mock.Anything, int64(1), audit.Filter{}).
+					Return(nil, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "Error - Missing user id in context",
+			withoutUID: true,
+			setupMocks: func(reader *mocks.MockAuditLogReader) {},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			readerMock := mocks.NewMockAuditLogReader(t)
+			tc.setupMocks(readerMock)
+
+			h := handler.New(slog.New(slog.NewTextHandler(io.Discard, nil)), readerMock)
+
+			req, err := http.NewRequest(http.MethodGet, "/url/audit"+tc.query, nil)
+			require.NoError(t, err)
+
+			if !tc.withoutUID {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyUID, int64(1)))
+			}
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+
+			var resp handler.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.NotNil(t, resp.Entries)
+			require.Len(t, resp.Entries, tc.wantEntries)
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package folder
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/http-server/handlers/request"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type Request struct {
+	Name string `json:"name"`
+	// WorkspaceID creates the folder in the workspace instead of as a
+	// personal folder; it is ignored on rename.
+	WorkspaceID int64 `json:"workspace_id,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	domain.Folder
+}
+
+type ListResponse struct {
+	resp.Response
+	Folders []domain.Folder `json:"folders"`
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type FolderCreator interface {
+	CreateFolder(ctx context.Context, name string, workspaceID int64, userEmail string, userID int64) (domain.Folder, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type FolderLister interface {
+	Folders(ctx context.Context, workspaceID int64, userEmail string, userID int64) ([]domain.Folder, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type FolderRenamer interface {
+	RenameFolder(ctx context.Context, id int64, name, requesterEmail string, requesterID int64) (domain.Folder, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type FolderDeleter interface {
+	DeleteFolder(ctx context.Context, id int64, requesterEmail string, requesterID int64) error
+}
+
+// New returns a handler that creates a personal folder, or a folder of the
+// workspace given in the request.
+func New(log *slog.Logger, creator FolderCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.folder.New"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, ok := request.User(log, w, r)
+		if !ok {
+			return
+		}
+
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Error("failed to decode request body", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("invalid request body"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		folder, err := creator.CreateFolder(r.Context(), req.Name, req.WorkspaceID, userEmail, userID)
+		if err != nil {
+			renderError(log, w, err, userEmail, "failed to create folder")
+			return
+		}
+
+		log.Info("folder created", slog.Int64("folder_id", folder.ID))
+
+		err = resp.RenderJSON(w, http.StatusOK, Response{
+			Response: resp.OK(),
+			Folder:   folder,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// NewList returns a handler that lists the personal folders of the user, or
+// with the workspace_id query parameter the folders of that workspace.
+func NewList(log *slog.Logger, lister FolderLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.folder.NewList"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, ok := request.User(log, w, r)
+		if !ok {
+			return
+		}
+
+		var workspaceID int64
+		if v := r.URL.Query().Get("workspace_id"); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || id <= 0 {
+				log.Info("invalid workspace id", slog.String("workspace_id", v))
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("invalid workspace_id"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			workspaceID = id
+		}
+
+		folders, err := lister.Folders(r.Context(), workspaceID, userEmail, userID)
+		if err != nil {
+			renderError(log, w, err, userEmail, "failed to list folders")
+			return
+		}
+
+		if folders == nil {
+			folders = []domain.Folder{}
+		}
+
+		err = resp.RenderJSON(w, http.StatusOK, ListResponse{
+			Response: resp.OK(),
+			Folders:  folders,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// NewRename returns a handler that renames a folder.
+func NewRename(log *slog.Logger, renamer FolderRenamer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.folder.NewRename"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, ok := request.User(log, w, r)
+		if !ok {
+			return
+		}
+
+		id, ok := request.ID(log, w, r, "folder id")
+		if !ok {
+			return
+		}
+
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Error("failed to decode request body", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("invalid request body"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		folder, err := renamer.RenameFolder(r.Context(), id, req.Name, userEmail, userID)
+		if err != nil {
+			renderError(log, w, err, userEmail, "failed to rename folder")
+			return
+		}
+
+		log.Info("folder renamed", slog.Int64("folder_id", id))
+
+		err = resp.RenderJSON(w, http.StatusOK, Response{
+			Response: resp.OK(),
+			Folder:   folder,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// NewDelete returns a handler that deletes a folder. Its links are kept.
+func NewDelete(log *slog.Logger, deleter FolderDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.folder.NewDelete"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, ok := request.User(log, w, r)
+		if !ok {
+			return
+		}
+
+		id, ok := request.ID(log, w, r, "folder id")
+		if !ok {
+			return
+		}
+
+		if err := deleter.DeleteFolder(r.Context(), id, userEmail, userID); err != nil {
+			renderError(log, w, err, userEmail, "failed to delete folder")
+			return
+		}
+
+		log.Info("folder deleted", slog.Int64("folder_id", id))
+
+		if err := resp.RenderJSON(w, http.StatusOK, resp.OK()); err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// renderError renders the response for a failed folder request.
+func renderError(log *slog.Logger, w http.ResponseWriter, err error, userEmail, msg string) {
+	status, body := http.StatusInternalServerError, "internal error"
+
+	switch {
+	case errors.Is(err, domain.ErrFolderNotFound):
+		status, body = http.StatusNotFound, domain.ErrFolderNotFound.Error()
+	case errors.Is(err, workspace.ErrWorkspaceNotFound):
+		status, body = http.StatusNotFound, workspace.ErrWorkspaceNotFound.Error()
+	case errors.Is(err, domain.ErrInvalidFolderName):
+		status, body = http.StatusBadRequest, domain.ErrInvalidFolderName.Error()
+	case errors.Is(err, domain.ErrPermissionDenied):
+		log.Info("permission denied", slog.String("user", userEmail))
+		status, body = http.StatusForbidden, "permission denied"
+	default:
+		log.Error(msg, slog.String("error", err.Error()))
+	}
+
+	err = resp.RenderJSON(w, status, resp.Error(body))
+	if err != nil {
+		log.Error("failed to render JSON response", slog.String("error", err.Error()))
+	}
+}
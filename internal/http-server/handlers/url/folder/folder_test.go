@@ -0,0 +1,341 @@
+package folder_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/http-server/handlers/url/folder"
+	"url-shortener/internal/http-server/handlers/url/folder/mocks"
+	"url-shortener/internal/http-server/middleware/auth"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequest(t *testing.T, method, target, id, body string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(method, target, bytes.NewReader([]byte(body)))
+	require.NoError(t, err)
+
+	rctx := chi.NewRouteContext()
+	if id != "" {
+		rctx.URLParams.Add("id", id)
+	}
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, auth.ContextKeyEmail, "owner@example.com")
+	ctx = context.WithValue(ctx, auth.ContextKeyUID, int64(123))
+
+	return req.WithContext(ctx)
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestCreateHandler(t *testing.T) {
+	createdAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name       string
+		body       string
+		setupMocks func(creator *mocks.MockFolderCreator)
+		statusCode int
+		respError  string
+	}{
+		{
+			name: "Success",
+			body: `{"name":"Campaigns"}`,
+			setupMocks: func(creator *mocks.MockFolderCreator) {
+				creator.On("CreateFolder", mock.Anything, "Campaigns", int64(0), "owner@example.com", int64(123)).
+					Return(domain.Folder{ID: 3, Name: "Campaigns", OwnerEmail: "owner@example.com", OwnerUID: 123, CreatedAt: createdAt}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "Success - Workspace folder",
+			body: `{"name":"Campaigns","workspace_id":7}`,
+			setupMocks: func(creator *mocks.MockFolderCreator) {
+				creator.On("CreateFolder", mock.Anything, "Campaigns", int64(7), "owner@example.com", int64(123)).
+					Return(domain.Folder{ID: 3, Name: "Campaigns", WorkspaceID: 7, CreatedAt: createdAt}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Error - Invalid body",
+			body:       `{"name":`,
+			setupMocks: func(creator *mocks.MockFolderCreator) {},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid request body",
+		},
+		{
+			name: "Error - Invalid name",
+			body: `{"name":" "}`,
+			setupMocks: func(creator *mocks.MockFolderCreator) {
+				creator.On("CreateFolder", mock.Anything, " ", int64(0), "owner@example.com", int64(123)).
+					Return(domain.Folder{}, fmt.Errorf("url.Service.CreateFolder: %w", domain.ErrInvalidFolderName)).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  domain.ErrInvalidFolderName.Error(),
+		},
+		{
+			name: "Error - Workspace viewer",
+			body: `{"name":"Campaigns","workspace_id":7}`,
+			setupMocks: func(creator *mocks.MockFolderCreator) {
+				creator.On("CreateFolder", mock.Anything, "Campaigns", int64(7), "owner@example.com", int64(123)).
+					Return(domain.Folder{}, domain.ErrPermissionDenied).Once()
+			},
+			statusCode: http.StatusForbidden,
+			respError:  "permission denied",
+		},
+		{
+			name: "Error - Create fails",
+			body: `{"name":"Campaigns"}`,
+			setupMocks: func(creator *mocks.MockFolderCreator) {
+				creator.On("CreateFolder", mock.Anything, "Campaigns", int64(0), "owner@example.com", int64(123)).
+					Return(domain.Folder{}, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+			respError:  "internal error",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			creatorMock := mocks.NewMockFolderCreator(t)
+			tc.setupMocks(creatorMock)
+
+			rr := httptest.NewRecorder()
+			folder.New(discardLogger(), creatorMock).ServeHTTP(rr, newRequest(t, http.MethodPost, "/url/folders", "", tc.body))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var res folder.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+			require.Equal(t, tc.respError, res.Error)
+
+			if tc.statusCode == http.StatusOK {
+				require.Equal(t, int64(3), res.ID)
+			}
+		})
+	}
+}
+
+func TestListHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		query      string
+		setupMocks func(lister *mocks.MockFolderLister)
+		statusCode int
+		wantCount  int
+	}{
+		{
+			name: "Success",
+			setupMocks: func(lister *mocks.MockFolderLister) {
+				lister.On("Folders", mock.Anything, int64(0), "owner@example.com", int64(123)).
+					Return([]domain.Folder{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+			wantCount:  2,
+		},
+		{
+			name:  "Success - No folders",
+			query: "?workspace_id=7",
+			setupMocks: func(lister *mocks.MockFolderLister) {
+				lister.On("Folders", mock.Anything, int64(7), "owner@example.com", int64(123)).Return(nil, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Error - Invalid workspace id",
+			query:      "?workspace_id=abc",
+			setupMocks: func(lister *mocks.MockFolderLister) {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "Error - Workspace not found",
+			query: "?workspace_id=7",
+			setupMocks: func(lister *mocks.MockFolderLister) {
+				lister.On("Folders", mock.Anything, int64(7), "owner@example.com", int64(123)).
+					Return(nil, workspace.ErrWorkspaceNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			listerMock := mocks.NewMockFolderLister(t)
+			tc.setupMocks(listerMock)
+
+			rr := httptest.NewRecorder()
+			folder.NewList(discardLogger(), listerMock).ServeHTTP(rr, newRequest(t, http.MethodGet, "/url/folders"+tc.query, "", ""))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+
+			var res folder.ListResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+			require.NotNil(t, res.Folders)
+			require.Len(t, res.Folders, tc.wantCount)
+		})
+	}
+}
+
+func TestRenameHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		id         string
+		body       string
+		setupMocks func(renamer *mocks.MockFolderRenamer)
+		statusCode int
+		respError  string
+	}{
+		{
+			name: "Success",
+			id:   "3",
+			body: `{"name":"Launches"}`,
+			setupMocks: func(renamer *mocks.MockFolderRenamer) {
+				renamer.On("RenameFolder", mock.Anything, int64(3), "Launches", "owner@example.com", int64(123)).
+					Return(domain.Folder{ID: 3, Name: "Launches"}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Error - Invalid folder id",
+			id:         "0",
+			body:       `{"name":"Launches"}`,
+			setupMocks: func(renamer *mocks.MockFolderRenamer) {},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid folder id",
+		},
+		{
+			name: "Error - Folder not found",
+			id:   "3",
+			body: `{"name":"Launches"}`,
+			setupMocks: func(renamer *mocks.MockFolderRenamer) {
+				renamer.On("RenameFolder", mock.Anything, int64(3), "Launches", "owner@example.com", int64(123)).
+					Return(domain.Folder{}, fmt.Errorf("url.Service.RenameFolder: %w", domain.ErrFolderNotFound)).Once()
+			},
+			statusCode: http.StatusNotFound,
+			respError:  domain.ErrFolderNotFound.Error(),
+		},
+		{
+			name: "Error - Not owner",
+			id:   "3",
+			body: `{"name":"Launches"}`,
+			setupMocks: func(renamer *mocks.MockFolderRenamer) {
+				renamer.On("RenameFolder", mock.Anything, int64(3), "Launches", "owner@example.com", int64(123)).
+					Return(domain.Folder{}, domain.ErrPermissionDenied).Once()
+			},
+			statusCode: http.StatusForbidden,
+			respError:  "permission denied",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			renamerMock := mocks.NewMockFolderRenamer(t)
+			tc.setupMocks(renamerMock)
+
+			rr := httptest.NewRecorder()
+			folder.NewRename(discardLogger(), renamerMock).ServeHTTP(rr, newRequest(t, http.MethodPatch, "/url/folders/"+tc.id, tc.id, tc.body))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var res folder.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+			require.Equal(t, tc.respError, res.Error)
+
+			if tc.statusCode == http.StatusOK {
+				require.Equal(t, "Launches", res.Name)
+			}
+		})
+	}
+}
+
+func TestDeleteHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		id         string
+		setupMocks func(deleter *mocks.MockFolderDeleter)
+		statusCode int
+		respError  string
+	}{
+		{
+			name: "Success",
+			id:   "3",
+			setupMocks: func(deleter *mocks.MockFolderDeleter) {
+				deleter.On("DeleteFolder", mock.Anything, int64(3), "owner@example.com", int64(123)).Return(nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Error - Invalid folder id",
+			id:         "abc",
+			setupMocks: func(deleter *mocks.MockFolderDeleter) {},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid folder id",
+		},
+		{
+			name: "Error - Folder not found",
+			id:   "3",
+			setupMocks: func(deleter *mocks.MockFolderDeleter) {
+				deleter.On("DeleteFolder", mock.Anything, int64(3), "owner@example.com", int64(123)).
+					Return(domain.ErrFolderNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+			respError:  domain.ErrFolderNotFound.Error(),
+		},
+		{
+			name: "Error - Delete fails",
+			id:   "3",
+			setupMocks: func(deleter *mocks.MockFolderDeleter) {
+				deleter.On("DeleteFolder", mock.Anything, int64(3), "owner@example.com", int64(123)).
+					Return(errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+			respError:  "internal error",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			deleterMock := mocks.NewMockFolderDeleter(t)
+			tc.setupMocks(deleterMock)
+
+			rr := httptest.NewRecorder()
+			folder.NewDelete(discardLogger(), deleterMock).ServeHTTP(rr, newRequest(t, http.MethodDelete, "/url/folders/"+tc.id, tc.id, ""))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var res resp.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+			require.Equal(t, tc.respError, res.Error)
+		})
+	}
+}
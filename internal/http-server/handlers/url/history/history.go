@@ -0,0 +1,144 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/handlers/request"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type Response struct {
+	resp.Response
+	History []domain.DestinationChange `json:"history"`
+}
+
+type RevertResponse struct {
+	resp.Response
+	domain.Link
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type HistoryGetter interface {
+	History(ctx context.Context, alias, requesterEmail string, requesterID int64) ([]domain.DestinationChange, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type URLReverter interface {
+	Revert(ctx context.Context, alias string, changeID int64, requesterEmail string, requesterID int64) (domain.Link, error)
+}
+
+// New returns a handler that lists the destination changes of a link.
+func New(log *slog.Logger, historyGetter HistoryGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.history.New"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, alias, ok := request.UserAndAlias(log, w, r)
+		if !ok {
+			return
+		}
+
+		history, err := historyGetter.History(r.Context(), alias, userEmail, userID)
+		if err != nil {
+			renderError(log, w, err, alias, userEmail, "failed to get url history")
+			return
+		}
+
+		if history == nil {
+			history = []domain.DestinationChange{}
+		}
+
+		err = resp.RenderJSON(w, http.StatusOK, Response{
+			Response: resp.OK(),
+			History:  history,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// NewRevert returns a handler that points a link back to the destination it
+// had before a change.
+func NewRevert(log *slog.Logger, urlReverter URLReverter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.history.NewRevert"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, alias, ok := request.UserAndAlias(log, w, r)
+		if !ok {
+			return
+		}
+
+		changeID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+		if err != nil || changeID < 1 {
+			log.Info("invalid change id", slog.String("id", chi.URLParam(r, "id")))
+			err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("invalid change id"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		link, err := urlReverter.Revert(r.Context(), alias, changeID, userEmail, userID)
+		if err != nil {
+			renderError(log, w, err, alias, userEmail, "failed to revert url")
+			return
+		}
+
+		log.Info("url reverted", slog.String("alias", alias), slog.Int64("change_id", changeID))
+
+		err = resp.RenderJSON(w, http.StatusOK, RevertResponse{
+			Response: resp.OK(),
+			Link:     link,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// renderError renders the response for a failed history request or revert.
+func renderError(log *slog.Logger, w http.ResponseWriter, err error, alias, userEmail, msg string) {
+	status, body := http.StatusInternalServerError, "internal error"
+	var violation *domain.PolicyViolationError
+	switch {
+	case errors.Is(err, domain.ErrURLNotFound):
+		log.Info("url not found", slog.String("alias", alias))
+		status, body = http.StatusNotFound, "not found"
+	case errors.Is(err, domain.ErrChangeNotFound):
+		log.Info("destination change not found", slog.String("alias", alias))
+		status, body = http.StatusNotFound, domain.ErrChangeNotFound.Error()
+	case errors.Is(err, domain.ErrPermissionDenied):
+		log.Info("permission denied", slog.String("alias", alias), slog.String("user", userEmail))
+		status, body = http.StatusForbidden, "permission denied"
+	case errors.As(err, &violation):
+		log.Info("previous destination rejected by policy", slog.String("rule", violation.Rule), slog.String("host", violation.Host))
+		status, body = http.StatusConflict, "previous destination not allowed"
+	case errors.Is(err, domain.ErrURLBlocked):
+		log.Warn("previous destination is blocklisted", slog.String("error", err.Error()))
+		status, body = http.StatusConflict, "previous destination is blocklisted"
+	default:
+		log.Error(msg, slog.String("error", err.Error()))
+	}
+
+	err = resp.RenderJSON(w, status, resp.Error(body))
+	if err != nil {
+		log.Error("failed to render JSON response", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,253 @@
+package history_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/handlers/url/history"
+	"url-shortener/internal/http-server/handlers/url/history/mocks"
+	"url-shortener/internal/http-server/middleware/auth"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequest(t *testing.T, method, alias, id string, withoutEmail, withoutUserID bool) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(method, "/"+alias+"/history", nil)
+	require.NoError(t, err)
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("alias", alias)
+	if id != "" {
+		rctx.URLParams.Add("id", id)
+	}
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	if !withoutEmail {
+		req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyEmail, "owner@example.com"))
+	}
+	if !withoutUserID {
+		req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyUID, int64(123)))
+	}
+
+	return req
+}
+
+func TestHistoryHandler(t *testing.T) {
+	changedAt := time.Date(2026, 4, 2, 10, 0, 0, 0, time.UTC)
+	changes := []domain.DestinationChange{
+		{ID: 2, OldURL: "https://example.com/b", NewURL: "https://example.com/c", ChangedBy: "admin@example.com", ChangedAt: changedAt},
+		{ID: 1, OldURL: "https://example.com/a", NewURL: "https://example.com/b", ChangedBy: "owner@example.com", ChangedAt: changedAt.Add(-time.Hour)},
+	}
+
+	cases := []struct {
+		name          string
+		alias         string
+		withoutEmail  bool
+		withoutUserID bool
+		setupMocks    func(historyGetter *mocks.MockHistoryGetter)
+		statusCode    int
+		wantChanges   int
+	}{
+		{
+			name:  "Success",
+			alias: "test_alias",
+			setupMocks: func(historyGetter *mocks.MockHistoryGetter) {
+				historyGetter.On("History", mock.Anything, "test_alias", "owner@example.com", int64(123)).
+					Return(changes, nil).Once()
+			},
+			statusCode:  http.StatusOK,
+			wantChanges: 2,
+		},
+		{
+			name:  "Success - Never repointed",
+			alias: "test_alias",
+			setupMocks: func(historyGetter *mocks.MockHistoryGetter) {
+				historyGetter.On("History", mock.Anything, "test_alias", "owner@example.com", int64(123)).
+					Return(nil, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Error - URL not found",
+			alias: "missing",
+			setupMocks: func(historyGetter *mocks.MockHistoryGetter) {
+				historyGetter.On("History", mock.Anything, "missing", "owner@example.com", int64(123)).
+					Return(nil, domain.ErrURLNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:  "Error - Not owner and not admin",
+			alias: "test_alias",
+			setupMocks: func(historyGetter *mocks.MockHistoryGetter) {
+				historyGetter.On("History", mock.Anything, "test_alias", "owner@example.com", int64(123)).
+					Return(nil, domain.ErrPermissionDenied).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:  "Error - Internal error",
+			alias: "test_alias",
+			setupMocks: func(historyGetter *mocks.MockHistoryGetter) {
+				historyGetter.On("History", mock.Anything, "test_alias", "owner@example.com", int64(123)).
+					Return(nil, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:         "Error - Missing user email in context",
+			alias:        "test_alias",
+			withoutEmail: true,
+			setupMocks:   func(historyGetter *mocks.MockHistoryGetter) {},
+			statusCode:   http.StatusInternalServerError,
+		},
+		{
+			name:          "Error - Missing user ID in context",
+			alias:         "test_alias",
+			withoutUserID: true,
+			setupMocks:    func(historyGetter *mocks.MockHistoryGetter) {},
+			statusCode:    http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			historyGetterMock := mocks.NewMockHistoryGetter(t)
+			tc.setupMocks(historyGetterMock)
+
+			handler := history.New(slog.New(slog.NewTextHandler(io.Discard, nil)), historyGetterMock)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, newRequest(t, http.MethodGet, tc.alias, "", tc.withoutEmail, tc.withoutUserID))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+
+			var resp history.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.NotNil(t, resp.History)
+			require.Len(t, resp.History, tc.wantChanges)
+			if tc.wantChanges > 0 {
+				require.Equal(t, changes, resp.History)
+			}
+		})
+	}
+}
+
+func TestRevertHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		id         string
+		setupMocks func(urlReverter *mocks.MockURLReverter)
+		statusCode int
+		respError  string
+	}{
+		{
+			name: "Success",
+			id:   "7",
+			setupMocks: func(urlReverter *mocks.MockURLReverter) {
+				urlReverter.On("Revert", mock.Anything, "test_alias", int64(7), "owner@example.com", int64(123)).
+					Return(domain.Link{Alias: "test_alias", URL: "https://example.com/a"}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Error - Invalid change id",
+			id:         "latest",
+			setupMocks: func(urlReverter *mocks.MockURLReverter) {},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid change id",
+		},
+		{
+			name:       "Error - Zero change id",
+			id:         "0",
+			setupMocks: func(urlReverter *mocks.MockURLReverter) {},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid change id",
+		},
+		{
+			name: "Error - Change not found",
+			id:   "99",
+			setupMocks: func(urlReverter *mocks.MockURLReverter) {
+				urlReverter.On("Revert", mock.Anything, "test_alias", int64(99), "owner@example.com", int64(123)).
+					Return(domain.Link{}, fmt.Errorf("url.Service.Revert: %w", domain.ErrChangeNotFound)).Once()
+			},
+			statusCode: http.StatusNotFound,
+			respError:  domain.ErrChangeNotFound.Error(),
+		},
+		{
+			name: "Error - Previous destination blocklisted",
+			id:   "7",
+			setupMocks: func(urlReverter *mocks.MockURLReverter) {
+				urlReverter.On("Revert", mock.Anything, "test_alias", int64(7), "owner@example.com", int64(123)).
+					Return(domain.Link{}, fmt.Errorf("url.Service.Revert: %w: matched %q", domain.ErrURLBlocked, "phish.example")).Once()
+			},
+			statusCode: http.StatusConflict,
+			respError:  "previous destination is blocklisted",
+		},
+		{
+			name: "Error - Previous destination rejected by policy",
+			id:   "7",
+			setupMocks: func(urlReverter *mocks.MockURLReverter) {
+				urlReverter.On("Revert", mock.Anything, "test_alias", int64(7), "owner@example.com", int64(123)).
+					Return(domain.Link{}, fmt.Errorf("url.Service.Revert: %w", &domain.PolicyViolationError{Rule: domain.RulePrivateIP, Host: "10.0.0.1"})).Once()
+			},
+			statusCode: http.StatusConflict,
+			respError:  "previous destination not allowed",
+		},
+		{
+			name: "Error - Not owner and not admin",
+			id:   "7",
+			setupMocks: func(urlReverter *mocks.MockURLReverter) {
+				urlReverter.On("Revert", mock.Anything, "test_alias", int64(7), "owner@example.com", int64(123)).
+					Return(domain.Link{}, domain.ErrPermissionDenied).Once()
+			},
+			statusCode: http.StatusForbidden,
+			respError:  "permission denied",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlReverterMock := mocks.NewMockURLReverter(t)
+			tc.setupMocks(urlReverterMock)
+
+			handler := history.NewRevert(slog.New(slog.NewTextHandler(io.Discard, nil)), urlReverterMock)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, newRequest(t, http.MethodPost, "test_alias", tc.id, false, false))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var resp history.RevertResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			if tc.statusCode != http.StatusOK {
+				require.Equal(t, tc.respError, resp.Error)
+				return
+			}
+
+			require.Equal(t, "https://example.com/a", resp.URL)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/http-server/middleware/auth"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type Response struct {
+	resp.Response
+	URLs []domain.Link `json:"urls"`
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type URLLister interface {
+	List(ctx context.Context, ownerEmail string, ownerID int64, filter domain.ListFilter) ([]domain.Link, error)
+}
+
+// New returns a handler that lists the personal links of the user, or with
+// the workspace_id query parameter the links of that workspace. Repeated tag
+// parameters keep the links that have all of the tags, and folder_id keeps
+// the links in that folder.
+func New(log *slog.Logger, urlLister URLLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.list.New"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, ok := auth.GetEmail(r.Context())
+		if !ok {
+			log.Error("failed to get user email from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user email"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		userID, ok := auth.GetUID(r.Context())
+		if !ok {
+			log.Error("failed to get user id from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user id"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		query := r.URL.Query()
+		filter := domain.ListFilter{Tags: query["tag"]}
+		ids := []struct {
+			param string
+			dst   *int64
+		}{
+			{"workspace_id", &filter.WorkspaceID},
+			{"folder_id", &filter.FolderID},
+		}
+		for _, id := range ids {
+			param, v := id.param, query.Get(id.param)
+			if v == "" {
+				continue
+			}
+			parsed, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || parsed <= 0 {
+				log.Info("invalid id", slog.String(param, v))
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("invalid "+param))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			*id.dst = parsed
+		}
+
+		links, err := urlLister.List(r.Context(), userEmail, userID, filter)
+		if err != nil {
+			status, body := http.StatusInternalServerError, "internal error"
+			switch {
+			case errors.Is(err, domain.ErrInvalidTag):
+				status, body = http.StatusBadRequest, domain.ErrInvalidTag.Error()
+			case errors.Is(err, domain.ErrTooManyTags):
+				status, body = http.StatusBadRequest, domain.ErrTooManyTags.Error()
+			case errors.Is(err, workspace.ErrWorkspaceNotFound):
+				log.Info("workspace not found", slog.Int64("workspace_id", filter.WorkspaceID))
+				status, body = http.StatusNotFound, workspace.ErrWorkspaceNotFound.Error()
+			case errors.Is(err, domain.ErrPermissionDenied):
+				log.Info("permission denied", slog.Int64("workspace_id", filter.WorkspaceID), slog.String("user", userEmail))
+				status, body = http.StatusForbidden, "permission denied"
+			default:
+				log.Error("failed to list urls", slog.String("error", err.Error()))
+			}
+			err = resp.RenderJSON(w, status, resp.Error(body))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		if links == nil {
+			links = []domain.Link{}
+		}
+
+		err = resp.RenderJSON(w, http.StatusOK, Response{
+			Response: resp.OK(),
+			URLs:     links,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package list_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/http-server/handlers/url/list"
+	"url-shortener/internal/http-server/handlers/url/list/mocks"
+	"url-shortener/internal/http-server/middleware/auth"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListHandler(t *testing.T) {
+	checkedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	notBefore := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name         string
+		userEmail    string
+		query        string
+		withoutEmail bool
+		withoutUID   bool
+		setupMocks   func(urlLister *mocks.MockURLLister)
+		statusCode   int
+		wantURLs     int
+	}{
+		{
+			name:      "Success",
+			userEmail: "owner@example.com",
+			setupMocks: func(urlLister *mocks.MockURLLister) {
+				urlLister.On("List", mock.Anything, "owner@example.com", int64(123), domain.ListFilter{}).
+					Return([]domain.Link{
+						{Alias: "a", URL: "https://example.com/a", OwnerEmail: "owner@example.com"},
+						{
+							Alias:      "b",
+							URL:        "https://example.com/b",
+							OwnerEmail: "owner@example.com",
+							Health:     domain.LinkHealth{StatusCode: http.StatusNotFound, CheckedAt: &checkedAt, Broken: true},
+						},
+						{Alias: "c", URL: "https://example.com/c", OwnerEmail: "owner@example.com", NotBefore: &notBefore},
+					}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+			wantURLs:   3,
+		},
+		{
+			name:      "Success - No links",
+			userEmail: "owner@example.com",
+			setupMocks: func(urlLister *mocks.MockURLLister) {
+				urlLister.On("List", mock.Anything, "owner@example.com", int64(123), domain.ListFilter{}).
+					Return(nil, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:      "Error - List fails",
+			userEmail: "owner@example.com",
+			setupMocks: func(urlLister *mocks.MockURLLister) {
+				urlLister.On("List", mock.Anything, "owner@example.com", int64(123), domain.ListFilter{}).
+					Return(nil, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:      "Success - Workspace links",
+			userEmail: "owner@example.com",
+			query:     "?workspace_id=7",
+			setupMocks: func(urlLister *mocks.MockURLLister) {
+				urlLister.On("List", mock.Anything, "owner@example.com", int64(123), domain.ListFilter{WorkspaceID: 7}).
+					Return([]domain.Link{
+						{Alias: "w", URL: "https://example.com/w", OwnerEmail: "colleague@example.com", WorkspaceID: 7},
+					}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+			wantURLs:   1,
+		},
+		{
+			name:       "Error - Invalid workspace id",
+			userEmail:  "owner@example.com",
+			query:      "?workspace_id=abc",
+			setupMocks: func(urlLister *mocks.MockURLLister) {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:      "Error - Workspace not found",
+			userEmail: "owner@example.com",
+			query:     "?workspace_id=7",
+			setupMocks: func(urlLister *mocks.MockURLLister) {
+				urlLister.On("List", mock.Anything, "owner@example.com", int64(123), domain.ListFilter{WorkspaceID: 7}).
+					Return(nil, workspace.ErrWorkspaceNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:      "Error - Not a workspace member",
+			userEmail: "owner@example.com",
+			query:     "?workspace_id=7",
+			setupMocks: func(urlLister *mocks.MockURLLister) {
+				urlLister.On("List", mock.Anything, "owner@example.com", int64(123), domain.ListFilter{WorkspaceID: 7}).
+					Return(nil, domain.ErrPermissionDenied).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:      "Success - Tag and folder filters",
+			userEmail: "owner@example.com",
+			query:     "?tag=launch&tag=promo&folder_id=3",
+			setupMocks: func(urlLister *mocks.MockURLLister) {
+				urlLister.On("List", mock.Anything, "owner@example.com", int64(123),
+					domain.ListFilter{Tags: []string{"launch", "promo"}, FolderID: 3}).
+					Return([]domain.Link{
+						{Alias: "t", URL: "https://example.com/t", Tags: []string{"launch", "promo"}, FolderID: 3},
+					}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+			wantURLs:   1,
+		},
+		{
+			name:       "Error - Invalid folder id",
+			userEmail:  "owner@example.com",
+			query:      "?folder_id=0",
+			setupMocks: func(urlLister *mocks.MockURLLister) {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:      "Error - Invalid tag",
+			userEmail: "owner@example.com",
+			query:     "?tag=no+spaces",
+			setupMocks: func(urlLister *mocks.MockURLLister) {
+				urlLister.On("List", mock.Anything, "owner@example.com", int64(123),
+					domain.ListFilter{Tags: []string{"no spaces"}}).
+					Return(nil, domain.ErrInvalidTag).Once()
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Error - Missing user email in context",
+			withoutEmail: true,
+			setupMocks:   func(urlLister *mocks.MockURLLister) {},
+			statusCode:   http.StatusInternalServerError,
+		},
+		{
+			name:       "Error - Missing user id in context",
+			userEmail:  "owner@example.com",
+			withoutUID: true,
+			setupMocks: func(urlLister *mocks.MockURLLister) {},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlListerMock := mocks.NewMockURLLister(t)
+			tc.setupMocks(urlListerMock)
+
+			handler := list.New(slog.New(slog.NewTextHandler(io.Discard, nil)), urlListerMock)
+
+			req, err := http.NewRequest(http.MethodGet, "/url"+tc.query, nil)
+			require.NoError(t, err)
+
+			if !tc.withoutEmail {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyEmail, tc.userEmail))
+			}
+			if !tc.withoutUID {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyUID, int64(123)))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+
+			var resp list.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.NotNil(t, resp.URLs)
+			require.Len(t, resp.URLs, tc.wantURLs)
+			for _, link := range resp.URLs {
+				if link.Alias == "c" {
+					require.Equal(t, &notBefore, link.NotBefore)
+					require.Nil(t, link.NotAfter)
+				}
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ package mocks
 
 import (
 	"context"
+	domain "url-shortener/internal/domain/url"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -38,8 +39,8 @@ func (_m *MockURLShortener) EXPECT() *MockURLShortener_Expecter {
 }
 
 // Shorten provides a mock function for the type MockURLShortener
-func (_mock *MockURLShortener) Shorten(ctx context.Context, originalURL string, alias string, userEmail string) (string, error) {
-	ret := _mock.Called(ctx, originalURL, alias, userEmail)
+func (_mock *MockURLShortener) Shorten(ctx context.Context, originalURL string, alias string, userEmail string, userID int64, opts domain.ShortenOptions) (string, error) {
+	ret := _mock.Called(ctx, originalURL, alias, userEmail, userID, opts)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Shorten")
@@ -47,16 +48,16 @@ func (_mock *MockURLShortener) Shorten(ctx context.Context, originalURL string,
 
 	var r0 string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) (string, error)); ok {
-		return returnFunc(ctx, originalURL, alias, userEmail)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, int64, domain.ShortenOptions) (string, error)); ok {
+		return returnFunc(ctx, originalURL, alias, userEmail, userID, opts)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
-		r0 = returnFunc(ctx, originalURL, alias, userEmail)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, string, int64, domain.ShortenOptions) string); ok {
+		r0 = returnFunc(ctx, originalURL, alias, userEmail, userID, opts)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
-		r1 = returnFunc(ctx, originalURL, alias, userEmail)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, string, int64, domain.ShortenOptions) error); ok {
+		r1 = returnFunc(ctx, originalURL, alias, userEmail, userID, opts)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -73,11 +74,13 @@ type MockURLShortener_Shorten_Call struct {
 //   - originalURL string
 //   - alias string
 //   - userEmail string
-func (_e *MockURLShortener_Expecter) Shorten(ctx interface{}, originalURL interface{}, alias interface{}, userEmail interface{}) *MockURLShortener_Shorten_Call {
-	return &MockURLShortener_Shorten_Call{Call: _e.mock.On("Shorten", ctx, originalURL, alias, userEmail)}
+//   - userID int64
+//   - opts domain.ShortenOptions
+func (_e *MockURLShortener_Expecter) Shorten(ctx interface{}, originalURL interface{}, alias interface{}, userEmail interface{}, userID interface{}, opts interface{}) *MockURLShortener_Shorten_Call {
+	return &MockURLShortener_Shorten_Call{Call: _e.mock.On("Shorten", ctx, originalURL, alias, userEmail, userID, opts)}
 }
 
-func (_c *MockURLShortener_Shorten_Call) Run(run func(ctx context.Context, originalURL string, alias string, userEmail string)) *MockURLShortener_Shorten_Call {
+func (_c *MockURLShortener_Shorten_Call) Run(run func(ctx context.Context, originalURL string, alias string, userEmail string, userID int64, opts domain.ShortenOptions)) *MockURLShortener_Shorten_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -95,11 +98,21 @@ func (_c *MockURLShortener_Shorten_Call) Run(run func(ctx context.Context, origi
 		if args[3] != nil {
 			arg3 = args[3].(string)
 		}
+		var arg4 int64
+		if args[4] != nil {
+			arg4 = args[4].(int64)
+		}
+		var arg5 domain.ShortenOptions
+		if args[5] != nil {
+			arg5 = args[5].(domain.ShortenOptions)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
 			arg3,
+			arg4,
+			arg5,
 		)
 	})
 	return _c
@@ -110,7 +123,7 @@ func (_c *MockURLShortener_Shorten_Call) Return(s string, err error) *MockURLSho
 	return _c
 }
 
-func (_c *MockURLShortener_Shorten_Call) RunAndReturn(run func(ctx context.Context, originalURL string, alias string, userEmail string) (string, error)) *MockURLShortener_Shorten_Call {
+func (_c *MockURLShortener_Shorten_Call) RunAndReturn(run func(ctx context.Context, originalURL string, alias string, userEmail string, userID int64, opts domain.ShortenOptions) (string, error)) *MockURLShortener_Shorten_Call {
 	_c.Call.Return(run)
 	return _c
 }
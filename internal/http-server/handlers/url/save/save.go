@@ -6,11 +6,12 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"time"
 	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
 	"url-shortener/internal/http-server/middleware/auth"
 
 	resp "url-shortener/internal/lib/api/response"
-	"url-shortener/internal/lib/metrics"
 
 	"github.com/go-chi/chi/v5/middleware"
 )
@@ -18,16 +19,67 @@ import (
 type Request struct {
 	OriginalURL string `json:"original_url" validate:"required"`
 	Alias       string `json:"alias,omitempty"`
+	Dedup       *bool  `json:"dedup,omitempty"`
+	// RedirectType is 301, 302, 307 or 308; empty uses the configured default.
+	RedirectType int `json:"redirect_type,omitempty"`
+	// Title is shown on the link preview page.
+	Title string `json:"title,omitempty"`
+	// Notes are remarks for the owner; they are searchable but not shown to
+	// visitors.
+	Notes string `json:"notes,omitempty"`
+	// Warn shows visitors an interstitial page before redirecting them.
+	Warn bool `json:"warn,omitempty"`
+	// Passthrough forwards extra path segments and query parameters to the
+	// destination: "preserve", "override" or "append"; empty disables it.
+	Passthrough string `json:"passthrough,omitempty"`
+	// UTM is added to the destination at redirect time and can be changed later.
+	UTM *domain.UTMTemplate `json:"utm,omitempty"`
+	// Targeting sends visitors on matching platforms or devices elsewhere;
+	// the first matching rule wins.
+	Targeting []domain.TargetingRule `json:"targeting,omitempty"`
+	// Variants split visitors between several weighted destinations.
+	Variants []domain.Variant `json:"variants,omitempty"`
+	// StickyVariants keeps returning visitors on their first variant.
+	StickyVariants bool `json:"sticky_variants,omitempty"`
+	// Password makes visitors enter it before they are redirected.
+	Password string `json:"password,omitempty"`
+	// MaxClicks makes the link stop working after this many redirects, 1 for
+	// a one-time link; empty means unlimited.
+	MaxClicks int `json:"max_clicks,omitempty"`
+	// NotBefore and NotAfter schedule when the link starts and stops
+	// redirecting, in RFC 3339 format.
+	NotBefore *time.Time `json:"not_before,omitempty"`
+	NotAfter  *time.Time `json:"not_after,omitempty"`
+	// SignedOnly makes the link redirect only through signed URLs.
+	SignedOnly bool `json:"signed_only,omitempty"`
+	// WorkspaceID creates the link in the workspace instead of as a personal link.
+	WorkspaceID int64 `json:"workspace_id,omitempty"`
+	// Tags label the link for filtering the link listing.
+	Tags []string `json:"tags,omitempty"`
+	// FolderID puts the link into a folder of its owner.
+	FolderID int64 `json:"folder_id,omitempty"`
+}
+
+// LogValue keeps the password out of the logs.
+func (r Request) LogValue() slog.Value {
+	type request Request
+	masked := request(r)
+	if masked.Password != "" {
+		masked.Password = "[redacted]"
+	}
+	return slog.AnyValue(masked)
 }
 
 type Response struct {
 	resp.Response
 	Alias string `json:"alias,omitempty"`
+	// Rule names the destination policy rule that rejected the URL.
+	Rule string `json:"rule,omitempty"`
 }
 
 //go:generate go run github.com/vektra/mockery/v3
 type URLShortener interface {
-	Shorten(ctx context.Context, originalURL, alias, userEmail string) (string, error)
+	Shorten(ctx context.Context, originalURL, alias, userEmail string, userID int64, opts domain.ShortenOptions) (string, error)
 }
 
 func New(log *slog.Logger, urlShortener URLShortener) http.HandlerFunc {
@@ -64,9 +116,51 @@ func New(log *slog.Logger, urlShortener URLShortener) http.HandlerFunc {
 			return
 		}
 
-		alias, err := urlShortener.Shorten(r.Context(), req.OriginalURL, req.Alias, ownerEmail)
+		ownerID, ok := auth.GetUID(r.Context())
+		if !ok {
+			log.Error("failed to get owner id from context")
+
+			err = resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get owner id"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		alias, err := urlShortener.Shorten(r.Context(), req.OriginalURL, req.Alias, ownerEmail, ownerID, domain.ShortenOptions{
+			Dedup:          req.Dedup,
+			RedirectType:   req.RedirectType,
+			Title:          req.Title,
+			Notes:          req.Notes,
+			Warn:           req.Warn,
+			Passthrough:    req.Passthrough,
+			UTM:            req.UTM,
+			Targeting:      req.Targeting,
+			Variants:       req.Variants,
+			StickyVariants: req.StickyVariants,
+			Password:       req.Password,
+			MaxClicks:      req.MaxClicks,
+			NotBefore:      req.NotBefore,
+			NotAfter:       req.NotAfter,
+			SignedOnly:     req.SignedOnly,
+			WorkspaceID:    req.WorkspaceID,
+			Tags:           req.Tags,
+			FolderID:       req.FolderID,
+		})
 
 		if err != nil {
+			var violation *domain.PolicyViolationError
+			if errors.As(err, &violation) {
+				log.Info("destination rejected by policy", slog.String("rule", violation.Rule), slog.String("host", violation.Host))
+				err = resp.RenderJSON(w, http.StatusBadRequest, Response{
+					Response: resp.Error("destination not allowed"),
+					Rule:     violation.Rule,
+				})
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
 			if errors.Is(err, domain.ErrInvalidURL) || errors.Is(err, domain.ErrInvalidScheme) {
 				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("invalid URL"))
 				if err != nil {
@@ -75,6 +169,134 @@ func New(log *slog.Logger, urlShortener URLShortener) http.HandlerFunc {
 
 				return
 			}
+			if errors.Is(err, domain.ErrInvalidAlias) {
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(domain.ErrInvalidAlias.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrInvalidRedirectType) {
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(domain.ErrInvalidRedirectType.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrInvalidPassthrough) {
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(domain.ErrInvalidPassthrough.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrInvalidUTM) {
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(domain.ErrInvalidUTM.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrInvalidActivationWindow) {
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(domain.ErrInvalidActivationWindow.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrInvalidMaxClicks) {
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(domain.ErrInvalidMaxClicks.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrSigningDisabled) {
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(domain.ErrSigningDisabled.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrInvalidPassword) {
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(domain.ErrInvalidPassword.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrInvalidVariants) {
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(domain.ErrInvalidVariants.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrInvalidTargeting) {
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(domain.ErrInvalidTargeting.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrTitleTooLong) {
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(domain.ErrTitleTooLong.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrNotesTooLong) {
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(domain.ErrNotesTooLong.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrInvalidTag) {
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(domain.ErrInvalidTag.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrTooManyTags) {
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(domain.ErrTooManyTags.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrURLBlocked) {
+				log.Warn("destination is blocklisted", slog.String("error", err.Error()))
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("destination is blocklisted"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, workspace.ErrWorkspaceNotFound) {
+				err = resp.RenderJSON(w, http.StatusNotFound, resp.Error(workspace.ErrWorkspaceNotFound.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrFolderNotFound) {
+				err = resp.RenderJSON(w, http.StatusNotFound, resp.Error(domain.ErrFolderNotFound.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrPermissionDenied) {
+				log.Info("permission denied", slog.Int64("workspace_id", req.WorkspaceID), slog.String("user", ownerEmail))
+				err = resp.RenderJSON(w, http.StatusForbidden, resp.Error("permission denied"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
 			if errors.Is(err, domain.ErrAliasExists) {
 				err = resp.RenderJSON(w, http.StatusConflict, resp.Error("alias already exists"))
 				if err != nil {
@@ -93,8 +315,6 @@ func New(log *slog.Logger, urlShortener URLShortener) http.HandlerFunc {
 
 		log.Info("url saved successfully", slog.String("alias", alias), slog.String("original_url", req.OriginalURL))
 
-		metrics.URLsCreatedTotal.Inc()
-
 		err = resp.RenderJSON(w, http.StatusOK, Response{
 			Response: resp.OK(),
 			Alias:    alias,
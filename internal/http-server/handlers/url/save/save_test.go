@@ -10,9 +10,12 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
 	"url-shortener/internal/http-server/handlers/url/save"
 	"url-shortener/internal/http-server/handlers/url/save/mocks"
 	"url-shortener/internal/http-server/middleware/auth"
@@ -22,14 +25,37 @@ import (
 )
 
 func TestSaveHandler(t *testing.T) {
+	launchStart := time.Date(2026, time.May, 1, 9, 0, 0, 0, time.UTC)
+	launchEnd := launchStart.Add(7 * 24 * time.Hour)
+
 	cases := []struct {
 		name           string
 		alias          string
 		url            string
 		ownerEmail     string
 		respError      string
+		respRule       string
 		mockError      error
 		mockAlias      string
+		dedup          *bool
+		redirectType   int
+		title          string
+		notes          string
+		warn           bool
+		passthrough    string
+		utm            *domain.UTMTemplate
+		targeting      []domain.TargetingRule
+		variants       []domain.Variant
+		sticky         bool
+		password       string
+		maxClicks      int
+		notBefore      *time.Time
+		notAfter       *time.Time
+		signedOnly     bool
+		workspaceID    int64
+		tags           []string
+		folderID       int64
+		withoutUID     bool
 		statusCode     int
 		shouldCallMock bool
 	}{
@@ -51,6 +77,280 @@ func TestSaveHandler(t *testing.T) {
 			statusCode:     http.StatusOK,
 			shouldCallMock: true,
 		},
+		{
+			name:           "Dedup requested",
+			alias:          "",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			mockAlias:      "existingAlias",
+			dedup:          boolPtr(true),
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Dedup disabled by request",
+			alias:          "",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			mockAlias:      "randomAlias",
+			dedup:          boolPtr(false),
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Permanent redirect",
+			alias:          "perm",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			mockAlias:      "perm",
+			redirectType:   http.StatusMovedPermanently,
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Reserved alias",
+			alias:          "url",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			respError:      domain.ErrInvalidAlias.Error(),
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", domain.ErrInvalidAlias),
+			statusCode:     http.StatusBadRequest,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Invalid redirect type",
+			alias:          "bad",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			redirectType:   http.StatusOK,
+			respError:      domain.ErrInvalidRedirectType.Error(),
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", domain.ErrInvalidRedirectType),
+			statusCode:     http.StatusBadRequest,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Title is passed through",
+			alias:          "titled",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			title:          "Search",
+			mockAlias:      "titled",
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Notes are passed through",
+			alias:          "noted",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			notes:          "Printed on the spring flyer",
+			mockAlias:      "noted",
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Warn flag is passed through",
+			alias:          "careful",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			warn:           true,
+			mockAlias:      "careful",
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Passthrough mode is passed through",
+			alias:          "promo",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			passthrough:    domain.PassthroughOverride,
+			mockAlias:      "promo",
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Invalid passthrough mode",
+			alias:          "promo",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			passthrough:    "merge",
+			respError:      domain.ErrInvalidPassthrough.Error(),
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", domain.ErrInvalidPassthrough),
+			statusCode:     http.StatusBadRequest,
+			shouldCallMock: true,
+		},
+		{
+			name:           "UTM template is passed through",
+			alias:          "campaign",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			utm:            &domain.UTMTemplate{Source: "newsletter", Medium: "email", Campaign: "spring"},
+			mockAlias:      "campaign",
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Invalid UTM template",
+			alias:          "campaign",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			utm:            &domain.UTMTemplate{Source: "a\nb"},
+			respError:      domain.ErrInvalidUTM.Error(),
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", domain.ErrInvalidUTM),
+			statusCode:     http.StatusBadRequest,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Targeting rules are passed through",
+			alias:          "app",
+			url:            "https://example.com/app",
+			ownerEmail:     "test@example.com",
+			targeting:      []domain.TargetingRule{{Platform: "ios", URL: "https://apps.apple.com/app/id1"}},
+			mockAlias:      "app",
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Invalid targeting rule",
+			alias:          "app",
+			url:            "https://example.com/app",
+			ownerEmail:     "test@example.com",
+			targeting:      []domain.TargetingRule{{Platform: "symbian", URL: "https://example.com/old"}},
+			respError:      domain.ErrInvalidTargeting.Error(),
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", domain.ErrInvalidTargeting),
+			statusCode:     http.StatusBadRequest,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Split variants are passed through",
+			alias:          "experiment",
+			url:            "https://example.com/landing",
+			ownerEmail:     "test@example.com",
+			variants:       []domain.Variant{{Name: "a", URL: "https://example.com/a", Weight: 50}, {Name: "b", URL: "https://example.com/b", Weight: 50}},
+			sticky:         true,
+			mockAlias:      "experiment",
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Invalid split variants",
+			alias:          "experiment",
+			url:            "https://example.com/landing",
+			ownerEmail:     "test@example.com",
+			variants:       []domain.Variant{{Name: "a", URL: "https://example.com/a", Weight: 0}},
+			respError:      domain.ErrInvalidVariants.Error(),
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", domain.ErrInvalidVariants),
+			statusCode:     http.StatusBadRequest,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Password is passed through",
+			alias:          "internal_docs",
+			url:            "https://example.com/internal",
+			ownerEmail:     "test@example.com",
+			password:       "correct horse",
+			mockAlias:      "internal_docs",
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Password too short",
+			alias:          "internal_docs",
+			url:            "https://example.com/internal",
+			ownerEmail:     "test@example.com",
+			password:       "abc",
+			respError:      domain.ErrInvalidPassword.Error(),
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", domain.ErrInvalidPassword),
+			statusCode:     http.StatusBadRequest,
+			shouldCallMock: true,
+		},
+		{
+			name:           "One-time link",
+			alias:          "once",
+			url:            "https://example.com/secret",
+			ownerEmail:     "test@example.com",
+			maxClicks:      1,
+			mockAlias:      "once",
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Negative max clicks",
+			alias:          "once",
+			url:            "https://example.com/secret",
+			ownerEmail:     "test@example.com",
+			maxClicks:      -1,
+			respError:      domain.ErrInvalidMaxClicks.Error(),
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", domain.ErrInvalidMaxClicks),
+			statusCode:     http.StatusBadRequest,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Activation window",
+			alias:          "launch",
+			url:            "https://example.com/launch",
+			ownerEmail:     "test@example.com",
+			notBefore:      &launchStart,
+			notAfter:       &launchEnd,
+			mockAlias:      "launch",
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Activation window ends before it starts",
+			alias:          "launch",
+			url:            "https://example.com/launch",
+			ownerEmail:     "test@example.com",
+			notBefore:      &launchEnd,
+			notAfter:       &launchStart,
+			respError:      domain.ErrInvalidActivationWindow.Error(),
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", domain.ErrInvalidActivationWindow),
+			statusCode:     http.StatusBadRequest,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Signed only",
+			alias:          "invite",
+			url:            "https://example.com/invite",
+			ownerEmail:     "test@example.com",
+			signedOnly:     true,
+			mockAlias:      "invite",
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Signed only without signing keys",
+			alias:          "invite",
+			url:            "https://example.com/invite",
+			ownerEmail:     "test@example.com",
+			signedOnly:     true,
+			respError:      domain.ErrSigningDisabled.Error(),
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", domain.ErrSigningDisabled),
+			statusCode:     http.StatusBadRequest,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Title too long",
+			alias:          "titled",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			title:          strings.Repeat("a", domain.MaxTitleLength+1),
+			respError:      domain.ErrTitleTooLong.Error(),
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", domain.ErrTitleTooLong),
+			statusCode:     http.StatusBadRequest,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Notes too long",
+			alias:          "noted",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			notes:          strings.Repeat("a", domain.MaxNotesLength+1),
+			respError:      domain.ErrNotesTooLong.Error(),
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", domain.ErrNotesTooLong),
+			statusCode:     http.StatusBadRequest,
+			shouldCallMock: true,
+		},
 		{
 			name:           "Empty URL",
 			url:            "",
@@ -61,6 +361,27 @@ func TestSaveHandler(t *testing.T) {
 			statusCode:     http.StatusBadRequest,
 			shouldCallMock: true,
 		},
+		{
+			name:           "Destination rejected by policy",
+			url:            "http://127.0.0.1/admin",
+			alias:          "some_alias",
+			ownerEmail:     "test@example.com",
+			respError:      "destination not allowed",
+			respRule:       domain.RulePrivateIP,
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", &domain.PolicyViolationError{Rule: domain.RulePrivateIP, Host: "127.0.0.1"}),
+			statusCode:     http.StatusBadRequest,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Destination blocklisted",
+			url:            "https://phish.example/login",
+			alias:          "some_alias",
+			ownerEmail:     "test@example.com",
+			respError:      "destination is blocklisted",
+			mockError:      fmt.Errorf("url.Service.Shorten: %w: matched %q", domain.ErrURLBlocked, "phish.example"),
+			statusCode:     http.StatusBadRequest,
+			shouldCallMock: true,
+		},
 		{
 			name:           "Shorten Error",
 			alias:          "test_alias",
@@ -80,6 +401,81 @@ func TestSaveHandler(t *testing.T) {
 			statusCode:     http.StatusInternalServerError,
 			shouldCallMock: false,
 		},
+		{
+			name:           "Workspace link",
+			alias:          "team_link",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			workspaceID:    7,
+			mockAlias:      "team_link",
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Workspace not found",
+			alias:          "team_link",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			workspaceID:    7,
+			respError:      workspace.ErrWorkspaceNotFound.Error(),
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", workspace.ErrWorkspaceNotFound),
+			statusCode:     http.StatusNotFound,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Workspace viewer",
+			alias:          "team_link",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			workspaceID:    7,
+			respError:      "permission denied",
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", domain.ErrPermissionDenied),
+			statusCode:     http.StatusForbidden,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Tags and folder",
+			alias:          "tagged",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			tags:           []string{"launch", "promo"},
+			folderID:       3,
+			mockAlias:      "tagged",
+			statusCode:     http.StatusOK,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Invalid tag",
+			alias:          "tagged",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			tags:           []string{"no spaces"},
+			respError:      domain.ErrInvalidTag.Error(),
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", domain.ErrInvalidTag),
+			statusCode:     http.StatusBadRequest,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Folder of someone else",
+			alias:          "tagged",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			folderID:       3,
+			respError:      domain.ErrFolderNotFound.Error(),
+			mockError:      fmt.Errorf("url.Service.Shorten: %w", domain.ErrFolderNotFound),
+			statusCode:     http.StatusNotFound,
+			shouldCallMock: true,
+		},
+		{
+			name:           "Missing owner id",
+			alias:          "test_alias",
+			url:            "https://google.com",
+			ownerEmail:     "test@example.com",
+			withoutUID:     true,
+			respError:      "failed to get owner id",
+			statusCode:     http.StatusInternalServerError,
+			shouldCallMock: false,
+		},
 	}
 
 	for _, tc := range cases {
@@ -89,16 +485,72 @@ func TestSaveHandler(t *testing.T) {
 			urlSaverMock := mocks.NewMockURLShortener(t)
 
 			if tc.shouldCallMock {
-				urlSaverMock.On("Shorten", mock.Anything, tc.url, tc.alias, tc.ownerEmail).
+				urlSaverMock.On("Shorten", mock.Anything, tc.url, tc.alias, tc.ownerEmail, int64(123), domain.ShortenOptions{Dedup: tc.dedup, RedirectType: tc.redirectType, Title: tc.title, Notes: tc.notes, Warn: tc.warn, Passthrough: tc.passthrough, UTM: tc.utm, Targeting: tc.targeting, Variants: tc.variants, StickyVariants: tc.sticky, Password: tc.password, MaxClicks: tc.maxClicks, NotBefore: tc.notBefore, NotAfter: tc.notAfter, SignedOnly: tc.signedOnly, WorkspaceID: tc.workspaceID, Tags: tc.tags, FolderID: tc.folderID}).
 					Return(tc.mockAlias, tc.mockError).
 					Once()
 			}
 
 			handler := save.New(slog.New(slog.NewTextHandler(io.Discard, nil)), urlSaverMock)
 
-			input := fmt.Sprintf(`{"original_url": "%s", "alias": "%s"}`, tc.url, tc.alias)
+			body := map[string]any{"original_url": tc.url, "alias": tc.alias}
+			if tc.dedup != nil {
+				body["dedup"] = *tc.dedup
+			}
+			if tc.redirectType != 0 {
+				body["redirect_type"] = tc.redirectType
+			}
+			if tc.title != "" {
+				body["title"] = tc.title
+			}
+			if tc.notes != "" {
+				body["notes"] = tc.notes
+			}
+			if tc.warn {
+				body["warn"] = true
+			}
+			if tc.passthrough != "" {
+				body["passthrough"] = tc.passthrough
+			}
+			if tc.utm != nil {
+				body["utm"] = tc.utm
+			}
+			if tc.targeting != nil {
+				body["targeting"] = tc.targeting
+			}
+			if tc.variants != nil {
+				body["variants"] = tc.variants
+			}
+			if tc.sticky {
+				body["sticky_variants"] = true
+			}
+			if tc.password != "" {
+				body["password"] = tc.password
+			}
+			if tc.maxClicks != 0 {
+				body["max_clicks"] = tc.maxClicks
+			}
+			if tc.notBefore != nil {
+				body["not_before"] = tc.notBefore
+			}
+			if tc.notAfter != nil {
+				body["not_after"] = tc.notAfter
+			}
+			if tc.signedOnly {
+				body["signed_only"] = true
+			}
+			if tc.workspaceID != 0 {
+				body["workspace_id"] = tc.workspaceID
+			}
+			if tc.tags != nil {
+				body["tags"] = tc.tags
+			}
+			if tc.folderID != 0 {
+				body["folder_id"] = tc.folderID
+			}
+			input, err := json.Marshal(body)
+			require.NoError(t, err)
 
-			req, err := http.NewRequest(http.MethodPost, "/save", bytes.NewReader([]byte(input)))
+			req, err := http.NewRequest(http.MethodPost, "/save", bytes.NewReader(input))
 			require.NoError(t, err)
 
 			// Add authenticated user email to context (only if provided)
@@ -106,20 +558,36 @@ func TestSaveHandler(t *testing.T) {
 				ctx := context.WithValue(req.Context(), auth.ContextKeyEmail, tc.ownerEmail)
 				req = req.WithContext(ctx)
 			}
+			if !tc.withoutUID {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyUID, int64(123)))
+			}
 
 			rr := httptest.NewRecorder()
 			handler.ServeHTTP(rr, req)
 
 			require.Equal(t, tc.statusCode, rr.Code)
 
-			body := rr.Body.String()
-
 			var resp save.Response
 
-			require.NoError(t, json.Unmarshal([]byte(body), &resp))
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
 
 			require.Equal(t, tc.respError, resp.Error)
+			require.Equal(t, tc.respRule, resp.Rule)
 
 		})
 	}
 }
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestRequestLogValueHidesPassword(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, nil))
+
+	log.Info("request decoded", slog.Any("req", save.Request{OriginalURL: "https://example.com", Password: "correct horse"}))
+
+	require.NotContains(t, buf.String(), "correct horse")
+	require.Contains(t, buf.String(), "[redacted]")
+}
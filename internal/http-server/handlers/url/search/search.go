@@ -0,0 +1,131 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/http-server/middleware/auth"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type Response struct {
+	resp.Response
+	domain.SearchPage
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type URLSearcher interface {
+	Search(ctx context.Context, userEmail string, userID int64, search domain.Search) (domain.SearchPage, error)
+}
+
+// New returns a handler that searches the alias, destination URL, title, tags
+// and notes of the user's personal links for the words of the q query
+// parameter, or with workspace_id the links of that workspace. The links are
+// ranked best match first; limit and offset page through them.
+func New(log *slog.Logger, searcher URLSearcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.search.New"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, ok := auth.GetEmail(r.Context())
+		if !ok {
+			log.Error("failed to get user email from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user email"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		userID, ok := auth.GetUID(r.Context())
+		if !ok {
+			log.Error("failed to get user id from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user id"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		search, err := parseSearch(r.URL.Query())
+		if err != nil {
+			log.Info("invalid search query", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(err.Error()))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		page, err := searcher.Search(r.Context(), userEmail, userID, search)
+		if err != nil {
+			status, body := http.StatusInternalServerError, "internal error"
+			switch {
+			case errors.Is(err, domain.ErrInvalidSearchQuery):
+				status, body = http.StatusBadRequest, domain.ErrInvalidSearchQuery.Error()
+			case errors.Is(err, domain.ErrInvalidPage):
+				status, body = http.StatusBadRequest, domain.ErrInvalidPage.Error()
+			case errors.Is(err, workspace.ErrWorkspaceNotFound):
+				log.Info("workspace not found", slog.Int64("workspace_id", search.WorkspaceID))
+				status, body = http.StatusNotFound, workspace.ErrWorkspaceNotFound.Error()
+			case errors.Is(err, domain.ErrPermissionDenied):
+				log.Info("permission denied", slog.Int64("workspace_id", search.WorkspaceID), slog.String("user", userEmail))
+				status, body = http.StatusForbidden, "permission denied"
+			default:
+				log.Error("failed to search urls", slog.String("error", err.Error()))
+			}
+			err = resp.RenderJSON(w, status, resp.Error(body))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		if page.URLs == nil {
+			page.URLs = []domain.Link{}
+		}
+
+		err = resp.RenderJSON(w, http.StatusOK, Response{
+			Response:   resp.OK(),
+			SearchPage: page,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// parseSearch reads the search from the query parameters.
+func parseSearch(q url.Values) (domain.Search, error) {
+	search := domain.Search{Query: q.Get("q")}
+
+	var err error
+	if v := q.Get("workspace_id"); v != "" {
+		if search.WorkspaceID, err = strconv.ParseInt(v, 10, 64); err != nil || search.WorkspaceID <= 0 {
+			return domain.Search{}, errors.New("invalid workspace_id")
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if search.Limit, err = strconv.Atoi(v); err != nil {
+			return domain.Search{}, errors.New("invalid limit")
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		if search.Offset, err = strconv.Atoi(v); err != nil {
+			return domain.Search{}, errors.New("invalid offset")
+		}
+	}
+
+	return search, nil
+}
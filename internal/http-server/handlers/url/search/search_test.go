@@ -0,0 +1,183 @@
+package search_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/http-server/handlers/url/search"
+	"url-shortener/internal/http-server/handlers/url/search/mocks"
+	"url-shortener/internal/http-server/middleware/auth"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchHandler(t *testing.T) {
+	cases := []struct {
+		name           string
+		query          string
+		withoutEmail   bool
+		withoutUID     bool
+		setupMocks     func(searcher *mocks.MockURLSearcher)
+		statusCode     int
+		respError      string
+		wantURLs       int
+		wantNextOffset int
+	}{
+		{
+			name:  "Success",
+			query: "?q=spring+launch",
+			setupMocks: func(searcher *mocks.MockURLSearcher) {
+				searcher.On("Search", mock.Anything, "owner@example.com", int64(123), domain.Search{Query: "spring launch"}).
+					Return(domain.SearchPage{URLs: []domain.Link{{Alias: "launch"}, {Alias: "spring"}}}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+			wantURLs:   2,
+		},
+		{
+			name:  "Success - Next page",
+			query: "?q=launch&workspace_id=7&limit=1&offset=2",
+			setupMocks: func(searcher *mocks.MockURLSearcher) {
+				searcher.On("Search", mock.Anything, "owner@example.com", int64(123), domain.Search{Query: "launch", WorkspaceID: 7, Limit: 1, Offset: 2}).
+					Return(domain.SearchPage{URLs: []domain.Link{{Alias: "launch"}}, NextOffset: 3}, nil).Once()
+			},
+			statusCode:     http.StatusOK,
+			wantURLs:       1,
+			wantNextOffset: 3,
+		},
+		{
+			name:  "Success - No matches",
+			query: "?q=nothing",
+			setupMocks: func(searcher *mocks.MockURLSearcher) {
+				searcher.On("Search", mock.Anything, "owner@example.com", int64(123), domain.Search{Query: "nothing"}).
+					Return(domain.SearchPage{}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Error - Invalid limit",
+			query:      "?q=launch&limit=ten",
+			setupMocks: func(searcher *mocks.MockURLSearcher) {},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid limit",
+		},
+		{
+			name:       "Error - Invalid workspace id",
+			query:      "?q=launch&workspace_id=0",
+			setupMocks: func(searcher *mocks.MockURLSearcher) {},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid workspace_id",
+		},
+		{
+			name:  "Error - Empty query",
+			query: "?q=",
+			setupMocks: func(searcher *mocks.MockURLSearcher) {
+				searcher.On("Search", mock.Anything, "owner@example.com", int64(123), domain.Search{}).
+					Return(domain.SearchPage{}, fmt.Errorf("url.Service.Search: %w", domain.ErrInvalidSearchQuery)).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  domain.ErrInvalidSearchQuery.Error(),
+		},
+		{
+			name:  "Error - Limit out of range",
+			query: "?q=launch&limit=1000",
+			setupMocks: func(searcher *mocks.MockURLSearcher) {
+				searcher.On("Search", mock.Anything, "owner@example.com", int64(123), domain.Search{Query: "launch", Limit: 1000}).
+					Return(domain.SearchPage{}, fmt.Errorf("url.Service.Search: %w", domain.ErrInvalidPage)).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  domain.ErrInvalidPage.Error(),
+		},
+		{
+			name:  "Error - Workspace not found",
+			query: "?q=launch&workspace_id=7",
+			setupMocks: func(searcher *mocks.MockURLSearcher) {
+				searcher.On("Search", mock.Anything, "owner@example.com", int64(123), domain.Search{Query: "launch", WorkspaceID: 7}).
+					Return(domain.SearchPage{}, workspace.ErrWorkspaceNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+			respError:  workspace.ErrWorkspaceNotFound.Error(),
+		},
+		{
+			name:  "Error - Not a workspace member",
+			query: "?q=launch&workspace_id=7",
+			setupMocks: func(searcher *mocks.MockURLSearcher) {
+				searcher.On("Search", mock.Anything, "owner@example.com", int64(123), domain.Search{Query: "launch", WorkspaceID: 7}).
+					Return(domain.SearchPage{}, domain.ErrPermissionDenied).Once()
+			},
+			statusCode: http.StatusForbidden,
+			respError:  "permission denied",
+		},
+		{
+			name:  "Error - Search fails",
+			query: "?q=launch",
+			setupMocks: func(searcher *mocks.MockURLSearcher) {
+				searcher.On("Search", mock.Anything, "owner@example.com", int64(123), domain.Search{Query: "launch"}).
+					Return(domain.SearchPage{}, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+			respError:  "internal error",
+		},
+		{
+			name:         "Error - Missing user email in context",
+			query:        "?q=launch",
+			withoutEmail: true,
+			setupMocks:   func(searcher *mocks.MockURLSearcher) {},
+			statusCode:   http.StatusInternalServerError,
+			respError:    "failed to get user email",
+		},
+		{
+			name:       "Error - Missing user id in context",
+			query:      "?q=launch",
+			withoutUID: true,
+			setupMocks: func(searcher *mocks.MockURLSearcher) {},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to get user id",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			searcherMock := mocks.NewMockURLSearcher(t)
+			tc.setupMocks(searcherMock)
+
+			handler := search.New(slog.New(slog.NewTextHandler(io.Discard, nil)), searcherMock)
+
+			req, err := http.NewRequest(http.MethodGet, "/url/search"+tc.query, nil)
+			require.NoError(t, err)
+
+			if !tc.withoutEmail {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyEmail, "owner@example.com"))
+			}
+			if !tc.withoutUID {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyUID, int64(123)))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var res search.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+			require.Equal(t, tc.respError, res.Error)
+
+			if tc.statusCode == http.StatusOK {
+				require.NotNil(t, res.URLs)
+				require.Len(t, res.URLs, tc.wantURLs)
+				require.Equal(t, tc.wantNextOffset, res.NextOffset)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package sign
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/middleware/auth"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Request describes the signed URL to mint; all fields are optional.
+type Request struct {
+	// ExpiresAt is when the URL stops working, in RFC 3339 format; empty
+	// uses the configured default lifetime.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// Path is appended to the alias, escaped as in the URL, without a
+	// leading slash.
+	Path string `json:"path,omitempty"`
+	// Query is the encoded query string the URL carries besides the signature.
+	Query string `json:"query,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	URL       string     `json:"url,omitempty"`
+	KeyID     string     `json:"key_id,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type URLSigner interface {
+	SignURL(ctx context.Context, alias, requesterEmail string, requesterID int64, opts domain.SignOptions) (domain.SignedURL, error)
+}
+
+func New(log *slog.Logger, urlSigner URLSigner) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.sign.New"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, ok := auth.GetEmail(r.Context())
+		if !ok {
+			log.Error("failed to get user email from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user email"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		userID, ok := auth.GetUID(r.Context())
+		if !ok {
+			log.Error("failed to get user id from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user id"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.Error("alias parameter is missing")
+			err := resp.RenderJSON(w, http.StatusBadRequest, resp.Error("alias parameter is required"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		var req Request
+
+		// An empty body mints a URL with the defaults.
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil && !errors.Is(err, io.EOF) {
+			log.Error("failed to decode request body", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("invalid request body"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		signed, err := urlSigner.SignURL(r.Context(), alias, userEmail, userID, domain.SignOptions{
+			ExpiresAt: req.ExpiresAt,
+			ExtraPath: req.Path,
+			RawQuery:  req.Query,
+		})
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidSignatureExpiry) || errors.Is(err, domain.ErrInvalidSignedPath) {
+				log.Info("invalid sign request", slog.String("alias", alias), slog.String("error", err.Error()))
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(validationMessage(err)))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrSigningDisabled) {
+				log.Info("signed urls are not configured")
+				err = resp.RenderJSON(w, http.StatusNotImplemented, resp.Error(domain.ErrSigningDisabled.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrURLNotFound) {
+				log.Info("url not found", slog.String("alias", alias))
+				err = resp.RenderJSON(w, http.StatusNotFound, resp.Error("not found"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrPermissionDenied) {
+				log.Info("permission denied", slog.String("alias", alias), slog.String("user", userEmail))
+				err = resp.RenderJSON(w, http.StatusForbidden, resp.Error("permission denied"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			log.Error("failed to sign url", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("internal error"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		log.Info("url signed", slog.String("alias", alias), slog.String("key_id", signed.KeyID))
+
+		err = resp.RenderJSON(w, http.StatusOK, Response{
+			Response:  resp.OK(),
+			URL:       signed.URL,
+			KeyID:     signed.KeyID,
+			ExpiresAt: &signed.ExpiresAt,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// validationMessage returns the client-facing message for a rejected request.
+func validationMessage(err error) string {
+	for _, known := range []error{domain.ErrInvalidSignatureExpiry, domain.ErrInvalidSignedPath} {
+		if errors.Is(err, known) {
+			return known.Error()
+		}
+	}
+	return "invalid request"
+}
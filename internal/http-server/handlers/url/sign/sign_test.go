@@ -0,0 +1,200 @@
+package sign_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/handlers/url/sign"
+	"url-shortener/internal/http-server/handlers/url/sign/mocks"
+	"url-shortener/internal/http-server/middleware/auth"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignHandler(t *testing.T) {
+	expiresAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	signed := domain.SignedURL{
+		URL:       "https://sho.rt/test_alias?sig_exp=1777636800&sig_kid=k1&sig=abc",
+		KeyID:     "k1",
+		ExpiresAt: expiresAt,
+	}
+
+	cases := []struct {
+		name          string
+		alias         string
+		body          string
+		withoutEmail  bool
+		withoutUserID bool
+		setupMocks    func(urlSigner *mocks.MockURLSigner)
+		statusCode    int
+		respError     string
+	}{
+		{
+			name:  "Success with defaults",
+			alias: "test_alias",
+			setupMocks: func(urlSigner *mocks.MockURLSigner) {
+				urlSigner.On("SignURL", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.SignOptions{}).
+					Return(signed, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Success with options",
+			alias: "test_alias",
+			body:  `{"expires_at":"2026-05-01T12:00:00Z","path":"docs/intro","query":"lang=en"}`,
+			setupMocks: func(urlSigner *mocks.MockURLSigner) {
+				urlSigner.On("SignURL", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.SignOptions{
+					ExpiresAt: &expiresAt,
+					ExtraPath: "docs/intro",
+					RawQuery:  "lang=en",
+				}).Return(signed, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Error - Invalid body",
+			alias:      "test_alias",
+			body:       `{"expires_at":"tomorrow"}`,
+			setupMocks: func(urlSigner *mocks.MockURLSigner) {},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid request body",
+		},
+		{
+			name:  "Error - Invalid expiry",
+			alias: "test_alias",
+			setupMocks: func(urlSigner *mocks.MockURLSigner) {
+				urlSigner.On("SignURL", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.SignOptions{}).
+					Return(domain.SignedURL{}, domain.ErrInvalidSignatureExpiry).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  domain.ErrInvalidSignatureExpiry.Error(),
+		},
+		{
+			name:  "Error - Invalid path",
+			alias: "test_alias",
+			body:  `{"path":"/docs"}`,
+			setupMocks: func(urlSigner *mocks.MockURLSigner) {
+				urlSigner.On("SignURL", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.SignOptions{ExtraPath: "/docs"}).
+					Return(domain.SignedURL{}, domain.ErrInvalidSignedPath).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  domain.ErrInvalidSignedPath.Error(),
+		},
+		{
+			name:  "Error - Signing disabled",
+			alias: "test_alias",
+			setupMocks: func(urlSigner *mocks.MockURLSigner) {
+				urlSigner.On("SignURL", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.SignOptions{}).
+					Return(domain.SignedURL{}, domain.ErrSigningDisabled).Once()
+			},
+			statusCode: http.StatusNotImplemented,
+			respError:  domain.ErrSigningDisabled.Error(),
+		},
+		{
+			name:  "Error - URL not found",
+			alias: "nonexistent",
+			setupMocks: func(urlSigner *mocks.MockURLSigner) {
+				urlSigner.On("SignURL", mock.Anything, "nonexistent", "owner@example.com", int64(123), domain.SignOptions{}).
+					Return(domain.SignedURL{}, domain.ErrURLNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+			respError:  "not found",
+		},
+		{
+			name:  "Error - Not owner and not admin",
+			alias: "test_alias",
+			setupMocks: func(urlSigner *mocks.MockURLSigner) {
+				urlSigner.On("SignURL", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.SignOptions{}).
+					Return(domain.SignedURL{}, domain.ErrPermissionDenied).Once()
+			},
+			statusCode: http.StatusForbidden,
+			respError:  "permission denied",
+		},
+		{
+			name:  "Error - Internal error",
+			alias: "test_alias",
+			setupMocks: func(urlSigner *mocks.MockURLSigner) {
+				urlSigner.On("SignURL", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.SignOptions{}).
+					Return(domain.SignedURL{}, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+			respError:  "internal error",
+		},
+		{
+			name:         "Error - Missing user email in context",
+			alias:        "test_alias",
+			withoutEmail: true,
+			setupMocks:   func(urlSigner *mocks.MockURLSigner) {},
+			statusCode:   http.StatusInternalServerError,
+		},
+		{
+			name:          "Error - Missing user ID in context",
+			alias:         "test_alias",
+			withoutUserID: true,
+			setupMocks:    func(urlSigner *mocks.MockURLSigner) {},
+			statusCode:    http.StatusInternalServerError,
+		},
+		{
+			name:       "Error - Empty alias parameter",
+			alias:      "",
+			setupMocks: func(urlSigner *mocks.MockURLSigner) {},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlSignerMock := mocks.NewMockURLSigner(t)
+			tc.setupMocks(urlSignerMock)
+
+			handler := sign.New(slog.New(slog.NewTextHandler(io.Discard, nil)), urlSignerMock)
+
+			req, err := http.NewRequest(http.MethodPost, "/url/"+tc.alias+"/sign", strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", tc.alias)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			if !tc.withoutEmail {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyEmail, "owner@example.com"))
+			}
+			if !tc.withoutUserID {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyUID, int64(123)))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var resp sign.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			if tc.statusCode != http.StatusOK {
+				if tc.respError != "" {
+					require.Equal(t, tc.respError, resp.Error)
+				}
+				return
+			}
+
+			require.Equal(t, signed.URL, resp.URL)
+			require.Equal(t, signed.KeyID, resp.KeyID)
+			require.NotNil(t, resp.ExpiresAt)
+			require.True(t, signed.ExpiresAt.Equal(*resp.ExpiresAt))
+		})
+	}
+}
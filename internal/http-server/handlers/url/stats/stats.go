@@ -0,0 +1,100 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/middleware/auth"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type Response struct {
+	resp.Response
+	domain.LinkStats
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type StatsGetter interface {
+	Stats(ctx context.Context, alias, requesterEmail string, requesterID int64) (domain.LinkStats, error)
+}
+
+func New(log *slog.Logger, statsGetter StatsGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.stats.New"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, ok := auth.GetEmail(r.Context())
+		if !ok {
+			log.Error("failed to get user email from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user email"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		userID, ok := auth.GetUID(r.Context())
+		if !ok {
+			log.Error("failed to get user id from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user id"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.Error("alias parameter is missing")
+			err := resp.RenderJSON(w, http.StatusBadRequest, resp.Error("alias parameter is required"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		stats, err := statsGetter.Stats(r.Context(), alias, userEmail, userID)
+		if err != nil {
+			if errors.Is(err, domain.ErrURLNotFound) {
+				log.Info("url not found", slog.String("alias", alias))
+				err = resp.RenderJSON(w, http.StatusNotFound, resp.Error("not found"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrPermissionDenied) {
+				log.Info("permission denied", slog.String("alias", alias), slog.String("user", userEmail))
+				err = resp.RenderJSON(w, http.StatusForbidden, resp.Error("permission denied"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			log.Error("failed to get url stats", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("internal error"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		err = resp.RenderJSON(w, http.StatusOK, Response{
+			Response:  resp.OK(),
+			LinkStats: stats,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package stats_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/handlers/url/stats"
+	"url-shortener/internal/http-server/handlers/url/stats/mocks"
+	"url-shortener/internal/http-server/middleware/auth"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsHandler(t *testing.T) {
+	checkedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	notBefore := time.Date(2025, 2, 1, 9, 0, 0, 0, time.UTC)
+	notAfter := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	link := domain.LinkStats{
+		Link: domain.Link{
+			Alias:      "test_alias",
+			URL:        "https://example.com",
+			OwnerEmail: "owner@example.com",
+			UTMVersion: 2,
+			NotBefore:  &notBefore,
+			NotAfter:   &notAfter,
+			Health:     domain.LinkHealth{StatusCode: http.StatusOK, CheckedAt: &checkedAt},
+		},
+		Clicks: 5,
+		UTMVersions: []domain.UTMVersion{
+			{Version: 1, Template: domain.UTMTemplate{Source: "newsletter"}, Clicks: 3},
+			{Version: 2, Template: domain.UTMTemplate{Source: "twitter"}, Clicks: 2},
+		},
+		VariantClicks: []domain.VariantStats{
+			{Variant: domain.Variant{Name: "a", URL: "https://example.com/a", Weight: 1}, Clicks: 4},
+			{Variant: domain.Variant{Name: "old"}, Clicks: 1},
+		},
+	}
+
+	cases := []struct {
+		name          string
+		alias         string
+		userEmail     string
+		userID        int64
+		withoutEmail  bool
+		withoutUserID bool
+		setupMocks    func(statsGetter *mocks.MockStatsGetter)
+		statusCode    int
+	}{
+		{
+			name:      "Success",
+			alias:     "test_alias",
+			userEmail: "owner@example.com",
+			userID:    123,
+			setupMocks: func(statsGetter *mocks.MockStatsGetter) {
+				statsGetter.On("Stats", mock.Anything, "test_alias", "owner@example.com", int64(123)).
+					Return(link, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:      "Error - URL not found",
+			alias:     "nonexistent",
+			userEmail: "owner@example.com",
+			userID:    123,
+			setupMocks: func(statsGetter *mocks.MockStatsGetter) {
+				statsGetter.On("Stats", mock.Anything, "nonexistent", "owner@example.com", int64(123)).
+					Return(domain.LinkStats{}, domain.ErrURLNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:      "Error - Not owner and not admin",
+			alias:     "test_alias",
+			userEmail: "other@example.com",
+			userID:    789,
+			setupMocks: func(statsGetter *mocks.MockStatsGetter) {
+				statsGetter.On("Stats", mock.Anything, "test_alias", "other@example.com", int64(789)).
+					Return(domain.LinkStats{}, domain.ErrPermissionDenied).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:      "Error - Internal error",
+			alias:     "test_alias",
+			userEmail: "owner@example.com",
+			userID:    123,
+			setupMocks: func(statsGetter *mocks.MockStatsGetter) {
+				statsGetter.On("Stats", mock.Anything, "test_alias", "owner@example.com", int64(123)).
+					Return(domain.LinkStats{}, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:         "Error - Missing user email in context",
+			alias:        "test_alias",
+			withoutEmail: true,
+			setupMocks:   func(statsGetter *mocks.MockStatsGetter) {},
+			statusCode:   http.StatusInternalServerError,
+		},
+		{
+			name:          "Error - Missing user ID in context",
+			alias:         "test_alias",
+			userEmail:     "owner@example.com",
+			withoutUserID: true,
+			setupMocks:    func(statsGetter *mocks.MockStatsGetter) {},
+			statusCode:    http.StatusInternalServerError,
+		},
+		{
+			name:       "Error - Empty alias parameter",
+			alias:      "",
+			userEmail:  "owner@example.com",
+			userID:     123,
+			setupMocks: func(statsGetter *mocks.MockStatsGetter) {},
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			statsGetterMock := mocks.NewMockStatsGetter(t)
+			tc.setupMocks(statsGetterMock)
+
+			handler := stats.New(slog.New(slog.NewTextHandler(io.Discard, nil)), statsGetterMock)
+
+			req, err := http.NewRequest(http.MethodGet, "/url/"+tc.alias+"/stats", nil)
+			require.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", tc.alias)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			if !tc.withoutEmail {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyEmail, tc.userEmail))
+			}
+			if !tc.withoutUserID {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyUID, tc.userID))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+
+			var resp stats.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, link.Alias, resp.Alias)
+			require.Equal(t, http.StatusOK, resp.Health.StatusCode)
+			require.False(t, resp.Health.Broken)
+			require.Equal(t, 5, resp.Clicks)
+			require.Equal(t, link.UTMVersions, resp.UTMVersions)
+			require.Equal(t, link.VariantClicks, resp.VariantClicks)
+			require.Equal(t, link.NotBefore, resp.NotBefore)
+			require.Equal(t, link.NotAfter, resp.NotAfter)
+		})
+	}
+}
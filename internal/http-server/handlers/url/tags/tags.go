@@ -0,0 +1,105 @@
+package tags
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/http-server/middleware/auth"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type Response struct {
+	resp.Response
+	Tags []domain.TagStats `json:"tags"`
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type TagStatsProvider interface {
+	TagStats(ctx context.Context, workspaceID int64, userEmail string, userID int64) ([]domain.TagStats, error)
+}
+
+// New returns a handler that lists the tags of the user's personal links, or
+// with the workspace_id query parameter of the workspace links, with the
+// number of live links carrying each tag and the clicks they received.
+func New(log *slog.Logger, provider TagStatsProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.tags.New"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, ok := auth.GetEmail(r.Context())
+		if !ok {
+			log.Error("failed to get user email from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user email"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		userID, ok := auth.GetUID(r.Context())
+		if !ok {
+			log.Error("failed to get user id from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user id"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		var workspaceID int64
+		if v := r.URL.Query().Get("workspace_id"); v != "" {
+			id, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || id <= 0 {
+				log.Info("invalid workspace id", slog.String("workspace_id", v))
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("invalid workspace_id"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			workspaceID = id
+		}
+
+		stats, err := provider.TagStats(r.Context(), workspaceID, userEmail, userID)
+		if err != nil {
+			status, body := http.StatusInternalServerError, "internal error"
+			switch {
+			case errors.Is(err, workspace.ErrWorkspaceNotFound):
+				log.Info("workspace not found", slog.Int64("workspace_id", workspaceID))
+				status, body = http.StatusNotFound, workspace.ErrWorkspaceNotFound.Error()
+			case errors.Is(err, domain.ErrPermissionDenied):
+				log.Info("permission denied", slog.Int64("workspace_id", workspaceID), slog.String("user", userEmail))
+				status, body = http.StatusForbidden, "permission denied"
+			default:
+				log.Error("failed to get tag stats", slog.String("error", err.Error()))
+			}
+			err = resp.RenderJSON(w, status, resp.Error(body))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		if stats == nil {
+			stats = []domain.TagStats{}
+		}
+
+		err = resp.RenderJSON(w, http.StatusOK, Response{
+			Response: resp.OK(),
+			Tags:     stats,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package tags_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/http-server/handlers/url/tags"
+	"url-shortener/internal/http-server/handlers/url/tags/mocks"
+	"url-shortener/internal/http-server/middleware/auth"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagsHandler(t *testing.T) {
+	cases := []struct {
+		name         string
+		query        string
+		withoutEmail bool
+		withoutUID   bool
+		setupMocks   func(provider *mocks.MockTagStatsProvider)
+		statusCode   int
+		wantTags     []domain.TagStats
+	}{
+		{
+			name: "Success",
+			setupMocks: func(provider *mocks.MockTagStatsProvider) {
+				provider.On("TagStats", mock.Anything, int64(0), "owner@example.com", int64(123)).
+					Return([]domain.TagStats{{Tag: "launch", Links: 2, Clicks: 5}, {Tag: "promo", Links: 1}}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+			wantTags:   []domain.TagStats{{Tag: "launch", Links: 2, Clicks: 5}, {Tag: "promo", Links: 1}},
+		},
+		{
+			name:  "Success - No tags",
+			query: "?workspace_id=7",
+			setupMocks: func(provider *mocks.MockTagStatsProvider) {
+				provider.On("TagStats", mock.Anything, int64(7), "owner@example.com", int64(123)).Return(nil, nil).Once()
+			},
+			statusCode: http.StatusOK,
+			wantTags:   []domain.TagStats{},
+		},
+		{
+			name:       "Error - Invalid workspace id",
+			query:      "?workspace_id=-1",
+			setupMocks: func(provider *mocks.MockTagStatsProvider) {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "Error - Workspace not found",
+			query: "?workspace_id=7",
+			setupMocks: func(provider *mocks.MockTagStatsProvider) {
+				provider.On("TagStats", mock.Anything, int64(7), "owner@example.com", int64(123)).
+					Return(nil, workspace.ErrWorkspaceNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:  "Error - Not a workspace member",
+			query: "?workspace_id=7",
+			setupMocks: func(provider *mocks.MockTagStatsProvider) {
+				provider.On("TagStats", mock.Anything, int64(7), "owner@example.com", int64(123)).
+					Return(nil, domain.ErrPermissionDenied).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name: "Error - Stats fail",
+			setupMocks: func(provider *mocks.MockTagStatsProvider) {
+				provider.On("TagStats", mock.Anything, int64(0), "owner@example.com", int64(123)).
+					Return(nil, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:         "Error - Missing user email in context",
+			withoutEmail: true,
+			setupMocks:   func(provider *mocks.MockTagStatsProvider) {},
+			statusCode:   http.StatusInternalServerError,
+		},
+		{
+			name:       "Error - Missing user id in context",
+			withoutUID: true,
+			setupMocks: func(provider *mocks.MockTagStatsProvider) {},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			providerMock := mocks.NewMockTagStatsProvider(t)
+			tc.setupMocks(providerMock)
+
+			handler := tags.New(slog.New(slog.NewTextHandler(io.Discard, nil)), providerMock)
+
+			req, err := http.NewRequest(http.MethodGet, "/url/tags"+tc.query, nil)
+			require.NoError(t, err)
+
+			if !tc.withoutEmail {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyEmail, "owner@example.com"))
+			}
+			if !tc.withoutUID {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyUID, int64(123)))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+
+			var res tags.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+			require.Equal(t, tc.wantTags, res.Tags)
+		})
+	}
+}
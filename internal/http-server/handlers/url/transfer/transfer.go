@@ -0,0 +1,306 @@
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/handlers/request"
+	"url-shortener/internal/http-server/middleware/auth"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type Request struct {
+	ToEmail string `json:"to_email"`
+	// RequireAcceptance makes the transfer wait for the recipient to accept
+	// it; omitted uses the configured default.
+	RequireAcceptance *bool `json:"require_acceptance,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	domain.Transfer
+}
+
+type ListResponse struct {
+	resp.Response
+	Transfers []domain.Transfer `json:"transfers"`
+}
+
+type AcceptResponse struct {
+	resp.Response
+	domain.Link
+}
+
+type BulkRequest struct {
+	FromEmail string `json:"from_email"`
+	ToEmail   string `json:"to_email"`
+}
+
+type BulkResponse struct {
+	resp.Response
+	Aliases []string `json:"aliases"`
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type OwnershipTransferer interface {
+	TransferOwnership(ctx context.Context, alias, toEmail, requesterEmail string, requesterID int64, opts domain.TransferOptions) (domain.Transfer, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type TransferLister interface {
+	IncomingTransfers(ctx context.Context, requesterEmail string) ([]domain.Transfer, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type TransferAccepter interface {
+	AcceptTransfer(ctx context.Context, alias, requesterEmail string, requesterID int64) (domain.Link, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type TransferCanceller interface {
+	CancelTransfer(ctx context.Context, alias, requesterEmail string, requesterID int64) error
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type BulkTransferer interface {
+	TransferAll(ctx context.Context, fromEmail, toEmail string, requesterID int64) ([]string, error)
+}
+
+// New returns a handler that hands a link over to another user, right away or
+// once the recipient accepts.
+func New(log *slog.Logger, transferer OwnershipTransferer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.transfer.New"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, alias, ok := request.UserAndAlias(log, w, r)
+		if !ok {
+			return
+		}
+
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Error("failed to decode request body", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("invalid request body"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		transfer, err := transferer.TransferOwnership(r.Context(), alias, req.ToEmail, userEmail, userID, domain.TransferOptions{
+			RequireAcceptance: req.RequireAcceptance,
+		})
+		if err != nil {
+			renderError(log, w, err, alias, userEmail, "failed to transfer url")
+			return
+		}
+
+		log.Info("url transfer started", slog.String("alias", alias), slog.Bool("pending", transfer.Pending))
+
+		err = resp.RenderJSON(w, http.StatusOK, Response{
+			Response: resp.OK(),
+			Transfer: transfer,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// NewList returns a handler that lists the pending transfers to the user.
+func NewList(log *slog.Logger, lister TransferLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.transfer.NewList"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, ok := auth.GetEmail(r.Context())
+		if !ok {
+			log.Error("failed to get user email from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user email"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		transfers, err := lister.IncomingTransfers(r.Context(), userEmail)
+		if err != nil {
+			log.Error("failed to list transfers", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("internal error"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		if transfers == nil {
+			transfers = []domain.Transfer{}
+		}
+
+		err = resp.RenderJSON(w, http.StatusOK, ListResponse{
+			Response:  resp.OK(),
+			Transfers: transfers,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// NewAccept returns a handler that completes a pending transfer to the user.
+func NewAccept(log *slog.Logger, accepter TransferAccepter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.transfer.NewAccept"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, alias, ok := request.UserAndAlias(log, w, r)
+		if !ok {
+			return
+		}
+
+		link, err := accepter.AcceptTransfer(r.Context(), alias, userEmail, userID)
+		if err != nil {
+			renderError(log, w, err, alias, userEmail, "failed to accept transfer")
+			return
+		}
+
+		log.Info("url transfer accepted", slog.String("alias", alias))
+
+		err = resp.RenderJSON(w, http.StatusOK, AcceptResponse{
+			Response: resp.OK(),
+			Link:     link,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// NewCancel returns a handler that declines or withdraws a pending transfer.
+func NewCancel(log *slog.Logger, canceller TransferCanceller) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.transfer.NewCancel"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, alias, ok := request.UserAndAlias(log, w, r)
+		if !ok {
+			return
+		}
+
+		if err := canceller.CancelTransfer(r.Context(), alias, userEmail, userID); err != nil {
+			renderError(log, w, err, alias, userEmail, "failed to cancel transfer")
+			return
+		}
+
+		log.Info("url transfer cancelled", slog.String("alias", alias))
+
+		if err := resp.RenderJSON(w, http.StatusOK, resp.OK()); err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// NewBulk returns a handler that moves all links of one user to another, for
+// offboarding. Only admins may use it.
+func NewBulk(log *slog.Logger, transferer BulkTransferer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.transfer.NewBulk"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userID, ok := auth.GetUID(r.Context())
+		if !ok {
+			log.Error("failed to get user id from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user id"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		var req BulkRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Error("failed to decode request body", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("invalid request body"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		aliases, err := transferer.TransferAll(r.Context(), req.FromEmail, req.ToEmail, userID)
+		if err != nil {
+			renderError(log, w, err, "", req.FromEmail, "failed to transfer urls")
+			return
+		}
+
+		log.Info("urls transferred in bulk", slog.String("from", req.FromEmail), slog.Int("count", len(aliases)))
+
+		if aliases == nil {
+			aliases = []string{}
+		}
+
+		err = resp.RenderJSON(w, http.StatusOK, BulkResponse{
+			Response: resp.OK(),
+			Aliases:  aliases,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// renderError renders the response for a failed transfer request.
+func renderError(log *slog.Logger, w http.ResponseWriter, err error, alias, userEmail, msg string) {
+	status, body := http.StatusInternalServerError, "internal error"
+
+	switch {
+	case errors.Is(err, domain.ErrURLNotFound):
+		log.Info("url not found", slog.String("alias", alias))
+		status, body = http.StatusNotFound, "not found"
+	case errors.Is(err, domain.ErrTransferNotFound):
+		log.Info("transfer not found", slog.String("alias", alias), slog.String("user", userEmail))
+		status, body = http.StatusNotFound, domain.ErrTransferNotFound.Error()
+	case errors.Is(err, domain.ErrInvalidRecipient):
+		log.Info("invalid transfer recipient", slog.String("alias", alias))
+		status, body = http.StatusBadRequest, domain.ErrInvalidRecipient.Error()
+	case errors.Is(err, domain.ErrWorkspaceLink):
+		log.Info("workspace link cannot be transferred", slog.String("alias", alias))
+		status, body = http.StatusConflict, domain.ErrWorkspaceLink.Error()
+	case errors.Is(err, domain.ErrPermissionDenied):
+		log.Info("permission denied", slog.String("alias", alias), slog.String("user", userEmail))
+		status, body = http.StatusForbidden, "permission denied"
+	default:
+		log.Error(msg, slog.String("error", err.Error()))
+	}
+
+	err = resp.RenderJSON(w, status, resp.Error(body))
+	if err != nil {
+		log.Error("failed to render JSON response", slog.String("error", err.Error()))
+	}
+}
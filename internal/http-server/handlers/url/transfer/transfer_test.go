@@ -0,0 +1,398 @@
+package transfer_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/handlers/url/transfer"
+	"url-shortener/internal/http-server/handlers/url/transfer/mocks"
+	"url-shortener/internal/http-server/middleware/auth"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequest(t *testing.T, method, alias, body string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(method, "/url/transfers", bytes.NewReader([]byte(body)))
+	require.NoError(t, err)
+
+	rctx := chi.NewRouteContext()
+	if alias != "" {
+		rctx.URLParams.Add("alias", alias)
+	}
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, auth.ContextKeyEmail, "owner@example.com")
+	ctx = context.WithValue(ctx, auth.ContextKeyUID, int64(123))
+
+	return req.WithContext(ctx)
+}
+
+func TestTransferHandler(t *testing.T) {
+	requestedAt := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	accept := true
+
+	cases := []struct {
+		name        string
+		body        string
+		setupMocks  func(transferer *mocks.MockOwnershipTransferer)
+		statusCode  int
+		respError   string
+		wantPending bool
+	}{
+		{
+			name: "Success - Immediate",
+			body: `{"to_email":"new@example.com"}`,
+			setupMocks: func(transferer *mocks.MockOwnershipTransferer) {
+				transferer.On("TransferOwnership", mock.Anything, "test_alias", "new@example.com", "owner@example.com", int64(123), domain.TransferOptions{}).
+					Return(domain.Transfer{Alias: "test_alias", FromEmail: "owner@example.com", ToEmail: "new@example.com", RequestedAt: requestedAt}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "Success - Pending acceptance",
+			body: `{"to_email":"new@example.com","require_acceptance":true}`,
+			setupMocks: func(transferer *mocks.MockOwnershipTransferer) {
+				transferer.On("TransferOwnership", mock.Anything, "test_alias", "new@example.com", "owner@example.com", int64(123), domain.TransferOptions{RequireAcceptance: &accept}).
+					Return(domain.Transfer{Alias: "test_alias", ToEmail: "new@example.com", Pending: true}, nil).Once()
+			},
+			statusCode:  http.StatusOK,
+			wantPending: true,
+		},
+		{
+			name:       "Error - Invalid body",
+			body:       `{"to_email":`,
+			setupMocks: func(transferer *mocks.MockOwnershipTransferer) {},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid request body",
+		},
+		{
+			name: "Error - Invalid recipient",
+			body: `{"to_email":"owner@example.com"}`,
+			setupMocks: func(transferer *mocks.MockOwnershipTransferer) {
+				transferer.On("TransferOwnership", mock.Anything, "test_alias", "owner@example.com", "owner@example.com", int64(123), domain.TransferOptions{}).
+					Return(domain.Transfer{}, fmt.Errorf("url.Service.TransferOwnership: %w", domain.ErrInvalidRecipient)).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  domain.ErrInvalidRecipient.Error(),
+		},
+		{
+			name: "Error - Workspace link",
+			body: `{"to_email":"new@example.com"}`,
+			setupMocks: func(transferer *mocks.MockOwnershipTransferer) {
+				transferer.On("TransferOwnership", mock.Anything, "test_alias", "new@example.com", "owner@example.com", int64(123), domain.TransferOptions{}).
+					Return(domain.Transfer{}, fmt.Errorf("url.Service.TransferOwnership: %w", domain.ErrWorkspaceLink)).Once()
+			},
+			statusCode: http.StatusConflict,
+			respError:  domain.ErrWorkspaceLink.Error(),
+		},
+		{
+			name: "Error - URL not found",
+			body: `{"to_email":"new@example.com"}`,
+			setupMocks: func(transferer *mocks.MockOwnershipTransferer) {
+				transferer.On("TransferOwnership", mock.Anything, "test_alias", "new@example.com", "owner@example.com", int64(123), domain.TransferOptions{}).
+					Return(domain.Transfer{}, domain.ErrURLNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+			respError:  "not found",
+		},
+		{
+			name: "Error - Not owner and not admin",
+			body: `{"to_email":"new@example.com"}`,
+			setupMocks: func(transferer *mocks.MockOwnershipTransferer) {
+				transferer.On("TransferOwnership", mock.Anything, "test_alias", "new@example.com", "owner@example.com", int64(123), domain.TransferOptions{}).
+					Return(domain.Transfer{}, domain.ErrPermissionDenied).Once()
+			},
+			statusCode: http.StatusForbidden,
+			respError:  "permission denied",
+		},
+		{
+			name: "Error - Transfer fails",
+			body: `{"to_email":"new@example.com"}`,
+			setupMocks: func(transferer *mocks.MockOwnershipTransferer) {
+				transferer.On("TransferOwnership", mock.Anything, "test_alias", "new@example.com", "owner@example.com", int64(123), domain.TransferOptions{}).
+					Return(domain.Transfer{}, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+			respError:  "internal error",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			transfererMock := mocks.NewMockOwnershipTransferer(t)
+			tc.setupMocks(transfererMock)
+
+			handler := transfer.New(slog.New(slog.NewTextHandler(io.Discard, nil)), transfererMock)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, newRequest(t, http.MethodPost, "test_alias", tc.body))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var resp transfer.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			if tc.statusCode != http.StatusOK {
+				require.Equal(t, tc.respError, resp.Error)
+				return
+			}
+
+			require.Equal(t, "new@example.com", resp.ToEmail)
+			require.Equal(t, tc.wantPending, resp.Pending)
+		})
+	}
+}
+
+func TestListHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		setupMocks func(lister *mocks.MockTransferLister)
+		statusCode int
+		wantCount  int
+	}{
+		{
+			name: "Success",
+			setupMocks: func(lister *mocks.MockTransferLister) {
+				lister.On("IncomingTransfers", mock.Anything, "owner@example.com").
+					Return([]domain.Transfer{{Alias: "a", Pending: true}, {Alias: "b", Pending: true}}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+			wantCount:  2,
+		},
+		{
+			name: "Success - No transfers",
+			setupMocks: func(lister *mocks.MockTransferLister) {
+				lister.On("IncomingTransfers", mock.Anything, "owner@example.com").Return(nil, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "Error - List fails",
+			setupMocks: func(lister *mocks.MockTransferLister) {
+				lister.On("IncomingTransfers", mock.Anything, "owner@example.com").
+					Return(nil, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			listerMock := mocks.NewMockTransferLister(t)
+			tc.setupMocks(listerMock)
+
+			handler := transfer.NewList(slog.New(slog.NewTextHandler(io.Discard, nil)), listerMock)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, newRequest(t, http.MethodGet, "", ""))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+
+			var resp transfer.ListResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.NotNil(t, resp.Transfers)
+			require.Len(t, resp.Transfers, tc.wantCount)
+		})
+	}
+}
+
+func TestAcceptHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		setupMocks func(accepter *mocks.MockTransferAccepter)
+		statusCode int
+		respError  string
+	}{
+		{
+			name: "Success",
+			setupMocks: func(accepter *mocks.MockTransferAccepter) {
+				accepter.On("AcceptTransfer", mock.Anything, "test_alias", "owner@example.com", int64(123)).
+					Return(domain.Link{Alias: "test_alias", OwnerEmail: "owner@example.com"}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "Error - Transfer not found",
+			setupMocks: func(accepter *mocks.MockTransferAccepter) {
+				accepter.On("AcceptTransfer", mock.Anything, "test_alias", "owner@example.com", int64(123)).
+					Return(domain.Link{}, fmt.Errorf("url.Service.AcceptTransfer: %w", domain.ErrTransferNotFound)).Once()
+			},
+			statusCode: http.StatusNotFound,
+			respError:  domain.ErrTransferNotFound.Error(),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			accepterMock := mocks.NewMockTransferAccepter(t)
+			tc.setupMocks(accepterMock)
+
+			handler := transfer.NewAccept(slog.New(slog.NewTextHandler(io.Discard, nil)), accepterMock)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, newRequest(t, http.MethodPost, "test_alias", ""))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var resp transfer.AcceptResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			if tc.statusCode != http.StatusOK {
+				require.Equal(t, tc.respError, resp.Error)
+				return
+			}
+
+			require.Equal(t, "owner@example.com", resp.OwnerEmail)
+		})
+	}
+}
+
+func TestCancelHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		setupMocks func(canceller *mocks.MockTransferCanceller)
+		statusCode int
+	}{
+		{
+			name: "Success",
+			setupMocks: func(canceller *mocks.MockTransferCanceller) {
+				canceller.On("CancelTransfer", mock.Anything, "test_alias", "owner@example.com", int64(123)).Return(nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "Error - Transfer not found",
+			setupMocks: func(canceller *mocks.MockTransferCanceller) {
+				canceller.On("CancelTransfer", mock.Anything, "test_alias", "owner@example.com", int64(123)).
+					Return(domain.ErrTransferNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "Error - Neither recipient nor owner",
+			setupMocks: func(canceller *mocks.MockTransferCanceller) {
+				canceller.On("CancelTransfer", mock.Anything, "test_alias", "owner@example.com", int64(123)).
+					Return(domain.ErrPermissionDenied).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cancellerMock := mocks.NewMockTransferCanceller(t)
+			tc.setupMocks(cancellerMock)
+
+			handler := transfer.NewCancel(slog.New(slog.NewTextHandler(io.Discard, nil)), cancellerMock)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, newRequest(t, http.MethodDelete, "test_alias", ""))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+		})
+	}
+}
+
+func TestBulkHandler(t *testing.T) {
+	cases := []struct {
+		name        string
+		body        string
+		setupMocks  func(transferer *mocks.MockBulkTransferer)
+		statusCode  int
+		wantAliases []string
+	}{
+		{
+			name: "Success",
+			body: `{"from_email":"leaver@example.com","to_email":"new@example.com"}`,
+			setupMocks: func(transferer *mocks.MockBulkTransferer) {
+				transferer.On("TransferAll", mock.Anything, "leaver@example.com", "new@example.com", int64(123)).
+					Return([]string{"a", "b"}, nil).Once()
+			},
+			statusCode:  http.StatusOK,
+			wantAliases: []string{"a", "b"},
+		},
+		{
+			name: "Success - Nothing to move",
+			body: `{"from_email":"leaver@example.com","to_email":"new@example.com"}`,
+			setupMocks: func(transferer *mocks.MockBulkTransferer) {
+				transferer.On("TransferAll", mock.Anything, "leaver@example.com", "new@example.com", int64(123)).
+					Return(nil, nil).Once()
+			},
+			statusCode:  http.StatusOK,
+			wantAliases: []string{},
+		},
+		{
+			name:       "Error - Invalid body",
+			body:       `[]`,
+			setupMocks: func(transferer *mocks.MockBulkTransferer) {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Error - Invalid recipient",
+			body: `{"from_email":"leaver@example.com","to_email":"leaver@example.com"}`,
+			setupMocks: func(transferer *mocks.MockBulkTransferer) {
+				transferer.On("TransferAll", mock.Anything, "leaver@example.com", "leaver@example.com", int64(123)).
+					Return(nil, domain.ErrInvalidRecipient).Once()
+			},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Error - Not an admin",
+			body: `{"from_email":"leaver@example.com","to_email":"new@example.com"}`,
+			setupMocks: func(transferer *mocks.MockBulkTransferer) {
+				transferer.On("TransferAll", mock.Anything, "leaver@example.com", "new@example.com", int64(123)).
+					Return(nil, domain.ErrPermissionDenied).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			transfererMock := mocks.NewMockBulkTransferer(t)
+			tc.setupMocks(transfererMock)
+
+			handler := transfer.NewBulk(slog.New(slog.NewTextHandler(io.Discard, nil)), transfererMock)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, newRequest(t, http.MethodPost, "", tc.body))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+
+			var resp transfer.BulkResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.wantAliases, resp.Aliases)
+		})
+	}
+}
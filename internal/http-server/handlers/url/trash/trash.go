@@ -0,0 +1,176 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/handlers/request"
+	"url-shortener/internal/http-server/middleware/auth"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type Response struct {
+	resp.Response
+	URLs []domain.Link `json:"urls"`
+}
+
+type RestoreResponse struct {
+	resp.Response
+	domain.Link
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type TrashLister interface {
+	Trash(ctx context.Context, requesterEmail string, requesterID int64) ([]domain.Link, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type URLRestorer interface {
+	Restore(ctx context.Context, alias, requesterEmail string, requesterID int64) (domain.Link, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type URLPurger interface {
+	Purge(ctx context.Context, alias, requesterEmail string, requesterID int64) error
+}
+
+// New returns a handler that lists the deleted links of the user.
+func New(log *slog.Logger, trashLister TrashLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.trash.New"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, ok := auth.GetEmail(r.Context())
+		if !ok {
+			log.Error("failed to get user email from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user email"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		userID, ok := auth.GetUID(r.Context())
+		if !ok {
+			log.Error("failed to get user id from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user id"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		links, err := trashLister.Trash(r.Context(), userEmail, userID)
+		if err != nil {
+			log.Error("failed to list deleted urls", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("internal error"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		if links == nil {
+			links = []domain.Link{}
+		}
+
+		err = resp.RenderJSON(w, http.StatusOK, Response{
+			Response: resp.OK(),
+			URLs:     links,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// NewRestore returns a handler that takes a link out of the trash.
+func NewRestore(log *slog.Logger, urlRestorer URLRestorer) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.trash.NewRestore"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, alias, ok := request.UserAndAlias(log, w, r)
+		if !ok {
+			return
+		}
+
+		link, err := urlRestorer.Restore(r.Context(), alias, userEmail, userID)
+		if err != nil {
+			renderError(log, w, err, alias, userEmail, "failed to restore url")
+			return
+		}
+
+		log.Info("url restored", slog.String("alias", alias))
+
+		err = resp.RenderJSON(w, http.StatusOK, RestoreResponse{
+			Response: resp.OK(),
+			Link:     link,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// NewPurge returns a handler that permanently removes a link from the trash.
+func NewPurge(log *slog.Logger, urlPurger URLPurger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.trash.NewPurge"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, alias, ok := request.UserAndAlias(log, w, r)
+		if !ok {
+			return
+		}
+
+		err := urlPurger.Purge(r.Context(), alias, userEmail, userID)
+		if err != nil {
+			renderError(log, w, err, alias, userEmail, "failed to purge url")
+			return
+		}
+
+		log.Info("url purged", slog.String("alias", alias))
+
+		err = resp.RenderJSON(w, http.StatusOK, resp.OK())
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// renderError renders the response for a failed restore or purge.
+func renderError(log *slog.Logger, w http.ResponseWriter, err error, alias, userEmail, msg string) {
+	status, body := http.StatusInternalServerError, "internal error"
+	switch {
+	case errors.Is(err, domain.ErrURLNotFound):
+		log.Info("url not in trash", slog.String("alias", alias))
+		status, body = http.StatusNotFound, "not found"
+	case errors.Is(err, domain.ErrPermissionDenied):
+		log.Info("permission denied", slog.String("alias", alias), slog.String("user", userEmail))
+		status, body = http.StatusForbidden, "permission denied"
+	default:
+		log.Error(msg, slog.String("error", err.Error()))
+	}
+
+	err = resp.RenderJSON(w, status, resp.Error(body))
+	if err != nil {
+		log.Error("failed to render JSON response", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,200 @@
+package trash_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/handlers/url/trash"
+	"url-shortener/internal/http-server/handlers/url/trash/mocks"
+	"url-shortener/internal/http-server/middleware/auth"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrashHandler(t *testing.T) {
+	deletedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name         string
+		withoutEmail bool
+		withoutUID   bool
+		setupMocks   func(trashLister *mocks.MockTrashLister)
+		statusCode   int
+		wantURLs     int
+	}{
+		{
+			name: "Success",
+			setupMocks: func(trashLister *mocks.MockTrashLister) {
+				trashLister.On("Trash", mock.Anything, "owner@example.com", int64(123)).
+					Return([]domain.Link{
+						{Alias: "a", URL: "https://example.com/a", OwnerEmail: "owner@example.com", DeletedAt: &deletedAt, DeletedBy: "admin@example.com"},
+					}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+			wantURLs:   1,
+		},
+		{
+			name: "Success - Empty trash",
+			setupMocks: func(trashLister *mocks.MockTrashLister) {
+				trashLister.On("Trash", mock.Anything, "owner@example.com", int64(123)).
+					Return(nil, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "Error - Trash fails",
+			setupMocks: func(trashLister *mocks.MockTrashLister) {
+				trashLister.On("Trash", mock.Anything, "owner@example.com", int64(123)).
+					Return(nil, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:         "Error - Missing user email in context",
+			withoutEmail: true,
+			setupMocks:   func(trashLister *mocks.MockTrashLister) {},
+			statusCode:   http.StatusInternalServerError,
+		},
+		{
+			name:       "Error - Missing user id in context",
+			withoutUID: true,
+			setupMocks: func(trashLister *mocks.MockTrashLister) {},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			trashListerMock := mocks.NewMockTrashLister(t)
+			tc.setupMocks(trashListerMock)
+
+			handler := trash.New(slog.New(slog.NewTextHandler(io.Discard, nil)), trashListerMock)
+
+			req, err := http.NewRequest(http.MethodGet, "/url/trash", nil)
+			require.NoError(t, err)
+
+			if !tc.withoutEmail {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyEmail, "owner@example.com"))
+			}
+			if !tc.withoutUID {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyUID, int64(123)))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+
+			var resp trash.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.NotNil(t, resp.URLs)
+			require.Len(t, resp.URLs, tc.wantURLs)
+			for _, link := range resp.URLs {
+				require.Equal(t, &deletedAt, link.DeletedAt)
+				require.Equal(t, "admin@example.com", link.DeletedBy)
+			}
+		})
+	}
+}
+
+func TestRestoreAndPurgeHandlers(t *testing.T) {
+	cases := []struct {
+		name          string
+		alias         string
+		withoutEmail  bool
+		withoutUserID bool
+		mockError     error
+		callsMock     bool
+		statusCode    int
+	}{
+		{name: "Success", alias: "test_alias", callsMock: true, statusCode: http.StatusOK},
+		{name: "Error - Not in trash", alias: "test_alias", callsMock: true, mockError: domain.ErrURLNotFound, statusCode: http.StatusNotFound},
+		{name: "Error - Not owner and not admin", alias: "test_alias", callsMock: true, mockError: domain.ErrPermissionDenied, statusCode: http.StatusForbidden},
+		{name: "Error - Internal error", alias: "test_alias", callsMock: true, mockError: errors.New("database error"), statusCode: http.StatusInternalServerError},
+		{name: "Error - Missing user email in context", alias: "test_alias", withoutEmail: true, statusCode: http.StatusInternalServerError},
+		{name: "Error - Missing user ID in context", alias: "test_alias", withoutUserID: true, statusCode: http.StatusInternalServerError},
+		{name: "Error - Empty alias parameter", alias: "", statusCode: http.StatusBadRequest},
+	}
+
+	newRequest := func(t *testing.T, method, alias string, withoutEmail, withoutUserID bool) *http.Request {
+		req, err := http.NewRequest(method, "/url/trash/"+alias, nil)
+		require.NoError(t, err)
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("alias", alias)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		if !withoutEmail {
+			req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyEmail, "owner@example.com"))
+		}
+		if !withoutUserID {
+			req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyUID, int64(123)))
+		}
+		return req
+	}
+
+	for _, tc := range cases {
+		t.Run("Restore "+tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			restorerMock := mocks.NewMockURLRestorer(t)
+			if tc.callsMock {
+				link := domain.Link{}
+				if tc.mockError == nil {
+					link = domain.Link{Alias: tc.alias, URL: "https://example.com", OwnerEmail: "owner@example.com"}
+				}
+				restorerMock.On("Restore", mock.Anything, tc.alias, "owner@example.com", int64(123)).
+					Return(link, tc.mockError).Once()
+			}
+
+			handler := trash.NewRestore(slog.New(slog.NewTextHandler(io.Discard, nil)), restorerMock)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, newRequest(t, http.MethodPost, tc.alias, tc.withoutEmail, tc.withoutUserID))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+
+			var resp trash.RestoreResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.alias, resp.Alias)
+			require.Nil(t, resp.DeletedAt)
+		})
+
+		t.Run("Purge "+tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			purgerMock := mocks.NewMockURLPurger(t)
+			if tc.callsMock {
+				purgerMock.On("Purge", mock.Anything, tc.alias, "owner@example.com", int64(123)).
+					Return(tc.mockError).Once()
+			}
+
+			handler := trash.NewPurge(slog.New(slog.NewTextHandler(io.Discard, nil)), purgerMock)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, newRequest(t, http.MethodDelete, tc.alias, tc.withoutEmail, tc.withoutUserID))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/middleware/auth"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Request lists the fields to change; omitted fields are left as they are.
+type Request struct {
+	// URL repoints the link to a new destination.
+	URL *string `json:"url,omitempty"`
+	// UTM replaces the UTM template; an empty object removes it.
+	UTM *domain.UTMTemplate `json:"utm,omitempty"`
+	// Targeting replaces the targeting rules; an empty list removes them.
+	Targeting *[]domain.TargetingRule `json:"targeting,omitempty"`
+	// Variants replaces the split variants; an empty list removes them.
+	Variants *[]domain.Variant `json:"variants,omitempty"`
+	// StickyVariants turns sticky variants on or off.
+	StickyVariants *bool `json:"sticky_variants,omitempty"`
+	// SignedOnly turns redirecting only through signed URLs on or off.
+	SignedOnly *bool `json:"signed_only,omitempty"`
+	// Tags replaces the tags; an empty list removes them.
+	Tags *[]string `json:"tags,omitempty"`
+	// FolderID moves the link into a folder of its owner; 0 takes it out of
+	// its folder.
+	FolderID *int64 `json:"folder_id,omitempty"`
+	// Notes replaces the notes; an empty string removes them.
+	Notes *string `json:"notes,omitempty"`
+}
+
+type Response struct {
+	resp.Response
+	domain.Link
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type URLUpdater interface {
+	Update(ctx context.Context, alias, requesterEmail string, requesterID int64, upd domain.LinkUpdate) (domain.Link, error)
+}
+
+func New(log *slog.Logger, urlUpdater URLUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.url.update.New"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, ok := auth.GetEmail(r.Context())
+		if !ok {
+			log.Error("failed to get user email from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user email"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		userID, ok := auth.GetUID(r.Context())
+		if !ok {
+			log.Error("failed to get user id from context")
+			err := resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("failed to get user id"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		alias := chi.URLParam(r, "alias")
+		if alias == "" {
+			log.Error("alias parameter is missing")
+			err := resp.RenderJSON(w, http.StatusBadRequest, resp.Error("alias parameter is required"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		var req Request
+
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			log.Error("failed to decode request body", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("invalid request body"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		link, err := urlUpdater.Update(r.Context(), alias, userEmail, userID, domain.LinkUpdate{
+			URL:            req.URL,
+			UTM:            req.UTM,
+			Targeting:      req.Targeting,
+			Variants:       req.Variants,
+			StickyVariants: req.StickyVariants,
+			SignedOnly:     req.SignedOnly,
+			Tags:           req.Tags,
+			FolderID:       req.FolderID,
+			Notes:          req.Notes,
+		})
+		if err != nil {
+			var violation *domain.PolicyViolationError
+			if errors.As(err, &violation) {
+				log.Info("target rejected by policy", slog.String("rule", violation.Rule), slog.String("host", violation.Host))
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("destination not allowed"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrURLBlocked) {
+				log.Warn("target is blocklisted", slog.String("error", err.Error()))
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("destination is blocklisted"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrEmptyUpdate) || errors.Is(err, domain.ErrInvalidUTM) ||
+				errors.Is(err, domain.ErrInvalidTargeting) || errors.Is(err, domain.ErrInvalidVariants) ||
+				errors.Is(err, domain.ErrInvalidURL) || errors.Is(err, domain.ErrInvalidScheme) ||
+				errors.Is(err, domain.ErrSigningDisabled) || errors.Is(err, domain.ErrInvalidTag) ||
+				errors.Is(err, domain.ErrTooManyTags) || errors.Is(err, domain.ErrNotesTooLong) {
+				log.Info("invalid update", slog.String("alias", alias), slog.String("error", err.Error()))
+				err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error(validationMessage(err)))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrURLNotFound) {
+				log.Info("url not found", slog.String("alias", alias))
+				err = resp.RenderJSON(w, http.StatusNotFound, resp.Error("not found"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrFolderNotFound) {
+				log.Info("folder not found", slog.String("alias", alias))
+				err = resp.RenderJSON(w, http.StatusNotFound, resp.Error(domain.ErrFolderNotFound.Error()))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+			if errors.Is(err, domain.ErrPermissionDenied) {
+				log.Info("permission denied", slog.String("alias", alias), slog.String("user", userEmail))
+				err = resp.RenderJSON(w, http.StatusForbidden, resp.Error("permission denied"))
+				if err != nil {
+					log.Error("failed to render JSON response", slog.String("error", err.Error()))
+				}
+				return
+			}
+
+			log.Error("failed to update url", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("internal error"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		log.Info("url updated", slog.String("alias", alias))
+
+		err = resp.RenderJSON(w, http.StatusOK, Response{
+			Response: resp.OK(),
+			Link:     link,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// validationMessage returns the client-facing message for a rejected update.
+func validationMessage(err error) string {
+	if errors.Is(err, domain.ErrInvalidURL) || errors.Is(err, domain.ErrInvalidScheme) {
+		return "invalid URL"
+	}
+	for _, known := range []error{domain.ErrEmptyUpdate, domain.ErrInvalidUTM, domain.ErrInvalidTargeting, domain.ErrInvalidVariants, domain.ErrSigningDisabled, domain.ErrInvalidTag, domain.ErrTooManyTags, domain.ErrNotesTooLong} {
+		if errors.Is(err, known) {
+			return known.Error()
+		}
+	}
+	return "invalid update"
+}
@@ -0,0 +1,370 @@
+package update_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/http-server/handlers/url/update"
+	"url-shortener/internal/http-server/handlers/url/update/mocks"
+	"url-shortener/internal/http-server/middleware/auth"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateHandler(t *testing.T) {
+	utm := domain.UTMTemplate{Source: "newsletter", Medium: "email", Campaign: "spring"}
+	updated := domain.Link{
+		Alias:      "test_alias",
+		URL:        "https://example.com",
+		OwnerEmail: "owner@example.com",
+		UTM:        &utm,
+		UTMVersion: 2,
+	}
+
+	cases := []struct {
+		name          string
+		alias         string
+		body          string
+		withoutEmail  bool
+		withoutUserID bool
+		setupMocks    func(urlUpdater *mocks.MockURLUpdater)
+		statusCode    int
+		respError     string
+	}{
+		{
+			name:  "Success",
+			alias: "test_alias",
+			body:  `{"utm":{"source":"newsletter","medium":"email","campaign":"spring"}}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.LinkUpdate{UTM: &utm}).
+					Return(updated, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Empty template removes UTM parameters",
+			alias: "test_alias",
+			body:  `{"utm":{}}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.LinkUpdate{UTM: &domain.UTMTemplate{}}).
+					Return(domain.Link{Alias: "test_alias", UTMVersion: 3}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Nothing to update",
+			alias: "test_alias",
+			body:  `{}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.LinkUpdate{}).
+					Return(domain.Link{}, fmt.Errorf("url.Service.Update: %w", domain.ErrEmptyUpdate)).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  domain.ErrEmptyUpdate.Error(),
+		},
+		{
+			name:  "Invalid UTM template",
+			alias: "test_alias",
+			body:  `{"utm":{"source":"a\nb"}}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.LinkUpdate{UTM: &domain.UTMTemplate{Source: "a\nb"}}).
+					Return(domain.Link{}, fmt.Errorf("url.Service.Update: %w", domain.ErrInvalidUTM)).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  domain.ErrInvalidUTM.Error(),
+		},
+		{
+			name:  "Targeting rules",
+			alias: "test_alias",
+			body:  `{"targeting":[{"platform":"android","url":"https://play.google.com/store/apps/details?id=x"}]}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				rules := []domain.TargetingRule{{Platform: "android", URL: "https://play.google.com/store/apps/details?id=x"}}
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.LinkUpdate{Targeting: &rules}).
+					Return(domain.Link{Alias: "test_alias", Targeting: rules}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Empty targeting list removes rules",
+			alias: "test_alias",
+			body:  `{"targeting":[]}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				rules := []domain.TargetingRule{}
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.LinkUpdate{Targeting: &rules}).
+					Return(domain.Link{Alias: "test_alias"}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Invalid targeting rule",
+			alias: "test_alias",
+			body:  `{"targeting":[{"url":"https://example.com"}]}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), mock.Anything).
+					Return(domain.Link{}, fmt.Errorf("url.Service.Update: %w", domain.ErrInvalidTargeting)).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  domain.ErrInvalidTargeting.Error(),
+		},
+		{
+			name:  "Invalid target URL",
+			alias: "test_alias",
+			body:  `{"targeting":[{"device":"mobile","url":"ftp://example.com"}]}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), mock.Anything).
+					Return(domain.Link{}, fmt.Errorf("url.Service.Update: %w", domain.ErrInvalidScheme)).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid URL",
+		},
+		{
+			name:  "Target rejected by policy",
+			alias: "test_alias",
+			body:  `{"targeting":[{"device":"mobile","url":"http://127.0.0.1"}]}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), mock.Anything).
+					Return(domain.Link{}, fmt.Errorf("url.Service.Update: %w", &domain.PolicyViolationError{Rule: domain.RulePrivateIP, Host: "127.0.0.1"})).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  "destination not allowed",
+		},
+		{
+			name:  "Target blocklisted",
+			alias: "test_alias",
+			body:  `{"targeting":[{"device":"mobile","url":"https://phish.example"}]}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), mock.Anything).
+					Return(domain.Link{}, fmt.Errorf("url.Service.Update: %w", domain.ErrURLBlocked)).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  "destination is blocklisted",
+		},
+		{
+			name:  "Split variants and stickiness",
+			alias: "test_alias",
+			body:  `{"variants":[{"name":"a","url":"https://example.com/a","weight":1}],"sticky_variants":false}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				variants := []domain.Variant{{Name: "a", URL: "https://example.com/a", Weight: 1}}
+				sticky := false
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.LinkUpdate{Variants: &variants, StickyVariants: &sticky}).
+					Return(domain.Link{Alias: "test_alias", Variants: variants}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Repoint destination",
+			alias: "test_alias",
+			body:  `{"url":"https://example.com/new"}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				newURL := "https://example.com/new"
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.LinkUpdate{URL: &newURL}).
+					Return(domain.Link{Alias: "test_alias", URL: newURL}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Signed only",
+			alias: "test_alias",
+			body:  `{"signed_only":true}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				signedOnly := true
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.LinkUpdate{SignedOnly: &signedOnly}).
+					Return(domain.Link{Alias: "test_alias", SignedOnly: true}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Signed only without signing keys",
+			alias: "test_alias",
+			body:  `{"signed_only":true}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), mock.Anything).
+					Return(domain.Link{}, fmt.Errorf("url.Service.Update: %w", domain.ErrSigningDisabled)).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  domain.ErrSigningDisabled.Error(),
+		},
+		{
+			name:  "Invalid split variants",
+			alias: "test_alias",
+			body:  `{"variants":[{"name":"a b","url":"https://example.com/a","weight":1}]}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), mock.Anything).
+					Return(domain.Link{}, fmt.Errorf("url.Service.Update: %w", domain.ErrInvalidVariants)).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  domain.ErrInvalidVariants.Error(),
+		},
+		{
+			name:  "Tags and folder",
+			alias: "test_alias",
+			body:  `{"tags":["launch"],"folder_id":0}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				tags, folderID := []string{"launch"}, int64(0)
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.LinkUpdate{Tags: &tags, FolderID: &folderID}).
+					Return(domain.Link{Alias: "test_alias", Tags: tags}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Notes",
+			alias: "test_alias",
+			body:  `{"notes":"Printed on the spring flyer"}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				notes := "Printed on the spring flyer"
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), domain.LinkUpdate{Notes: &notes}).
+					Return(domain.Link{Alias: "test_alias", Notes: notes}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Notes too long",
+			alias: "test_alias",
+			body:  `{"notes":"..."}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), mock.Anything).
+					Return(domain.Link{}, fmt.Errorf("url.Service.Update: %w", domain.ErrNotesTooLong)).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  domain.ErrNotesTooLong.Error(),
+		},
+		{
+			name:  "Too many tags",
+			alias: "test_alias",
+			body:  `{"tags":["a","b"]}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), mock.Anything).
+					Return(domain.Link{}, fmt.Errorf("url.Service.Update: %w", domain.ErrTooManyTags)).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  domain.ErrTooManyTags.Error(),
+		},
+		{
+			name:  "Folder not found",
+			alias: "test_alias",
+			body:  `{"folder_id":9}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), mock.Anything).
+					Return(domain.Link{}, fmt.Errorf("url.Service.Update: %w", domain.ErrFolderNotFound)).Once()
+			},
+			statusCode: http.StatusNotFound,
+			respError:  domain.ErrFolderNotFound.Error(),
+		},
+		{
+			name:       "Invalid body",
+			alias:      "test_alias",
+			body:       `{"utm":`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid request body",
+		},
+		{
+			name:  "Not found",
+			alias: "missing",
+			body:  `{"utm":{}}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "missing", "owner@example.com", int64(123), mock.Anything).
+					Return(domain.Link{}, domain.ErrURLNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+			respError:  "not found",
+		},
+		{
+			name:  "Not owner and not admin",
+			alias: "test_alias",
+			body:  `{"utm":{}}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), mock.Anything).
+					Return(domain.Link{}, domain.ErrPermissionDenied).Once()
+			},
+			statusCode: http.StatusForbidden,
+			respError:  "permission denied",
+		},
+		{
+			name:  "Internal error",
+			alias: "test_alias",
+			body:  `{"utm":{}}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {
+				urlUpdater.On("Update", mock.Anything, "test_alias", "owner@example.com", int64(123), mock.Anything).
+					Return(domain.Link{}, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+			respError:  "internal error",
+		},
+		{
+			name:         "Missing user email in context",
+			alias:        "test_alias",
+			body:         `{"utm":{}}`,
+			withoutEmail: true,
+			setupMocks:   func(urlUpdater *mocks.MockURLUpdater) {},
+			statusCode:   http.StatusInternalServerError,
+			respError:    "failed to get user email",
+		},
+		{
+			name:          "Missing user ID in context",
+			alias:         "test_alias",
+			body:          `{"utm":{}}`,
+			withoutUserID: true,
+			setupMocks:    func(urlUpdater *mocks.MockURLUpdater) {},
+			statusCode:    http.StatusInternalServerError,
+			respError:     "failed to get user id",
+		},
+		{
+			name:       "Empty alias parameter",
+			alias:      "",
+			body:       `{"utm":{}}`,
+			setupMocks: func(urlUpdater *mocks.MockURLUpdater) {},
+			statusCode: http.StatusBadRequest,
+			respError:  "alias parameter is required",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			urlUpdaterMock := mocks.NewMockURLUpdater(t)
+			tc.setupMocks(urlUpdaterMock)
+
+			handler := update.New(slog.New(slog.NewTextHandler(io.Discard, nil)), urlUpdaterMock)
+
+			req, err := http.NewRequest(http.MethodPatch, "/url/"+tc.alias, strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("alias", tc.alias)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			if !tc.withoutEmail {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyEmail, "owner@example.com"))
+			}
+			if !tc.withoutUserID {
+				req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyUID, int64(123)))
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var resp update.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+			require.Equal(t, tc.respError, resp.Error)
+
+			if tc.statusCode == http.StatusOK {
+				require.Equal(t, tc.alias, resp.Alias)
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/http-server/handlers/request"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+type Request struct {
+	Name string `json:"name"`
+}
+
+type Response struct {
+	resp.Response
+	workspace.Workspace
+}
+
+type ListResponse struct {
+	resp.Response
+	Workspaces []workspace.Workspace `json:"workspaces"`
+}
+
+type MemberRequest struct {
+	Role workspace.Role `json:"role"`
+}
+
+type MemberResponse struct {
+	resp.Response
+	workspace.Member
+}
+
+type MembersResponse struct {
+	resp.Response
+	Members []workspace.Member `json:"members"`
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type WorkspaceCreator interface {
+	Create(ctx context.Context, name, requesterEmail string, requesterID int64) (workspace.Workspace, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type WorkspaceLister interface {
+	List(ctx context.Context, requesterEmail string, requesterID int64) ([]workspace.Workspace, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type MemberLister interface {
+	Members(ctx context.Context, workspaceID int64, requesterEmail string, requesterID int64) ([]workspace.Member, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type MemberSetter interface {
+	SetMember(ctx context.Context, workspaceID int64, email string, role workspace.Role, requesterEmail string, requesterID int64) (workspace.Member, error)
+}
+
+//go:generate go run github.com/vektra/mockery/v3
+type MemberRemover interface {
+	RemoveMember(ctx context.Context, workspaceID int64, email, requesterEmail string, requesterID int64) error
+}
+
+// New returns a handler that creates a workspace with the user as its admin.
+func New(log *slog.Logger, creator WorkspaceCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.workspace.New"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, ok := request.User(log, w, r)
+		if !ok {
+			return
+		}
+
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Error("failed to decode request body", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("invalid request body"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		ws, err := creator.Create(r.Context(), req.Name, userEmail, userID)
+		if err != nil {
+			renderError(log, w, err, 0, userEmail, "failed to create workspace")
+			return
+		}
+
+		log.Info("workspace created", slog.Int64("workspace_id", ws.ID))
+
+		err = resp.RenderJSON(w, http.StatusOK, Response{
+			Response:  resp.OK(),
+			Workspace: ws,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// NewList returns a handler that lists the workspaces of the user.
+func NewList(log *slog.Logger, lister WorkspaceLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.workspace.NewList"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, ok := request.User(log, w, r)
+		if !ok {
+			return
+		}
+
+		workspaces, err := lister.List(r.Context(), userEmail, userID)
+		if err != nil {
+			log.Error("failed to list workspaces", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusInternalServerError, resp.Error("internal error"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		if workspaces == nil {
+			workspaces = []workspace.Workspace{}
+		}
+
+		err = resp.RenderJSON(w, http.StatusOK, ListResponse{
+			Response:   resp.OK(),
+			Workspaces: workspaces,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// NewMembers returns a handler that lists the members of a workspace.
+func NewMembers(log *slog.Logger, lister MemberLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.workspace.NewMembers"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, workspaceID, ok := request.UserAndID(log, w, r, "workspace id")
+		if !ok {
+			return
+		}
+
+		members, err := lister.Members(r.Context(), workspaceID, userEmail, userID)
+		if err != nil {
+			renderError(log, w, err, workspaceID, userEmail, "failed to list members")
+			return
+		}
+
+		if members == nil {
+			members = []workspace.Member{}
+		}
+
+		err = resp.RenderJSON(w, http.StatusOK, MembersResponse{
+			Response: resp.OK(),
+			Members:  members,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// NewSetMember returns a handler that adds a member to a workspace or changes
+// the role of a member.
+func NewSetMember(log *slog.Logger, setter MemberSetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.workspace.NewSetMember"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, workspaceID, ok := request.UserAndID(log, w, r, "workspace id")
+		if !ok {
+			return
+		}
+
+		var req MemberRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Error("failed to decode request body", slog.String("error", err.Error()))
+			err = resp.RenderJSON(w, http.StatusBadRequest, resp.Error("invalid request body"))
+			if err != nil {
+				log.Error("failed to render JSON response", slog.String("error", err.Error()))
+			}
+			return
+		}
+
+		member, err := setter.SetMember(r.Context(), workspaceID, chi.URLParam(r, "email"), req.Role, userEmail, userID)
+		if err != nil {
+			renderError(log, w, err, workspaceID, userEmail, "failed to set member")
+			return
+		}
+
+		log.Info("workspace member set", slog.Int64("workspace_id", workspaceID), slog.String("role", string(member.Role)))
+
+		err = resp.RenderJSON(w, http.StatusOK, MemberResponse{
+			Response: resp.OK(),
+			Member:   member,
+		})
+		if err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// NewRemoveMember returns a handler that removes a member from a workspace.
+// Members may also remove themselves to leave it.
+func NewRemoveMember(log *slog.Logger, remover MemberRemover) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "http-server.handlers.workspace.NewRemoveMember"
+
+		log = log.With(
+			slog.String("op", op),
+			slog.String("request_id", middleware.GetReqID(r.Context())),
+		)
+
+		userEmail, userID, workspaceID, ok := request.UserAndID(log, w, r, "workspace id")
+		if !ok {
+			return
+		}
+
+		if err := remover.RemoveMember(r.Context(), workspaceID, chi.URLParam(r, "email"), userEmail, userID); err != nil {
+			renderError(log, w, err, workspaceID, userEmail, "failed to remove member")
+			return
+		}
+
+		log.Info("workspace member removed", slog.Int64("workspace_id", workspaceID))
+
+		if err := resp.RenderJSON(w, http.StatusOK, resp.OK()); err != nil {
+			log.Error("failed to render JSON response", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// renderError renders the response for a failed workspace request.
+func renderError(log *slog.Logger, w http.ResponseWriter, err error, workspaceID int64, userEmail, msg string) {
+	status, body := http.StatusInternalServerError, "internal error"
+
+	switch {
+	case errors.Is(err, workspace.ErrWorkspaceNotFound):
+		log.Info("workspace not found", slog.Int64("workspace_id", workspaceID))
+		status, body = http.StatusNotFound, workspace.ErrWorkspaceNotFound.Error()
+	case errors.Is(err, workspace.ErrMemberNotFound):
+		log.Info("workspace member not found", slog.Int64("workspace_id", workspaceID))
+		status, body = http.StatusNotFound, workspace.ErrMemberNotFound.Error()
+	case errors.Is(err, workspace.ErrInvalidName):
+		status, body = http.StatusBadRequest, workspace.ErrInvalidName.Error()
+	case errors.Is(err, workspace.ErrInvalidRole):
+		status, body = http.StatusBadRequest, workspace.ErrInvalidRole.Error()
+	case errors.Is(err, workspace.ErrInvalidMember):
+		status, body = http.StatusBadRequest, workspace.ErrInvalidMember.Error()
+	case errors.Is(err, workspace.ErrLastAdmin):
+		log.Info("last workspace admin kept", slog.Int64("workspace_id", workspaceID))
+		status, body = http.StatusConflict, workspace.ErrLastAdmin.Error()
+	case errors.Is(err, domain.ErrPermissionDenied):
+		log.Info("permission denied", slog.Int64("workspace_id", workspaceID), slog.String("user", userEmail))
+		status, body = http.StatusForbidden, "permission denied"
+	default:
+		log.Error(msg, slog.String("error", err.Error()))
+	}
+
+	err = resp.RenderJSON(w, status, resp.Error(body))
+	if err != nil {
+		log.Error("failed to render JSON response", slog.String("error", err.Error()))
+	}
+}
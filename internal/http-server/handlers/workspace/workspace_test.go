@@ -0,0 +1,387 @@
+package workspace_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	handler "url-shortener/internal/http-server/handlers/workspace"
+	"url-shortener/internal/http-server/handlers/workspace/mocks"
+	"url-shortener/internal/http-server/middleware/auth"
+	resp "url-shortener/internal/lib/api/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequest(t *testing.T, method, id, email, body string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(method, "/url/workspaces", bytes.NewReader([]byte(body)))
+	require.NoError(t, err)
+
+	rctx := chi.NewRouteContext()
+	if id != "" {
+		rctx.URLParams.Add("id", id)
+	}
+	if email != "" {
+		rctx.URLParams.Add("email", email)
+	}
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	ctx = context.WithValue(ctx, auth.ContextKeyEmail, "admin@example.com")
+	ctx = context.WithValue(ctx, auth.ContextKeyUID, int64(123))
+
+	return req.WithContext(ctx)
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestCreateHandler(t *testing.T) {
+	createdAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name       string
+		body       string
+		setupMocks func(creator *mocks.MockWorkspaceCreator)
+		statusCode int
+		respError  string
+	}{
+		{
+			name: "Success",
+			body: `{"name":"Marketing"}`,
+			setupMocks: func(creator *mocks.MockWorkspaceCreator) {
+				creator.On("Create", mock.Anything, "Marketing", "admin@example.com", int64(123)).
+					Return(workspace.Workspace{ID: 7, Name: "Marketing", CreatedBy: "admin@example.com", CreatedAt: createdAt, Role: workspace.RoleAdmin}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Error - Invalid body",
+			body:       `{"name":`,
+			setupMocks: func(creator *mocks.MockWorkspaceCreator) {},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid request body",
+		},
+		{
+			name: "Error - Invalid name",
+			body: `{"name":""}`,
+			setupMocks: func(creator *mocks.MockWorkspaceCreator) {
+				creator.On("Create", mock.Anything, "", "admin@example.com", int64(123)).
+					Return(workspace.Workspace{}, fmt.Errorf("workspace.Service.Create: %w", workspace.ErrInvalidName)).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  workspace.ErrInvalidName.Error(),
+		},
+		{
+			name: "Error - Create fails",
+			body: `{"name":"Marketing"}`,
+			setupMocks: func(creator *mocks.MockWorkspaceCreator) {
+				creator.On("Create", mock.Anything, "Marketing", "admin@example.com", int64(123)).
+					Return(workspace.Workspace{}, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+			respError:  "internal error",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			creatorMock := mocks.NewMockWorkspaceCreator(t)
+			tc.setupMocks(creatorMock)
+
+			rr := httptest.NewRecorder()
+			handler.New(discardLogger(), creatorMock).ServeHTTP(rr, newRequest(t, http.MethodPost, "", "", tc.body))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var res handler.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+
+			if tc.statusCode != http.StatusOK {
+				require.Equal(t, tc.respError, res.Error)
+				return
+			}
+
+			require.Equal(t, int64(7), res.ID)
+			require.Equal(t, workspace.RoleAdmin, res.Role)
+		})
+	}
+}
+
+func TestListHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		setupMocks func(lister *mocks.MockWorkspaceLister)
+		statusCode int
+		wantCount  int
+	}{
+		{
+			name: "Success",
+			setupMocks: func(lister *mocks.MockWorkspaceLister) {
+				lister.On("List", mock.Anything, "admin@example.com", int64(123)).
+					Return([]workspace.Workspace{{ID: 1, Role: workspace.RoleAdmin}, {ID: 2, Role: workspace.RoleViewer}}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+			wantCount:  2,
+		},
+		{
+			name: "Success - No workspaces",
+			setupMocks: func(lister *mocks.MockWorkspaceLister) {
+				lister.On("List", mock.Anything, "admin@example.com", int64(123)).Return(nil, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "Error - List fails",
+			setupMocks: func(lister *mocks.MockWorkspaceLister) {
+				lister.On("List", mock.Anything, "admin@example.com", int64(123)).
+					Return(nil, errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			listerMock := mocks.NewMockWorkspaceLister(t)
+			tc.setupMocks(listerMock)
+
+			rr := httptest.NewRecorder()
+			handler.NewList(discardLogger(), listerMock).ServeHTTP(rr, newRequest(t, http.MethodGet, "", "", ""))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+
+			var res handler.ListResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+			require.NotNil(t, res.Workspaces)
+			require.Len(t, res.Workspaces, tc.wantCount)
+		})
+	}
+}
+
+func TestMembersHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		id         string
+		setupMocks func(lister *mocks.MockMemberLister)
+		statusCode int
+		wantCount  int
+	}{
+		{
+			name: "Success",
+			id:   "7",
+			setupMocks: func(lister *mocks.MockMemberLister) {
+				lister.On("Members", mock.Anything, int64(7), "admin@example.com", int64(123)).
+					Return([]workspace.Member{
+						{WorkspaceID: 7, Email: "admin@example.com", Role: workspace.RoleAdmin},
+						{WorkspaceID: 7, Email: "viewer@example.com", Role: workspace.RoleViewer},
+					}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+			wantCount:  2,
+		},
+		{
+			name:       "Error - Invalid workspace id",
+			id:         "abc",
+			setupMocks: func(lister *mocks.MockMemberLister) {},
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Error - Workspace not found",
+			id:   "7",
+			setupMocks: func(lister *mocks.MockMemberLister) {
+				lister.On("Members", mock.Anything, int64(7), "admin@example.com", int64(123)).
+					Return(nil, workspace.ErrWorkspaceNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "Error - Not a member",
+			id:   "7",
+			setupMocks: func(lister *mocks.MockMemberLister) {
+				lister.On("Members", mock.Anything, int64(7), "admin@example.com", int64(123)).
+					Return(nil, domain.ErrPermissionDenied).Once()
+			},
+			statusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			listerMock := mocks.NewMockMemberLister(t)
+			tc.setupMocks(listerMock)
+
+			rr := httptest.NewRecorder()
+			handler.NewMembers(discardLogger(), listerMock).ServeHTTP(rr, newRequest(t, http.MethodGet, tc.id, "", ""))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+
+			var res handler.MembersResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+			require.Len(t, res.Members, tc.wantCount)
+		})
+	}
+}
+
+func TestSetMemberHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		body       string
+		setupMocks func(setter *mocks.MockMemberSetter)
+		statusCode int
+		respError  string
+	}{
+		{
+			name: "Success",
+			body: `{"role":"editor"}`,
+			setupMocks: func(setter *mocks.MockMemberSetter) {
+				setter.On("SetMember", mock.Anything, int64(7), "member@example.com", workspace.RoleEditor, "admin@example.com", int64(123)).
+					Return(workspace.Member{WorkspaceID: 7, Email: "member@example.com", Role: workspace.RoleEditor}, nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Error - Invalid body",
+			body:       `{"role":`,
+			setupMocks: func(setter *mocks.MockMemberSetter) {},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid request body",
+		},
+		{
+			name: "Error - Invalid role",
+			body: `{"role":"owner"}`,
+			setupMocks: func(setter *mocks.MockMemberSetter) {
+				setter.On("SetMember", mock.Anything, int64(7), "member@example.com", workspace.Role("owner"), "admin@example.com", int64(123)).
+					Return(workspace.Member{}, workspace.ErrInvalidRole).Once()
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  workspace.ErrInvalidRole.Error(),
+		},
+		{
+			name: "Error - Last admin",
+			body: `{"role":"viewer"}`,
+			setupMocks: func(setter *mocks.MockMemberSetter) {
+				setter.On("SetMember", mock.Anything, int64(7), "member@example.com", workspace.RoleViewer, "admin@example.com", int64(123)).
+					Return(workspace.Member{}, workspace.ErrLastAdmin).Once()
+			},
+			statusCode: http.StatusConflict,
+			respError:  workspace.ErrLastAdmin.Error(),
+		},
+		{
+			name: "Error - Not a workspace admin",
+			body: `{"role":"editor"}`,
+			setupMocks: func(setter *mocks.MockMemberSetter) {
+				setter.On("SetMember", mock.Anything, int64(7), "member@example.com", workspace.RoleEditor, "admin@example.com", int64(123)).
+					Return(workspace.Member{}, domain.ErrPermissionDenied).Once()
+			},
+			statusCode: http.StatusForbidden,
+			respError:  "permission denied",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			setterMock := mocks.NewMockMemberSetter(t)
+			tc.setupMocks(setterMock)
+
+			rr := httptest.NewRecorder()
+			handler.NewSetMember(discardLogger(), setterMock).
+				ServeHTTP(rr, newRequest(t, http.MethodPut, "7", "member@example.com", tc.body))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var res handler.MemberResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+
+			if tc.statusCode != http.StatusOK {
+				require.Equal(t, tc.respError, res.Error)
+				return
+			}
+
+			require.Equal(t, workspace.RoleEditor, res.Role)
+		})
+	}
+}
+
+func TestRemoveMemberHandler(t *testing.T) {
+	cases := []struct {
+		name       string
+		setupMocks func(remover *mocks.MockMemberRemover)
+		statusCode int
+	}{
+		{
+			name: "Success",
+			setupMocks: func(remover *mocks.MockMemberRemover) {
+				remover.On("RemoveMember", mock.Anything, int64(7), "member@example.com", "admin@example.com", int64(123)).
+					Return(nil).Once()
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name: "Error - Member not found",
+			setupMocks: func(remover *mocks.MockMemberRemover) {
+				remover.On("RemoveMember", mock.Anything, int64(7), "member@example.com", "admin@example.com", int64(123)).
+					Return(workspace.ErrMemberNotFound).Once()
+			},
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name: "Error - Remove fails",
+			setupMocks: func(remover *mocks.MockMemberRemover) {
+				remover.On("RemoveMember", mock.Anything, int64(7), "member@example.com", "admin@example.com", int64(123)).
+					Return(errors.New("database error")).Once()
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			removerMock := mocks.NewMockMemberRemover(t)
+			tc.setupMocks(removerMock)
+
+			rr := httptest.NewRecorder()
+			handler.NewRemoveMember(discardLogger(), removerMock).
+				ServeHTTP(rr, newRequest(t, http.MethodDelete, "7", "member@example.com", ""))
+
+			require.Equal(t, tc.statusCode, rr.Code)
+
+			var res resp.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+			if tc.statusCode == http.StatusOK {
+				require.Equal(t, resp.StatusOK, res.Status)
+			}
+		})
+	}
+}
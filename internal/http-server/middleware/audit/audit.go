@@ -0,0 +1,36 @@
+package audit
+
+import (
+	"net/http"
+	"url-shortener/internal/domain/audit"
+	"url-shortener/internal/http-server/middleware/auth"
+	"url-shortener/internal/http-server/middleware/clientip"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// New attaches the request ID, the client IP and the authenticated user to the
+// request context, where the audit log picks them up. It has to run after the
+// request ID, client IP and auth middlewares.
+func New() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			info := audit.RequestInfo{
+				RequestID: middleware.GetReqID(r.Context()),
+			}
+			if addr, ok := clientip.Addr(r); ok {
+				info.ClientIP = addr
+			}
+			if uid, ok := auth.GetUID(r.Context()); ok {
+				info.ActorUID = uid
+			}
+			if email, ok := auth.GetEmail(r.Context()); ok {
+				info.ActorEmail = email
+			}
+
+			next.ServeHTTP(w, r.WithContext(audit.WithRequestInfo(r.Context(), info)))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"url-shortener/internal/domain/audit"
+	"url-shortener/internal/http-server/middleware/auth"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		withUser   bool
+		want       audit.RequestInfo
+	}{
+		{
+			name:       "Authenticated request",
+			remoteAddr: "203.0.113.5:4000",
+			withUser:   true,
+			want: audit.RequestInfo{
+				RequestID:  "req-1",
+				ClientIP:   netip.MustParseAddr("203.0.113.5"),
+				ActorUID:   7,
+				ActorEmail: "owner@example.com",
+			},
+		},
+		{
+			name:       "Anonymous request",
+			remoteAddr: "198.51.100.7",
+			want: audit.RequestInfo{
+				RequestID: "req-1",
+				ClientIP:  netip.MustParseAddr("198.51.100.7"),
+			},
+		},
+		{
+			name:       "Unparsable remote address",
+			remoteAddr: "unknown",
+			want:       audit.RequestInfo{RequestID: "req-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				got   audit.RequestInfo
+				found bool
+			)
+			handler := New()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, found = audit.RequestInfoFrom(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodDelete, "/abc", nil)
+			req.RemoteAddr = tt.remoteAddr
+			ctx := context.WithValue(req.Context(), middleware.RequestIDKey, "req-1")
+			if tt.withUser {
+				ctx = context.WithValue(ctx, auth.ContextKeyUID, int64(7))
+				ctx = context.WithValue(ctx, auth.ContextKeyEmail, "owner@example.com")
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+
+			require.True(t, found)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package clientip
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.10"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		trusted    bool
+		remoteAddr string
+		forwarded  []string
+		want       string
+	}{
+		{
+			name:       "Direct client",
+			trusted:    true,
+			remoteAddr: "203.0.113.5:4000",
+			want:       "203.0.113.5:4000",
+		},
+		{
+			name:       "Header from untrusted peer is ignored",
+			trusted:    true,
+			remoteAddr: "203.0.113.5:4000",
+			forwarded:  []string{"198.51.100.7"},
+			want:       "203.0.113.5:4000",
+		},
+		{
+			name:       "Client behind trusted proxy",
+			trusted:    true,
+			remoteAddr: "10.1.2.3:5000",
+			forwarded:  []string{"198.51.100.7"},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "Spoofed entries left of the client are ignored",
+			trusted:    true,
+			remoteAddr: "10.1.2.3:5000",
+			forwarded:  []string{"1.1.1.1, 198.51.100.7, 192.0.2.10"},
+			want:       "198.51.100.7",
+		},
+		{
+			name:       "Several headers",
+			trusted:    true,
+			remoteAddr: "192.0.2.10:5000",
+			forwarded:  []string{"1.1.1.1", "2001:db8::1, 10.0.0.1"},
+			want:       "2001:db8::1",
+		},
+		{
+			name:       "Malformed entry stops the chain",
+			trusted:    true,
+			remoteAddr: "10.1.2.3:5000",
+			forwarded:  []string{"198.51.100.7, unknown"},
+			want:       "10.1.2.3:5000",
+		},
+		{
+			name:       "Only trusted proxies in the chain",
+			trusted:    true,
+			remoteAddr: "10.1.2.3:5000",
+			forwarded:  []string{"10.9.9.9"},
+			want:       "10.9.9.9",
+		},
+		{
+			name:       "No trusted proxies configured",
+			remoteAddr: "10.1.2.3:5000",
+			forwarded:  []string{"198.51.100.7"},
+			want:       "10.1.2.3:5000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxies := trusted
+			if !tt.trusted {
+				proxies = nil
+			}
+
+			var got string
+			handler := New(slog.New(slog.NewTextHandler(io.Discard, nil)), proxies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r.RemoteAddr
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwarded {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	prefixes, err := ParseTrustedProxies([]string{"10.1.2.3/8", "::ffff:127.0.0.1", "2001:db8::/32"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.0/8", "127.0.0.1/32", "2001:db8::/32"}, []string{
+		prefixes[0].String(), prefixes[1].String(), prefixes[2].String(),
+	})
+
+	_, err = ParseTrustedProxies([]string{"not-an-ip"})
+	require.Error(t, err)
+}
+
+func TestAddr(t *testing.T) {
+	for remoteAddr, want := range map[string]string{
+		"192.0.2.1:1234":          "192.0.2.1",
+		"192.0.2.1":               "192.0.2.1",
+		"[2001:db8::1]:443":       "2001:db8::1",
+		"[::ffff:192.0.2.1]:1234": "192.0.2.1",
+	} {
+		addr, ok := Addr(&http.Request{RemoteAddr: remoteAddr})
+		require.True(t, ok, remoteAddr)
+		require.Equal(t, want, addr.String())
+	}
+
+	_, ok := Addr(&http.Request{RemoteAddr: "@"})
+	require.False(t, ok)
+}
@@ -1,9 +1,12 @@
 package response
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"html/template"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -44,6 +47,24 @@ func RenderJSON(w http.ResponseWriter, status int, v any) error {
 	return nil
 }
 
+// RenderHTML executes the template with the given data and writes it as an HTML
+// response with the specified HTTP status code
+func RenderHTML(w http.ResponseWriter, status int, tmpl *template.Template, data any) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	if _, err := buf.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+
+	return nil
+}
+
 // ValidationError constructs a Response from validator.ValidationErrors
 func ValidationError(errs validator.ValidationErrors) Response {
 	var errMsgs []string
@@ -64,3 +85,33 @@ func ValidationError(errs validator.ValidationErrors) Response {
 		Error:  strings.Join(errMsgs, ", "),
 	}
 }
+
+// PrefersJSON reports whether the Accept header of r ranks application/json
+// above text/html. Requests without an Accept header are served HTML.
+func PrefersJSON(r *http.Request) bool {
+	var jsonQ, htmlQ float64
+
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.TrimSpace(key) == "q" {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+
+		switch mediaType {
+		case "application/json":
+			jsonQ = max(jsonQ, q)
+		case "text/html":
+			htmlQ = max(htmlQ, q)
+		}
+	}
+
+	return jsonQ > htmlQ
+}
@@ -0,0 +1,277 @@
+package blocklist
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/idna"
+)
+
+// Supported list file formats.
+const (
+	// FormatPlain lists one entry per line: a host (blocking the host and all
+	// of its subdomains) or a URL prefix such as "https://example.com/phish/".
+	FormatPlain = "plain"
+	// FormatHosts is the hosts-file format: an IP address followed by one or
+	// more host names, e.g. "0.0.0.0 malware.example".
+	FormatHosts = "hosts"
+)
+
+// Source is a list file and its format.
+type Source struct {
+	Path   string
+	Format string
+}
+
+// Blocklist matches URLs against host and URL prefix lists loaded from local
+// files. It is safe for concurrent use; Watch reloads the lists when a file changes.
+type Blocklist struct {
+	log     *slog.Logger
+	sources []Source
+
+	mu       sync.RWMutex
+	hosts    map[string]struct{}
+	prefixes []string
+	stamps   []fileStamp
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+// New creates a Blocklist and loads all sources. It fails if any source cannot be read.
+func New(log *slog.Logger, sources []Source) (*Blocklist, error) {
+	const op = "lib.blocklist.New"
+
+	sources = slices.Clone(sources)
+	for i, src := range sources {
+		if src.Format == "" {
+			sources[i].Format = FormatPlain
+		}
+		if sources[i].Format != FormatPlain && sources[i].Format != FormatHosts {
+			return nil, fmt.Errorf("%s: unknown format %q for %s", op, src.Format, src.Path)
+		}
+	}
+
+	b := &Blocklist{
+		log:     log.With(slog.String("component", "blocklist")),
+		sources: sources,
+	}
+
+	if err := b.Reload(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return b, nil
+}
+
+// Blocked reports whether rawURL is blocklisted and returns the matching entry.
+func (b *Blocklist) Blocked(rawURL string) (string, bool) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", false
+	}
+
+	host := normalizeHost(parsedURL.Hostname())
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for candidate := host; candidate != ""; {
+		if _, ok := b.hosts[candidate]; ok {
+			return candidate, true
+		}
+		_, parent, found := strings.Cut(candidate, ".")
+		if !found {
+			break
+		}
+		candidate = parent
+	}
+
+	if len(b.prefixes) > 0 {
+		normalized := normalizePrefix(rawURL)
+		for _, prefix := range b.prefixes {
+			if strings.HasPrefix(normalized, prefix) {
+				return prefix, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// Reload reads all sources and atomically replaces the current lists.
+// On error the previously loaded lists are kept.
+func (b *Blocklist) Reload() error {
+	hosts := make(map[string]struct{})
+	var prefixes []string
+	stamps := make([]fileStamp, len(b.sources))
+
+	for i, src := range b.sources {
+		stamp, err := stat(src.Path)
+		if err != nil {
+			return err
+		}
+		stamps[i] = stamp
+
+		if err = load(src, hosts, &prefixes); err != nil {
+			return err
+		}
+	}
+
+	b.mu.Lock()
+	b.hosts = hosts
+	b.prefixes = prefixes
+	b.stamps = stamps
+	b.mu.Unlock()
+
+	b.log.Info("blocklist loaded", slog.Int("hosts", len(hosts)), slog.Int("prefixes", len(prefixes)))
+
+	return nil
+}
+
+// Watch polls the source files every interval and reloads the lists when any
+// of them changes. It blocks until ctx is cancelled. A non-positive interval
+// disables reloading and returns immediately.
+func (b *Blocklist) Watch(ctx context.Context, interval time.Duration) {
+	if len(b.sources) == 0 {
+		return
+	}
+	if interval <= 0 {
+		b.log.Info("blocklist reload disabled", slog.Duration("interval", interval))
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !b.changed() {
+				continue
+			}
+			if err := b.Reload(); err != nil {
+				b.log.Error("failed to reload blocklist", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// changed reports whether any source file differs from the loaded version.
+func (b *Blocklist) changed() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for i, src := range b.sources {
+		stamp, err := stat(src.Path)
+		if err != nil {
+			b.log.Warn("failed to stat blocklist file", slog.String("path", src.Path), slog.String("error", err.Error()))
+			continue
+		}
+		if stamp != b.stamps[i] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func stat(path string) (fileStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	return fileStamp{modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+func load(src Source, hosts map[string]struct{}, prefixes *[]string) error {
+	f, err := os.Open(src.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src.Path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch src.Format {
+		case FormatHosts:
+			// The first field is the address the host is pinned to.
+			for _, host := range fields[1:] {
+				host = normalizeHost(host)
+				if isLocalHostsEntry(host) {
+					continue
+				}
+				hosts[host] = struct{}{}
+			}
+		default:
+			entry := fields[0]
+			if strings.Contains(entry, "://") {
+				*prefixes = append(*prefixes, normalizePrefix(entry))
+				continue
+			}
+			hosts[normalizeHost(strings.TrimPrefix(entry, "*."))] = struct{}{}
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", src.Path, err)
+	}
+
+	return nil
+}
+
+// isLocalHostsEntry reports whether host is one of the standard entries every
+// hosts file carries, which must never be treated as blocked.
+func isLocalHostsEntry(host string) bool {
+	switch host {
+	case "", "localhost", "localhost.localdomain", "local", "broadcasthost",
+		"ip6-localhost", "ip6-loopback", "ip6-localnet", "ip6-mcastprefix",
+		"ip6-allnodes", "ip6-allrouters", "ip6-allhosts", "0.0.0.0":
+		return true
+	}
+	return false
+}
+
+func normalizeHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if ascii, err := idna.Lookup.ToASCII(host); err == nil {
+		return ascii
+	}
+	return host
+}
+
+// normalizePrefix lowercases the scheme and host of a URL so prefixes match
+// regardless of how they were written.
+func normalizePrefix(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	parsedURL.Scheme = strings.ToLower(parsedURL.Scheme)
+	parsedURL.Host = strings.ToLower(parsedURL.Host)
+
+	return parsedURL.String()
+}
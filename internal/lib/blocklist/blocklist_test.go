@@ -0,0 +1,131 @@
+package blocklist
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestBlocklistBlocked(t *testing.T) {
+	dir := t.TempDir()
+
+	plain := filepath.Join(dir, "plain.txt")
+	writeFile(t, plain, `# phishing hosts
+phish.example
+*.wildcard.example
+https://Share.Example/files/evil  # one bad folder on a shared host
+`)
+
+	hosts := filepath.Join(dir, "hosts")
+	writeFile(t, hosts, `127.0.0.1 localhost
+::1 localhost ip6-localhost
+0.0.0.0 malware.example ads.malware2.example
+`)
+
+	b, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), []Source{
+		{Path: plain},
+		{Path: hosts, Format: FormatHosts},
+	})
+	require.NoError(t, err)
+
+	cases := []struct {
+		url       string
+		wantEntry string
+		blocked   bool
+	}{
+		{url: "https://phish.example/login", wantEntry: "phish.example", blocked: true},
+		{url: "https://PHISH.example./login", wantEntry: "phish.example", blocked: true},
+		{url: "https://a.b.phish.example/", wantEntry: "phish.example", blocked: true},
+		{url: "https://notphish.example/", blocked: false},
+		{url: "https://x.wildcard.example/", wantEntry: "wildcard.example", blocked: true},
+		{url: "https://share.example/files/evil/payload.exe", wantEntry: "https://share.example/files/evil", blocked: true},
+		{url: "https://share.example/files/good.pdf", blocked: false},
+		{url: "http://malware.example/", wantEntry: "malware.example", blocked: true},
+		{url: "http://ads.malware2.example/", wantEntry: "ads.malware2.example", blocked: true},
+		{url: "http://malware2.example/", blocked: false},
+		{url: "http://localhost/", blocked: false},
+	}
+
+	for _, tc := range cases {
+		entry, blocked := b.Blocked(tc.url)
+		require.Equal(t, tc.blocked, blocked, tc.url)
+		require.Equal(t, tc.wantEntry, entry, tc.url)
+	}
+}
+
+func TestBlocklistReloadOnChange(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "plain.txt")
+	writeFile(t, path, "old.example\n")
+
+	b, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), []Source{{Path: path}})
+	require.NoError(t, err)
+
+	require.False(t, b.changed())
+
+	writeFile(t, path, "new.example\nother.example\n")
+	// Make sure the modification is visible even on filesystems with coarse mtimes.
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Second)))
+
+	require.True(t, b.changed())
+	require.NoError(t, b.Reload())
+
+	_, blocked := b.Blocked("https://old.example/")
+	require.False(t, blocked)
+	_, blocked = b.Blocked("https://new.example/")
+	require.True(t, blocked)
+}
+
+func TestWatchWithoutIntervalDoesNotReload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "plain.txt")
+	writeFile(t, path, "bad.example\n")
+
+	b, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), []Source{{Path: path}})
+	require.NoError(t, err)
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			b.Watch(context.Background(), interval)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("Watch(%s) did not return", interval)
+		}
+	}
+}
+
+func TestBlocklistKeepsListsWhenReloadFails(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "plain.txt")
+	writeFile(t, path, "bad.example\n")
+
+	b, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), []Source{{Path: path}})
+	require.NoError(t, err)
+
+	require.NoError(t, os.Remove(path))
+	require.Error(t, b.Reload())
+
+	_, blocked := b.Blocked("https://bad.example/")
+	require.True(t, blocked)
+}
+
+func TestNewRejectsUnknownFormat(t *testing.T) {
+	_, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), []Source{{Path: "x", Format: "csv"}})
+	require.Error(t, err)
+}
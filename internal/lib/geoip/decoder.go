@@ -0,0 +1,264 @@
+package geoip
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Data section field types.
+const (
+	typeExtended  = 0
+	typePointer   = 1
+	typeString    = 2
+	typeDouble    = 3
+	typeBytes     = 4
+	typeUint16    = 5
+	typeUint32    = 6
+	typeMap       = 7
+	typeInt32     = 8
+	typeUint64    = 9
+	typeUint128   = 10
+	typeArray     = 11
+	typeContainer = 12
+	typeEnd       = 13
+	typeBool      = 14
+	typeFloat     = 15
+)
+
+// maxDepth limits nesting of maps and arrays so a corrupt file cannot
+// exhaust the stack.
+const maxDepth = 32
+
+var errTruncated = errors.New("unexpected end of data")
+
+// decoder decodes values of the data section into Go values: maps become
+// map[string]any, arrays []any, unsigned integers uint64 (uint128 *big.Int),
+// int32 int64, float and double float64.
+type decoder struct {
+	buf   []byte
+	depth int
+}
+
+// decode decodes the value at offset and returns the offset after it.
+func (d *decoder) decode(offset int) (any, int, error) {
+	typ, size, offset, err := d.control(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if typ == typePointer {
+		value, _, err := d.decodeValue(size)
+		if err != nil {
+			return nil, 0, err
+		}
+		return value, offset, nil
+	}
+
+	return d.decodeType(typ, size, offset)
+}
+
+// decodeValue decodes the value at offset, which must not be a pointer.
+func (d *decoder) decodeValue(offset int) (any, int, error) {
+	typ, size, next, err := d.control(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if typ == typePointer {
+		return nil, 0, errors.New("pointer to a pointer")
+	}
+	return d.decodeType(typ, size, next)
+}
+
+// control reads a control byte and returns the type and the size of the
+// field and the offset of its payload. For pointers, size is the target offset.
+func (d *decoder) control(offset int) (int, int, int, error) {
+	if offset >= len(d.buf) {
+		return 0, 0, 0, errTruncated
+	}
+	ctrl := d.buf[offset]
+	offset++
+
+	typ := int(ctrl >> 5)
+	if typ == typePointer {
+		return d.pointer(ctrl, offset)
+	}
+	if typ == typeExtended {
+		if offset >= len(d.buf) {
+			return 0, 0, 0, errTruncated
+		}
+		typ = 7 + int(d.buf[offset])
+		offset++
+		if typ <= typeMap || typ > typeFloat {
+			return 0, 0, 0, fmt.Errorf("invalid extended type %d", typ)
+		}
+	}
+
+	size := int(ctrl & 0x1f)
+	if size >= 29 {
+		n := size - 28
+		if offset+n > len(d.buf) {
+			return 0, 0, 0, errTruncated
+		}
+		extra := int(uintFrom(d.buf[offset : offset+n]))
+		offset += n
+		switch n {
+		case 1:
+			size = 29 + extra
+		case 2:
+			size = 285 + extra
+		default:
+			size = 65821 + extra
+		}
+	}
+
+	return typ, size, offset, nil
+}
+
+// pointer decodes the target offset of a pointer.
+func (d *decoder) pointer(ctrl byte, offset int) (int, int, int, error) {
+	n := int(ctrl>>3)&0x3 + 1
+	if offset+n > len(d.buf) {
+		return 0, 0, 0, errTruncated
+	}
+	b := d.buf[offset : offset+n]
+
+	var target int
+	switch n {
+	case 1:
+		target = int(ctrl&0x7)<<8 | int(b[0])
+	case 2:
+		target = (int(ctrl&0x7)<<16 | int(uintFrom(b))) + 2048
+	case 3:
+		target = (int(ctrl&0x7)<<24 | int(uintFrom(b))) + 526336
+	default:
+		target = int(uintFrom(b))
+	}
+
+	return typePointer, target, offset + n, nil
+}
+
+func (d *decoder) decodeType(typ, size, offset int) (any, int, error) {
+	switch typ {
+	case typeMap:
+		return d.decodeMap(size, offset)
+	case typeArray:
+		return d.decodeArray(size, offset)
+	case typeBool:
+		if size > 1 {
+			return nil, 0, fmt.Errorf("invalid boolean size %d", size)
+		}
+		return size == 1, offset, nil
+	case typeContainer, typeEnd:
+		return nil, 0, fmt.Errorf("unexpected field type %d", typ)
+	}
+
+	if offset+size > len(d.buf) {
+		return nil, 0, errTruncated
+	}
+	b := d.buf[offset : offset+size]
+	next := offset + size
+
+	switch typ {
+	case typeString:
+		return string(b), next, nil
+	case typeBytes:
+		return append([]byte(nil), b...), next, nil
+	case typeDouble:
+		if size != 8 {
+			return nil, 0, fmt.Errorf("invalid double size %d", size)
+		}
+		return math.Float64frombits(uintFrom(b)), next, nil
+	case typeFloat:
+		if size != 4 {
+			return nil, 0, fmt.Errorf("invalid float size %d", size)
+		}
+		return float64(math.Float32frombits(uint32(uintFrom(b)))), next, nil
+	case typeUint16, typeUint32, typeUint64:
+		if size > uintSize(typ) {
+			return nil, 0, fmt.Errorf("invalid size %d for type %d", size, typ)
+		}
+		return uintFrom(b), next, nil
+	case typeInt32:
+		if size > 4 {
+			return nil, 0, fmt.Errorf("invalid int32 size %d", size)
+		}
+		return int64(int32(uint32(uintFrom(b)))), next, nil
+	case typeUint128:
+		if size > 16 {
+			return nil, 0, fmt.Errorf("invalid uint128 size %d", size)
+		}
+		return new(big.Int).SetBytes(b), next, nil
+	}
+
+	return nil, 0, fmt.Errorf("unknown field type %d", typ)
+}
+
+func (d *decoder) decodeMap(size, offset int) (any, int, error) {
+	if d.depth++; d.depth > maxDepth {
+		return nil, 0, errors.New("data nested too deeply")
+	}
+	defer func() { d.depth-- }()
+
+	m := make(map[string]any, min(size, 64))
+	for range size {
+		key, next, err := d.decode(offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		k, ok := key.(string)
+		if !ok {
+			return nil, 0, errors.New("map key is not a string")
+		}
+
+		value, next, err := d.decode(next)
+		if err != nil {
+			return nil, 0, err
+		}
+		m[k] = value
+		offset = next
+	}
+
+	return m, offset, nil
+}
+
+func (d *decoder) decodeArray(size, offset int) (any, int, error) {
+	if d.depth++; d.depth > maxDepth {
+		return nil, 0, errors.New("data nested too deeply")
+	}
+	defer func() { d.depth-- }()
+
+	a := make([]any, 0, min(size, 64))
+	for range size {
+		value, next, err := d.decode(offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		a = append(a, value)
+		offset = next
+	}
+
+	return a, offset, nil
+}
+
+// uintSize returns the maximum size in bytes of an unsigned integer type.
+func uintSize(typ int) int {
+	switch typ {
+	case typeUint16:
+		return 2
+	case typeUint32:
+		return 4
+	default:
+		return 8
+	}
+}
+
+// uintFrom decodes a big-endian unsigned integer of up to 8 bytes.
+func uintFrom(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
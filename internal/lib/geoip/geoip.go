@@ -0,0 +1,221 @@
+// Package geoip resolves IP addresses to countries using a local database in
+// the MaxMind DB format, such as GeoLite2-Country or GeoIP2-Country.
+package geoip
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+)
+
+// ErrInvalidDatabase indicates that the file is not a supported MaxMind DB.
+var ErrInvalidDatabase = errors.New("invalid MaxMind DB")
+
+// metadataMarker precedes the metadata section at the end of the file.
+var metadataMarker = []byte("\xAB\xCD\xEFMaxMind.com")
+
+// dataSectionSeparator is the size of the zero bytes between the search tree
+// and the data section.
+const dataSectionSeparator = 16
+
+// Metadata describes a database.
+type Metadata struct {
+	DatabaseType string
+	IPVersion    int
+	RecordSize   int
+	NodeCount    int
+	BuildEpoch   uint64
+}
+
+// Reader looks up records in a MaxMind DB held in memory. It is safe for
+// concurrent use.
+type Reader struct {
+	buf       []byte
+	data      []byte
+	metadata  Metadata
+	ipv4Start int
+}
+
+// Open reads the whole database at path into memory.
+func Open(path string) (*Reader, error) {
+	const op = "lib.geoip.Open"
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	r, err := FromBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s: %w", op, path, err)
+	}
+
+	return r, nil
+}
+
+// FromBytes creates a Reader for a database already loaded into memory.
+func FromBytes(buf []byte) (*Reader, error) {
+	start := bytes.LastIndex(buf, metadataMarker)
+	if start < 0 {
+		return nil, fmt.Errorf("%w: metadata not found", ErrInvalidDatabase)
+	}
+	metaStart := start + len(metadataMarker)
+
+	raw, _, err := (&decoder{buf: buf[metaStart:]}).decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("%w: metadata: %w", ErrInvalidDatabase, err)
+	}
+	meta, ok := raw.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: metadata is not a map", ErrInvalidDatabase)
+	}
+
+	if major, _ := meta["binary_format_major_version"].(uint64); major != 2 {
+		return nil, fmt.Errorf("%w: unsupported format version %v", ErrInvalidDatabase, meta["binary_format_major_version"])
+	}
+
+	md := Metadata{}
+	md.DatabaseType, _ = meta["database_type"].(string)
+	md.BuildEpoch, _ = meta["build_epoch"].(uint64)
+	ipVersion, _ := meta["ip_version"].(uint64)
+	recordSize, _ := meta["record_size"].(uint64)
+	nodeCount, _ := meta["node_count"].(uint64)
+	md.IPVersion, md.RecordSize, md.NodeCount = int(ipVersion), int(recordSize), int(nodeCount)
+
+	if md.IPVersion != 4 && md.IPVersion != 6 {
+		return nil, fmt.Errorf("%w: unsupported ip version %d", ErrInvalidDatabase, md.IPVersion)
+	}
+	if md.RecordSize != 24 && md.RecordSize != 28 && md.RecordSize != 32 {
+		return nil, fmt.Errorf("%w: unsupported record size %d", ErrInvalidDatabase, md.RecordSize)
+	}
+
+	treeSize := md.NodeCount * md.RecordSize / 4
+	if treeSize+dataSectionSeparator > start {
+		return nil, fmt.Errorf("%w: search tree exceeds file size", ErrInvalidDatabase)
+	}
+
+	r := &Reader{
+		buf:      buf,
+		data:     buf[treeSize+dataSectionSeparator : start],
+		metadata: md,
+	}
+
+	// IPv4 addresses live under ::/96 in IPv6 databases.
+	if md.IPVersion == 6 {
+		node := 0
+		for i := 0; i < 96 && node < md.NodeCount; i++ {
+			node = r.record(node, 0)
+		}
+		r.ipv4Start = node
+	}
+
+	return r, nil
+}
+
+// Metadata returns the metadata of the database.
+func (r *Reader) Metadata() Metadata {
+	return r.metadata
+}
+
+// Lookup returns the record for ip, or nil if the database has none.
+func (r *Reader) Lookup(ip netip.Addr) (any, error) {
+	offset, found, err := r.find(ip)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	value, _, err := (&decoder{buf: r.data}).decode(offset)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDatabase, err)
+	}
+
+	return value, nil
+}
+
+// Country returns the ISO 3166-1 alpha-2 code of the country ip is located
+// in, falling back to the country it is registered in. It returns an empty
+// string when the database does not know the address.
+func (r *Reader) Country(ip netip.Addr) (string, error) {
+	record, err := r.Lookup(ip)
+	if err != nil || record == nil {
+		return "", err
+	}
+
+	for _, key := range []string{"country", "registered_country"} {
+		if code, ok := path(record, key, "iso_code").(string); ok && code != "" {
+			return code, nil
+		}
+	}
+
+	return "", nil
+}
+
+// find walks the search tree and returns the data section offset for ip.
+func (r *Reader) find(ip netip.Addr) (int, bool, error) {
+	ip = ip.Unmap()
+	if !ip.IsValid() {
+		return 0, false, nil
+	}
+
+	node, bits := 0, 128
+	if ip.Is4() {
+		bits = 32
+		if r.metadata.IPVersion == 6 {
+			node = r.ipv4Start
+		}
+	} else if r.metadata.IPVersion == 4 {
+		return 0, false, nil
+	}
+
+	addr := ip.AsSlice()
+	nodeCount := r.metadata.NodeCount
+	for i := 0; i < bits && node < nodeCount; i++ {
+		bit := int(addr[i/8]>>(7-i%8)) & 1
+		node = r.record(node, bit)
+	}
+
+	switch {
+	case node == nodeCount:
+		return 0, false, nil
+	case node > nodeCount:
+		offset := node - nodeCount - dataSectionSeparator
+		if offset < 0 || offset >= len(r.data) {
+			return 0, false, fmt.Errorf("%w: record points outside the data section", ErrInvalidDatabase)
+		}
+		return offset, true, nil
+	default:
+		return 0, false, fmt.Errorf("%w: search tree is deeper than the address", ErrInvalidDatabase)
+	}
+}
+
+// record returns the left (bit 0) or right (bit 1) record of a node.
+func (r *Reader) record(node, bit int) int {
+	switch r.metadata.RecordSize {
+	case 24:
+		b := r.buf[node*6+bit*3:]
+		return int(b[0])<<16 | int(b[1])<<8 | int(b[2])
+	case 28:
+		b := r.buf[node*7:]
+		if bit == 0 {
+			return int(b[3]&0xF0)<<20 | int(b[0])<<16 | int(b[1])<<8 | int(b[2])
+		}
+		return int(b[3]&0x0F)<<24 | int(b[4])<<16 | int(b[5])<<8 | int(b[6])
+	default:
+		b := r.buf[node*8+bit*4:]
+		return int(b[0])<<24 | int(b[1])<<16 | int(b[2])<<8 | int(b[3])
+	}
+}
+
+// path follows map keys through a decoded record.
+func path(value any, keys ...string) any {
+	for _, key := range keys {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
@@ -0,0 +1,93 @@
+package geoip
+
+import (
+	"bytes"
+	"net/netip"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The test database is generated by testdata/generate.go.
+const testDatabase = "testdata/GeoIP2-Country-Test.mmdb"
+
+func TestOpenMetadata(t *testing.T) {
+	r, err := Open(testDatabase)
+	require.NoError(t, err)
+
+	md := r.Metadata()
+	require.Equal(t, "GeoIP2-Country", md.DatabaseType)
+	require.Equal(t, 6, md.IPVersion)
+	require.Equal(t, 24, md.RecordSize)
+	require.Positive(t, md.NodeCount)
+}
+
+func TestCountry(t *testing.T) {
+	r, err := Open(testDatabase)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		ip   string
+		want string
+	}{
+		{name: "IPv4", ip: "81.2.69.142", want: "GB"},
+		{name: "IPv4 at network start", ip: "89.160.20.112", want: "SE"},
+		{name: "IPv4 at network end", ip: "216.160.83.63", want: "US"},
+		{name: "IPv4-mapped IPv6", ip: "::ffff:81.2.69.1", want: "GB"},
+		{name: "IPv6", ip: "2001:218:1234::1", want: "JP"},
+		{name: "Registered country only", ip: "67.43.156.7", want: "BT"},
+		{name: "Just outside a network", ip: "216.160.83.64", want: ""},
+		{name: "Unknown IPv4", ip: "192.0.2.1", want: ""},
+		{name: "Unknown IPv6", ip: "2001:db8::1", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Country(netip.MustParseAddr(tt.ip))
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCountryInvalidAddr(t *testing.T) {
+	r, err := Open(testDatabase)
+	require.NoError(t, err)
+
+	got, err := r.Country(netip.Addr{})
+	require.NoError(t, err)
+	require.Empty(t, got)
+}
+
+func TestFromBytesInvalid(t *testing.T) {
+	_, err := FromBytes([]byte("not a database"))
+	require.ErrorIs(t, err, ErrInvalidDatabase)
+
+	// Metadata without the search tree and data section it describes.
+	db, err := os.ReadFile(testDatabase)
+	require.NoError(t, err)
+	_, err = FromBytes(db[bytes.LastIndex(db, metadataMarker):])
+	require.ErrorIs(t, err, ErrInvalidDatabase)
+}
+
+func TestDecodePointers(t *testing.T) {
+	data := []byte{
+		// 0: map with 2 entries
+		0xe2,
+		// key "a", value: pointer to offset 12
+		0x41, 'a', 0x20, 12,
+		// key "b", value: pointer to offset 12
+		0x41, 'b', 0x20, 12,
+		// padding
+		0x00, 0x00, 0x00,
+		// 12: "xy"
+		0x42, 'x', 'y',
+	}
+
+	value, next, err := (&decoder{buf: data}).decode(0)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"a": "xy", "b": "xy"}, value)
+	require.Equal(t, 9, next)
+}
@@ -0,0 +1,222 @@
+//go:build ignore
+
+// Generates the test database used by the geoip tests:
+//
+//	go run ./internal/lib/geoip/testdata/generate.go
+//
+// The networks are documentation and test ranges; they say nothing about
+// where those addresses are really located.
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+)
+
+type network struct {
+	prefix string
+	record map[string]any
+}
+
+func country(code, name string) map[string]any {
+	return map[string]any{
+		"iso_code": code,
+		"names":    map[string]any{"en": name},
+	}
+}
+
+var networks = []network{
+	{"81.2.69.0/24", map[string]any{"country": country("GB", "United Kingdom")}},
+	{"89.160.20.112/28", map[string]any{"country": country("SE", "Sweden")}},
+	{"216.160.83.56/29", map[string]any{"country": country("US", "United States")}},
+	{"2001:218::/32", map[string]any{"country": country("JP", "Japan")}},
+	// Only the registered country is known, as for anycast networks.
+	{"67.43.156.0/24", map[string]any{"registered_country": country("BT", "Bhutan")}},
+}
+
+const recordSize = 24
+
+type node struct {
+	children [2]*node
+	data     [2]int // data section offset + 1 for leaves
+}
+
+func main() {
+	_, file, _, _ := runtime.Caller(0)
+	out := filepath.Join(filepath.Dir(file), "GeoIP2-Country-Test.mmdb")
+
+	var data bytes.Buffer
+	root := &node{}
+	for _, n := range networks {
+		prefix := netip.MustParsePrefix(n.prefix)
+		offset := data.Len()
+		encode(&data, n.record)
+		insert(root, prefix, offset)
+	}
+
+	nodes := number(root)
+	nodeCount := len(nodes)
+
+	var tree bytes.Buffer
+	for _, nd := range nodes {
+		var records [2]int
+		for bit := range 2 {
+			switch {
+			case nd.children[bit] != nil:
+				records[bit] = index(nodes, nd.children[bit])
+			case nd.data[bit] > 0:
+				records[bit] = nodeCount + 16 + nd.data[bit] - 1
+			default:
+				records[bit] = nodeCount
+			}
+		}
+		for _, r := range records {
+			tree.Write([]byte{byte(r >> 16), byte(r >> 8), byte(r)})
+		}
+	}
+
+	var meta bytes.Buffer
+	meta.WriteString("\xAB\xCD\xEFMaxMind.com")
+	encode(&meta, map[string]any{
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"build_epoch":                 uint64(1700000000),
+		"database_type":               "GeoIP2-Country",
+		"description":                 map[string]any{"en": "url-shortener test database"},
+		"ip_version":                  uint16(6),
+		"languages":                   []any{"en"},
+		"node_count":                  uint32(nodeCount),
+		"record_size":                 uint16(recordSize),
+	})
+
+	var db bytes.Buffer
+	db.Write(tree.Bytes())
+	db.Write(make([]byte, 16))
+	db.Write(data.Bytes())
+	db.Write(meta.Bytes())
+
+	if err := os.WriteFile(out, db.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// insert adds prefix to the IPv6 tree; IPv4 networks go under ::/96.
+func insert(root *node, prefix netip.Prefix, offset int) {
+	addr := prefix.Addr().As16()
+	bits := prefix.Bits()
+	if prefix.Addr().Is4() {
+		addr = [16]byte{}
+		v4 := prefix.Addr().As4()
+		copy(addr[12:], v4[:])
+		bits += 96
+	}
+
+	nd := root
+	for i := 0; i < bits; i++ {
+		bit := int(addr[i/8]>>(7-i%8)) & 1
+		if i == bits-1 {
+			nd.data[bit] = offset + 1
+			return
+		}
+		if nd.children[bit] == nil {
+			nd.children[bit] = &node{}
+		}
+		nd = nd.children[bit]
+	}
+}
+
+// number lists the nodes breadth first; the root gets index 0.
+func number(root *node) []*node {
+	nodes := []*node{root}
+	for i := 0; i < len(nodes); i++ {
+		for _, child := range nodes[i].children {
+			if child != nil {
+				nodes = append(nodes, child)
+			}
+		}
+	}
+	return nodes
+}
+
+func index(nodes []*node, target *node) int {
+	for i, nd := range nodes {
+		if nd == target {
+			return i
+		}
+	}
+	panic("node not found")
+}
+
+func control(buf *bytes.Buffer, typ, size int) {
+	var first byte
+	var ext []byte
+	if typ > 7 {
+		ext = []byte{byte(typ - 7)}
+	} else {
+		first = byte(typ << 5)
+	}
+
+	var sizeBytes []byte
+	switch {
+	case size < 29:
+		first |= byte(size)
+	case size < 285:
+		first |= 29
+		sizeBytes = []byte{byte(size - 29)}
+	default:
+		first |= 30
+		sizeBytes = binary.BigEndian.AppendUint16(nil, uint16(size-285))
+	}
+
+	buf.WriteByte(first)
+	buf.Write(ext)
+	buf.Write(sizeBytes)
+}
+
+func encode(buf *bytes.Buffer, value any) {
+	switch v := value.(type) {
+	case string:
+		control(buf, 2, len(v))
+		buf.WriteString(v)
+	case uint16:
+		b := trim(binary.BigEndian.AppendUint16(nil, v))
+		control(buf, 5, len(b))
+		buf.Write(b)
+	case uint32:
+		b := trim(binary.BigEndian.AppendUint32(nil, v))
+		control(buf, 6, len(b))
+		buf.Write(b)
+	case uint64:
+		b := trim(binary.BigEndian.AppendUint64(nil, v))
+		control(buf, 9, len(b))
+		buf.Write(b)
+	case []any:
+		control(buf, 11, len(v))
+		for _, item := range v {
+			encode(buf, item)
+		}
+	case map[string]any:
+		control(buf, 7, len(v))
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			encode(buf, k)
+			encode(buf, v[k])
+		}
+	default:
+		log.Fatalf("unsupported type %T", value)
+	}
+}
+
+func trim(b []byte) []byte {
+	return bytes.TrimLeft(b, "\x00")
+}
@@ -85,4 +85,58 @@ var (
 			Help:      "Total number of URL redirects performed",
 		},
 	)
+
+	BlockedURLsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "business",
+			Name:      "blocked_urls_total",
+			Help:      "Total number of blocklisted destinations refused",
+		},
+		[]string{"stage"}, // stage: shorten, redirect
+	)
+
+	UnlockAttemptsTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "business",
+			Name:      "unlock_attempts_total",
+			Help:      "Total number of password attempts on protected links",
+		},
+		[]string{"result"}, // result: success, wrong_password, throttled
+	)
+)
+
+// Health check metrics
+var (
+	HealthChecksTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "healthcheck",
+			Name:      "checks_total",
+			Help:      "Total number of destination health checks",
+		},
+		[]string{"result"}, // result: ok, broken, error
+	)
+
+	BrokenLinks = promauto.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "healthcheck",
+			Name:      "broken_links",
+			Help:      "Number of links whose destination failed its last health check",
+		},
+	)
+)
+
+// Trash metrics
+var (
+	PurgedLinksTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "trash",
+			Name:      "purged_links_total",
+			Help:      "Total number of deleted links purged from the trash by the purge job",
+		},
+	)
 )
@@ -0,0 +1,71 @@
+package sign
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var keyIDRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Key is a named signing key.
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// Keyring signs with its active key and verifies with any of its keys. Keys
+// are rotated by adding a new key, making it active, and removing the old key
+// once the signatures made with it have expired.
+type Keyring struct {
+	active  string
+	signers map[string]*Signer
+}
+
+// NewKeyring creates a Keyring from keys. active is the ID of the key used for
+// signing; empty selects the first key.
+func NewKeyring(keys []Key, active string) (*Keyring, error) {
+	const op = "lib.sign.NewKeyring"
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, errors.New("no keys"))
+	}
+	if active == "" {
+		active = keys[0].ID
+	}
+
+	signers := make(map[string]*Signer, len(keys))
+	for _, key := range keys {
+		if !keyIDRe.MatchString(key.ID) {
+			return nil, fmt.Errorf("%s: key id %q must be 1 to 32 letters, digits, '-' or '_'", op, key.ID)
+		}
+		if _, dup := signers[key.ID]; dup {
+			return nil, fmt.Errorf("%s: duplicate key id %q", op, key.ID)
+		}
+		if len(key.Secret) < MinKeyLength {
+			return nil, fmt.Errorf("%s: key %q is shorter than %d bytes", op, key.ID, MinKeyLength)
+		}
+		signers[key.ID] = New(key.Secret)
+	}
+
+	if _, ok := signers[active]; !ok {
+		return nil, fmt.Errorf("%s: unknown active key %q", op, active)
+	}
+
+	return &Keyring{active: active, signers: signers}, nil
+}
+
+// Sign signs message with the active key and returns the key ID with the signature.
+func (k *Keyring) Sign(message string) (keyID, signature string) {
+	return k.active, k.signers[k.active].Sign(message)
+}
+
+// Verify reports whether signature is a valid signature of message made with
+// the key keyID. Signatures of removed keys are rejected.
+func (k *Keyring) Verify(keyID, message, signature string) bool {
+	signer, ok := k.signers[keyID]
+	if !ok {
+		return false
+	}
+	return signer.Verify(message, signature)
+}
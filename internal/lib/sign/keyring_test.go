@@ -0,0 +1,64 @@
+package sign
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeyring(t *testing.T) {
+	secret := []byte(strings.Repeat("k", MinKeyLength))
+
+	tests := []struct {
+		name    string
+		keys    []Key
+		active  string
+		wantErr string
+	}{
+		{name: "First key is active by default", keys: []Key{{ID: "2025-01", Secret: secret}}},
+		{name: "Explicit active key", keys: []Key{{ID: "old", Secret: secret}, {ID: "new", Secret: secret}}, active: "new"},
+		{name: "No keys", wantErr: "no keys"},
+		{name: "Unknown active key", keys: []Key{{ID: "old", Secret: secret}}, active: "new", wantErr: `unknown active key "new"`},
+		{name: "Duplicate key id", keys: []Key{{ID: "a", Secret: secret}, {ID: "a", Secret: secret}}, wantErr: `duplicate key id "a"`},
+		{name: "Short secret", keys: []Key{{ID: "a", Secret: []byte("short")}}, wantErr: "shorter than 32 bytes"},
+		{name: "Invalid key id", keys: []Key{{ID: "a b", Secret: secret}}, wantErr: `key id "a b"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeyring(tt.keys, tt.active)
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	oldKey := Key{ID: "old", Secret: []byte(strings.Repeat("o", MinKeyLength))}
+	newKey := Key{ID: "new", Secret: []byte(strings.Repeat("n", MinKeyLength))}
+
+	before, err := NewKeyring([]Key{oldKey}, "")
+	require.NoError(t, err)
+	keyID, signature := before.Sign("message")
+	require.Equal(t, "old", keyID)
+
+	// The new key is active, the old one still verifies.
+	during, err := NewKeyring([]Key{oldKey, newKey}, "new")
+	require.NoError(t, err)
+	require.True(t, during.Verify(keyID, "message", signature))
+
+	newID, newSignature := during.Sign("message")
+	require.Equal(t, "new", newID)
+	require.True(t, during.Verify(newID, "message", newSignature))
+	require.False(t, during.Verify(newID, "message", signature), "signatures are bound to their key")
+
+	// Removing the old key revokes its signatures.
+	after, err := NewKeyring([]Key{newKey}, "")
+	require.NoError(t, err)
+	require.False(t, after.Verify(keyID, "message", signature))
+	require.True(t, after.Verify(newID, "message", newSignature))
+}
@@ -0,0 +1,34 @@
+package sign
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignerVerify(t *testing.T) {
+	signer := New([]byte("0123456789abcdef0123456789abcdef"))
+	signature := signer.Sign("unlock:docs")
+
+	tests := []struct {
+		name      string
+		signer    *Signer
+		message   string
+		signature string
+		want      bool
+	}{
+		{name: "Valid signature", signer: signer, message: "unlock:docs", signature: signature, want: true},
+		{name: "Other message", signer: signer, message: "unlock:other", signature: signature},
+		{name: "Other key", signer: New([]byte("fedcba9876543210fedcba9876543210")), message: "unlock:docs", signature: signature},
+		{name: "Random key", signer: NewRandom(), message: "unlock:docs", signature: signature},
+		{name: "Truncated signature", signer: signer, message: "unlock:docs", signature: signature[:10]},
+		{name: "Not base64", signer: signer, message: "unlock:docs", signature: "!!!"},
+		{name: "Empty signature", signer: signer, message: "unlock:docs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, tt.signer.Verify(tt.message, tt.signature))
+		})
+	}
+}
@@ -0,0 +1,85 @@
+// Package throttle limits how often an operation may be attempted per key,
+// e.g. per link and client address, to slow down brute-force attempts.
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter allows up to limit attempts per key within a window that starts at
+// the first attempt. Once the limit is reached the key is locked until the
+// window ends. State is kept in memory.
+type Limiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*entry
+	lastSweep time.Time
+}
+
+type entry struct {
+	attempts int
+	start    time.Time
+}
+
+// New creates a Limiter allowing limit attempts per key within window.
+func New(limit int, window time.Duration) *Limiter {
+	return &Limiter{
+		limit:   limit,
+		window:  window,
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+}
+
+// Attempt counts an attempt of key as a failure unless key is locked, and
+// reports whether the attempt may go ahead and, if not, how long key has to
+// wait. Checking and counting at once keeps concurrent attempts from all
+// passing the check before any of them failed; callers Reset key after a
+// successful attempt.
+func (l *Limiter) Attempt(key string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	e, ok := l.entries[key]
+	if !ok || now.Sub(e.start) >= l.window {
+		e = &entry{start: now}
+		l.entries[key] = e
+	}
+
+	if e.attempts >= l.limit {
+		return e.start.Add(l.window).Sub(now), false
+	}
+	e.attempts++
+
+	return 0, true
+}
+
+// Reset forgets the attempts of key, e.g. after a successful one.
+func (l *Limiter) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.entries, key)
+}
+
+// sweep drops expired entries at most once per window so that keys that
+// stopped trying do not pile up.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	l.lastSweep = now
+
+	for key, e := range l.entries {
+		if now.Sub(e.start) >= l.window {
+			delete(l.entries, key)
+		}
+	}
+}
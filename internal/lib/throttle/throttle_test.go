@@ -0,0 +1,99 @@
+package throttle
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterAttempt(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := New(3, time.Minute)
+	l.now = func() time.Time { return now }
+
+	for range 3 {
+		_, ok := l.Attempt("docs|192.0.2.1")
+		require.True(t, ok)
+	}
+
+	retryAfter, ok := l.Attempt("docs|192.0.2.1")
+	require.False(t, ok)
+	require.Equal(t, time.Minute, retryAfter)
+
+	// Other keys are not affected.
+	_, ok = l.Attempt("docs|192.0.2.2")
+	require.True(t, ok)
+
+	now = now.Add(40 * time.Second)
+	retryAfter, ok = l.Attempt("docs|192.0.2.1")
+	require.False(t, ok)
+	require.Equal(t, 20*time.Second, retryAfter)
+
+	now = now.Add(20 * time.Second)
+	_, ok = l.Attempt("docs|192.0.2.1")
+	require.True(t, ok, "attempts from an expired window must not count")
+}
+
+func TestLimiterReset(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := New(2, time.Minute)
+	l.now = func() time.Time { return now }
+
+	for range 2 {
+		_, ok := l.Attempt("key")
+		require.True(t, ok)
+	}
+	_, ok := l.Attempt("key")
+	require.False(t, ok)
+
+	l.Reset("key")
+	for range 2 {
+		_, ok = l.Attempt("key")
+		require.True(t, ok)
+	}
+}
+
+func TestLimiterSweep(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := New(5, time.Minute)
+	l.now = func() time.Time { return now }
+
+	l.Attempt("a")
+	l.Attempt("b")
+	now = now.Add(2 * time.Minute)
+	l.Attempt("c")
+
+	require.Len(t, l.entries, 1)
+}
+
+func TestLimiterAttemptConcurrent(t *testing.T) {
+	const (
+		limit    = 5
+		attempts = 100
+	)
+
+	l := New(limit, time.Minute)
+
+	var (
+		wg      sync.WaitGroup
+		allowed atomic.Int32
+	)
+	start := make(chan struct{})
+	for range attempts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if _, ok := l.Attempt("docs|192.0.2.1"); ok {
+				allowed.Add(1)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	require.Equal(t, int32(limit), allowed.Load())
+}
@@ -0,0 +1,82 @@
+package useragent
+
+import "strings"
+
+// Operating systems reported in Info.OS.
+const (
+	OSIOS      = "ios"
+	OSAndroid  = "android"
+	OSWindows  = "windows"
+	OSMacOS    = "macos"
+	OSLinux    = "linux"
+	OSChromeOS = "chromeos"
+	OSOther    = "other"
+)
+
+// Device classes reported in Info.Device.
+const (
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+)
+
+// Info is what could be told about a client from its User-Agent header.
+type Info struct {
+	OS     string
+	Device string
+}
+
+// botMarkers are substrings found in the User-Agent of crawlers, link
+// unfurlers and command-line clients.
+var botMarkers = []string{
+	"bot", "crawler", "spider", "slurp", "facebookexternalhit", "embedly",
+	"preview", "curl/", "wget/", "python-requests", "go-http-client", "headless",
+}
+
+// Parse classifies a User-Agent header by operating system and device class.
+// It only looks for well-known tokens and never fails; unrecognized clients
+// are reported as OSOther and DeviceUnknown.
+func Parse(userAgent string) Info {
+	ua := strings.ToLower(userAgent)
+	if ua == "" {
+		return Info{OS: OSOther, Device: DeviceUnknown}
+	}
+
+	info := Info{OS: OSOther, Device: DeviceUnknown}
+
+	switch {
+	case strings.Contains(ua, "iphone") || strings.Contains(ua, "ipod"):
+		info = Info{OS: OSIOS, Device: DeviceMobile}
+	case strings.Contains(ua, "ipad"):
+		info = Info{OS: OSIOS, Device: DeviceTablet}
+	case strings.Contains(ua, "android"):
+		// Android phones send "Mobile"; tablets usually do not.
+		info = Info{OS: OSAndroid, Device: DeviceTablet}
+		if strings.Contains(ua, "mobile") {
+			info.Device = DeviceMobile
+		}
+	case strings.Contains(ua, "windows phone"):
+		info = Info{OS: OSWindows, Device: DeviceMobile}
+	case strings.Contains(ua, "windows"):
+		info = Info{OS: OSWindows, Device: DeviceDesktop}
+	case strings.Contains(ua, "cros "):
+		// The trailing space keeps words like "Microsoft" from matching.
+		info = Info{OS: OSChromeOS, Device: DeviceDesktop}
+	case strings.Contains(ua, "macintosh") || strings.Contains(ua, "mac os x"):
+		// iPadOS Safari presents itself as a Mac by default, so it lands here.
+		info = Info{OS: OSMacOS, Device: DeviceDesktop}
+	case strings.Contains(ua, "linux") || strings.Contains(ua, "x11"):
+		info = Info{OS: OSLinux, Device: DeviceDesktop}
+	}
+
+	for _, marker := range botMarkers {
+		if strings.Contains(ua, marker) {
+			info.Device = DeviceBot
+			break
+		}
+	}
+
+	return info
+}
@@ -0,0 +1,87 @@
+package useragent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		ua   string
+		want Info
+	}{
+		{
+			name: "iPhone Safari",
+			ua:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+			want: Info{OS: OSIOS, Device: DeviceMobile},
+		},
+		{
+			name: "iPad Safari",
+			ua:   "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1",
+			want: Info{OS: OSIOS, Device: DeviceTablet},
+		},
+		{
+			name: "Android phone Chrome",
+			ua:   "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36",
+			want: Info{OS: OSAndroid, Device: DeviceMobile},
+		},
+		{
+			name: "Android tablet",
+			ua:   "Mozilla/5.0 (Linux; Android 13; SM-X700) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			want: Info{OS: OSAndroid, Device: DeviceTablet},
+		},
+		{
+			name: "Windows Edge",
+			ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0",
+			want: Info{OS: OSWindows, Device: DeviceDesktop},
+		},
+		{
+			name: "macOS Firefox",
+			ua:   "Mozilla/5.0 (Macintosh; Intel Mac OS X 14.4; rv:125.0) Gecko/20100101 Firefox/125.0",
+			want: Info{OS: OSMacOS, Device: DeviceDesktop},
+		},
+		{
+			name: "Linux Firefox",
+			ua:   "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:125.0) Gecko/20100101 Firefox/125.0",
+			want: Info{OS: OSLinux, Device: DeviceDesktop},
+		},
+		{
+			name: "ChromeOS",
+			ua:   "Mozilla/5.0 (X11; CrOS x86_64 14541.0.0) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+			want: Info{OS: OSChromeOS, Device: DeviceDesktop},
+		},
+		{
+			name: "Microsoft Office on macOS",
+			ua:   "Microsoft Office/16.0 (Macintosh; Mac OS X 10.15; Microsoft Outlook 16.80.23121017; Pro)",
+			want: Info{OS: OSMacOS, Device: DeviceDesktop},
+		},
+		{
+			name: "Googlebot smartphone",
+			ua:   "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)",
+			want: Info{OS: OSAndroid, Device: DeviceBot},
+		},
+		{
+			name: "curl",
+			ua:   "curl/8.5.0",
+			want: Info{OS: OSOther, Device: DeviceBot},
+		},
+		{
+			name: "Empty",
+			ua:   "",
+			want: Info{OS: OSOther, Device: DeviceUnknown},
+		},
+		{
+			name: "Unknown",
+			ua:   "SomeTV/1.0",
+			want: Info{OS: OSOther, Device: DeviceUnknown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Parse(tt.ua))
+		})
+	}
+}
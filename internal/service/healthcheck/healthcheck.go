@@ -0,0 +1,284 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"syscall"
+	"time"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/lib/metrics"
+)
+
+// Provider defines the storage operations the health checker needs.
+type Provider interface {
+	URLsToCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]domain.Link, error)
+	SaveHealthCheck(ctx context.Context, alias string, statusCode int, checkedAt time.Time, checkErr string) error
+	CountBrokenURLs(ctx context.Context) (int, error)
+}
+
+// Options configures a Checker.
+type Options struct {
+	// Interval is the pause between two checking passes; non-positive uses
+	// DefaultInterval.
+	Interval time.Duration
+	// RecheckAfter is how old the last check of a link must be before it is checked again.
+	RecheckAfter time.Duration
+	// BatchSize is the maximum number of links checked in one pass.
+	BatchSize int
+	// Concurrency bounds the number of requests in flight.
+	Concurrency int
+	// HostDelay is the minimum pause between two requests to the same host.
+	HostDelay time.Duration
+	// Timeout bounds a single check, including a GET fallback.
+	Timeout time.Duration
+	// UserAgent is sent with every check request.
+	UserAgent string
+	// AllowPrivateIPs lets the checker connect to private, loopback and
+	// link-local addresses, which are refused by default.
+	AllowPrivateIPs bool
+}
+
+// DefaultInterval is the pause between two checking passes when Options has none.
+const DefaultInterval = time.Minute
+
+// Checker periodically requests stored destinations and records the outcome.
+type Checker struct {
+	log      *slog.Logger
+	provider Provider
+	client   *http.Client
+	opts     Options
+
+	mu    sync.Mutex
+	hosts map[string]*hostGate
+}
+
+// hostGate serializes requests to one host and spaces them by HostDelay.
+type hostGate struct {
+	mu   sync.Mutex
+	next time.Time
+}
+
+// New creates a health Checker.
+func New(log *slog.Logger, provider Provider, opts Options) *Checker {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 100
+	}
+
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !opts.AllowPrivateIPs {
+		dialer.Control = refusePrivateAddress
+		// Through a proxy the dialer would only see the proxy's address.
+		transport.Proxy = nil
+	}
+	transport.DialContext = dialer.DialContext
+
+	return &Checker{
+		log:      log.With(slog.String("component", "healthcheck")),
+		provider: provider,
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   opts.Timeout,
+		},
+		opts:  opts,
+		hosts: make(map[string]*hostGate),
+	}
+}
+
+// Run checks links every Interval until ctx is cancelled.
+func (c *Checker) Run(ctx context.Context) {
+	c.log.Info("link health checker started", slog.Duration("interval", c.opts.Interval))
+
+	ticker := time.NewTicker(c.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := c.CheckOnce(ctx); err != nil && ctx.Err() == nil {
+			c.log.Error("health check pass failed", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			c.log.Info("link health checker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckOnce checks one batch of links that are due and updates the broken links gauge.
+func (c *Checker) CheckOnce(ctx context.Context) error {
+	const op = "healthcheck.Checker.CheckOnce"
+
+	links, err := c.provider.URLsToCheck(ctx, time.Now().Add(-c.opts.RecheckAfter), c.opts.BatchSize)
+	if err != nil {
+		return fmt.Errorf("%s: failed to get links to check: %w", op, err)
+	}
+
+	jobs := make(chan domain.Link)
+	var wg sync.WaitGroup
+
+	for range min(c.opts.Concurrency, len(links)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for link := range jobs {
+				c.checkLink(ctx, link)
+			}
+		}()
+	}
+
+feed:
+	for _, link := range links {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- link:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Politeness only matters within a pass; drop the per-host state so the
+	// map does not grow with every host ever checked.
+	c.mu.Lock()
+	c.hosts = make(map[string]*hostGate)
+	c.mu.Unlock()
+
+	broken, err := c.provider.CountBrokenURLs(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: failed to count broken links: %w", op, err)
+	}
+	metrics.BrokenLinks.Set(float64(broken))
+
+	return nil
+}
+
+func (c *Checker) checkLink(ctx context.Context, link domain.Link) {
+	log := c.log.With(slog.String("alias", link.Alias))
+
+	statusCode, checkErr := c.check(ctx, link.URL)
+	if ctx.Err() != nil {
+		return
+	}
+
+	var errMsg string
+	result := "ok"
+	switch {
+	case checkErr != nil:
+		errMsg = checkErr.Error()
+		result = "error"
+	case domain.IsBrokenStatus(statusCode):
+		result = "broken"
+	}
+	metrics.HealthChecksTotal.WithLabelValues(result).Inc()
+
+	if result != "ok" {
+		log.Info("destination is broken", slog.Int("status_code", statusCode), slog.String("error", errMsg))
+	}
+
+	if err := c.provider.SaveHealthCheck(ctx, link.Alias, statusCode, time.Now(), errMsg); err != nil {
+		log.Error("failed to save health check", slog.String("error", err.Error()))
+	}
+}
+
+// check requests rawURL with HEAD, falling back to GET for servers that do not
+// support HEAD, and returns the final status code.
+func (c *Checker) check(ctx context.Context, rawURL string) (int, error) {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, err
+	}
+
+	gate := c.gate(parsedURL.Host)
+	gate.mu.Lock()
+	defer gate.mu.Unlock()
+
+	if wait := time.Until(gate.next); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return 0, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	defer func() { gate.next = time.Now().Add(c.opts.HostDelay) }()
+
+	statusCode, err := c.do(ctx, http.MethodHead, rawURL)
+	if err == nil && statusCode != http.StatusMethodNotAllowed && statusCode != http.StatusNotImplemented {
+		return statusCode, nil
+	}
+
+	return c.do(ctx, http.MethodGet, rawURL)
+}
+
+func (c *Checker) do(ctx context.Context, method, rawURL string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, nil)
+	if err != nil {
+		return 0, err
+	}
+	if c.opts.UserAgent != "" {
+		req.Header.Set("User-Agent", c.opts.UserAgent)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	// Drain a little of the body so the connection can be reused.
+	_, _ = io.CopyN(io.Discard, res.Body, 4096)
+
+	return res.StatusCode, nil
+}
+
+func (c *Checker) gate(host string) *hostGate {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	g, ok := c.hosts[host]
+	if !ok {
+		g = &hostGate{}
+		c.hosts[host] = g
+	}
+	return g
+}
+
+var errPrivateAddress = errors.New("refusing to connect to a private address")
+
+// refusePrivateAddress keeps the checker from probing internal services when a
+// destination host resolves to a private, loopback or link-local address. The
+// dialer passes it resolved addresses, so anything that is not an IP is
+// refused as well.
+func refusePrivateAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: %q is not an IP address", errPrivateAddress, host)
+	}
+
+	if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return errPrivateAddress
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package healthcheck
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	domain "url-shortener/internal/domain/url"
+
+	"github.com/stretchr/testify/require"
+)
+
+type result struct {
+	statusCode int
+	checkErr   string
+}
+
+// fakeProvider keeps links in memory and records saved health checks.
+type fakeProvider struct {
+	mu      sync.Mutex
+	links   []domain.Link
+	results map[string]result
+}
+
+func (p *fakeProvider) URLsToCheck(_ context.Context, _ time.Time, limit int) ([]domain.Link, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.links[:min(limit, len(p.links))], nil
+}
+
+func (p *fakeProvider) SaveHealthCheck(_ context.Context, alias string, statusCode int, _ time.Time, checkErr string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.results[alias] = result{statusCode: statusCode, checkErr: checkErr}
+	return nil
+}
+
+func (p *fakeProvider) CountBrokenURLs(_ context.Context) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	broken := 0
+	for _, r := range p.results {
+		if domain.IsBrokenStatus(r.statusCode) {
+			broken++
+		}
+	}
+	return broken, nil
+}
+
+func newTestChecker(provider Provider, opts Options) *Checker {
+	opts.AllowPrivateIPs = true // httptest servers listen on loopback
+	if opts.Timeout == 0 {
+		opts.Timeout = 2 * time.Second
+	}
+	return New(slog.New(slog.NewTextHandler(io.Discard, nil)), provider, opts)
+}
+
+func TestCheckOnceRecordsStatusCodes(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodHead, r.Method)
+		require.Equal(t, "test-agent", r.UserAgent())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ok.Close()
+
+	gone := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer gone.Close()
+
+	// Some servers reject HEAD; the checker must retry with GET.
+	noHead := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer noHead.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	provider := &fakeProvider{
+		links: []domain.Link{
+			{Alias: "ok", URL: ok.URL},
+			{Alias: "gone", URL: gone.URL + "/missing"},
+			{Alias: "nohead", URL: noHead.URL},
+			{Alias: "closed", URL: closedURL},
+		},
+		results: make(map[string]result),
+	}
+
+	checker := newTestChecker(provider, Options{Concurrency: 4, BatchSize: 10, UserAgent: "test-agent"})
+	require.NoError(t, checker.CheckOnce(context.Background()))
+
+	require.Equal(t, http.StatusOK, provider.results["ok"].statusCode)
+	require.Equal(t, http.StatusNotFound, provider.results["gone"].statusCode)
+	require.Equal(t, http.StatusOK, provider.results["nohead"].statusCode)
+	require.Equal(t, 0, provider.results["closed"].statusCode)
+	require.NotEmpty(t, provider.results["closed"].checkErr)
+}
+
+func TestCheckOnceBoundsConcurrencyAndSpacesRequestsPerHost(t *testing.T) {
+	const hostDelay = 50 * time.Millisecond
+
+	var (
+		inFlight, maxInFlight atomic.Int32
+		mu                    sync.Mutex
+		times                 []time.Time
+	)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	provider := &fakeProvider{results: make(map[string]result)}
+	for _, alias := range []string{"a", "b", "c", "d"} {
+		provider.links = append(provider.links, domain.Link{Alias: alias, URL: srv.URL + "/" + alias})
+	}
+
+	checker := newTestChecker(provider, Options{Concurrency: 4, BatchSize: 10, HostDelay: hostDelay})
+	require.NoError(t, checker.CheckOnce(context.Background()))
+
+	require.Len(t, provider.results, 4)
+	// All links share one host, so requests must not overlap...
+	require.Equal(t, int32(1), maxInFlight.Load())
+	// ...and must be spaced by the host delay.
+	for i := 1; i < len(times); i++ {
+		require.GreaterOrEqual(t, times[i].Sub(times[i-1]), hostDelay)
+	}
+}
+
+func TestCheckOnceRespectsBatchSize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	provider := &fakeProvider{results: make(map[string]result)}
+	for _, alias := range []string{"a", "b", "c"} {
+		provider.links = append(provider.links, domain.Link{Alias: alias, URL: srv.URL})
+	}
+
+	checker := newTestChecker(provider, Options{Concurrency: 2, BatchSize: 2})
+	require.NoError(t, checker.CheckOnce(context.Background()))
+
+	require.Len(t, provider.results, 2)
+}
+
+func TestCheckerRefusesPrivateAddressesByDefault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("checker must not reach a loopback server")
+	}))
+	defer srv.Close()
+
+	provider := &fakeProvider{
+		links:   []domain.Link{{Alias: "internal", URL: srv.URL}},
+		results: make(map[string]result),
+	}
+
+	checker := New(slog.New(slog.NewTextHandler(io.Discard, nil)), provider, Options{Timeout: time.Second})
+	require.Nil(t, checker.client.Transport.(*http.Transport).Proxy, "a proxy would hide the destination address")
+	require.NoError(t, checker.CheckOnce(context.Background()))
+
+	require.Equal(t, 0, provider.results["internal"].statusCode)
+	require.Contains(t, provider.results["internal"].checkErr, errPrivateAddress.Error())
+}
+
+func TestRefusePrivateAddress(t *testing.T) {
+	cases := []struct {
+		name    string
+		address string
+		refused bool
+	}{
+		{name: "Public IPv4", address: "93.184.216.34:80"},
+		{name: "Public IPv6", address: "[2606:2800:220:1::]:443"},
+		{name: "Private IPv4", address: "10.0.0.1:80", refused: true},
+		{name: "Loopback IPv6", address: "[::1]:443", refused: true},
+		{name: "Link-local", address: "169.254.169.254:80", refused: true},
+		{name: "Host name", address: "example.com:80", refused: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := refusePrivateAddress("tcp", tc.address, nil)
+			if tc.refused {
+				require.ErrorIs(t, err, errPrivateAddress)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestRunWithoutInterval(t *testing.T) {
+	provider := &fakeProvider{results: make(map[string]result)}
+
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		checker := newTestChecker(provider, Options{Interval: interval})
+		require.Equal(t, DefaultInterval, checker.opts.Interval)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.NotPanics(t, func() { checker.Run(ctx) })
+	}
+}
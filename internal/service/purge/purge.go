@@ -0,0 +1,134 @@
+package purge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/lib/metrics"
+)
+
+// Provider defines the storage operations the purge job needs.
+type Provider interface {
+	DeletedURLsBefore(ctx context.Context, deletedBefore time.Time, limit int) ([]domain.Link, error)
+	PurgeURL(ctx context.Context, alias string, releasedAt time.Time) error
+	ReleaseAliases(ctx context.Context, now time.Time) (int, error)
+	AppendAudit(ctx context.Context, entry audit.Entry) error
+}
+
+// Options configures a Purger.
+type Options struct {
+	// Interval is the pause between two purging passes; non-positive uses
+	// DefaultInterval.
+	Interval time.Duration
+	// Retention is how long deleted links stay in the trash.
+	Retention time.Duration
+	// Quarantine is how long the alias of a deleted link stays reserved after
+	// the deletion.
+	Quarantine time.Duration
+	// BatchSize is the maximum number of links purged in one pass.
+	BatchSize int
+}
+
+// DefaultInterval is the pause between two purging passes when Options has none.
+const DefaultInterval = time.Hour
+
+// Purger periodically removes links that stayed in the trash longer than the
+// retention period and releases aliases whose quarantine has ended.
+type Purger struct {
+	log      *slog.Logger
+	provider Provider
+	opts     Options
+}
+
+// New creates a Purger.
+func New(log *slog.Logger, provider Provider, opts Options) *Purger {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 100
+	}
+
+	return &Purger{
+		log:      log.With(slog.String("component", "purge")),
+		provider: provider,
+		opts:     opts,
+	}
+}
+
+// Run purges the trash every Interval until ctx is cancelled.
+func (p *Purger) Run(ctx context.Context) {
+	p.log.Info("trash purger started", slog.Duration("interval", p.opts.Interval), slog.Duration("retention", p.opts.Retention))
+
+	ticker := time.NewTicker(p.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := p.PurgeOnce(ctx); err != nil && ctx.Err() == nil {
+			p.log.Error("purge pass failed", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			p.log.Info("trash purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeOnce purges one batch of links whose retention has ended and releases
+// the aliases whose quarantine has ended.
+func (p *Purger) PurgeOnce(ctx context.Context) error {
+	const op = "purge.Purger.PurgeOnce"
+
+	now := time.Now()
+
+	links, err := p.provider.DeletedURLsBefore(ctx, now.Add(-p.opts.Retention), p.opts.BatchSize)
+	if err != nil {
+		return fmt.Errorf("%s: failed to get links to purge: %w", op, err)
+	}
+
+	for _, link := range links {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err = p.provider.PurgeURL(ctx, link.Alias, link.DeletedAt.Add(p.opts.Quarantine)); err != nil {
+			p.log.Error("failed to purge link", slog.String("alias", link.Alias), slog.String("error", err.Error()))
+			continue
+		}
+		metrics.PurgedLinksTotal.Inc()
+		p.log.Info("link purged", slog.String("alias", link.Alias), slog.String("deleted_by", link.DeletedBy))
+		p.record(ctx, link)
+	}
+
+	released, err := p.provider.ReleaseAliases(ctx, now)
+	if err != nil {
+		return fmt.Errorf("%s: failed to release aliases: %w", op, err)
+	}
+	if released > 0 {
+		p.log.Info("aliases released from quarantine", slog.Int("count", released))
+	}
+
+	return nil
+}
+
+// record appends the purge of the link to the audit log. The entry has no
+// actor since the purge was not requested by anyone.
+func (p *Purger) record(ctx context.Context, link domain.Link) {
+	entry := audit.NewEntry(ctx, audit.ActionPurge, link.Alias)
+
+	var err error
+	if entry.Before, err = json.Marshal(link); err != nil {
+		p.log.Error("failed to encode audit snapshot", slog.String("alias", link.Alias), slog.String("error", err.Error()))
+	}
+
+	if err = p.provider.AppendAudit(ctx, entry); err != nil {
+		p.log.Error("failed to record audit entry", slog.String("alias", link.Alias), slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,142 @@
+package purge
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider keeps deleted links in memory and records purges.
+type fakeProvider struct {
+	mu          sync.Mutex
+	links       []domain.Link
+	failAlias   string
+	purged      map[string]time.Time
+	deletedCut  time.Time
+	releasedCut time.Time
+	audited     []audit.Entry
+}
+
+func (p *fakeProvider) DeletedURLsBefore(_ context.Context, deletedBefore time.Time, limit int) ([]domain.Link, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.deletedCut = deletedBefore
+	var due []domain.Link
+	for _, link := range p.links {
+		if link.DeletedAt.Before(deletedBefore) && len(due) < limit {
+			due = append(due, link)
+		}
+	}
+	return due, nil
+}
+
+func (p *fakeProvider) PurgeURL(_ context.Context, alias string, releasedAt time.Time) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if alias == p.failAlias {
+		return errors.New("database is locked")
+	}
+	p.purged[alias] = releasedAt
+	return nil
+}
+
+func (p *fakeProvider) ReleaseAliases(_ context.Context, now time.Time) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.releasedCut = now
+	return 0, nil
+}
+
+func (p *fakeProvider) AppendAudit(_ context.Context, entry audit.Entry) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.audited = append(p.audited, entry)
+	return nil
+}
+
+func deletedAgo(alias string, ago time.Duration) domain.Link {
+	deletedAt := time.Now().Add(-ago)
+	return domain.Link{Alias: alias, DeletedAt: &deletedAt, DeletedBy: "owner@example.com"}
+}
+
+func TestPurgeOnce(t *testing.T) {
+	provider := &fakeProvider{
+		links: []domain.Link{
+			deletedAgo("old", 40*24*time.Hour),
+			deletedAgo("broken", 35*24*time.Hour),
+			deletedAgo("older", 31*24*time.Hour),
+			deletedAgo("recent", time.Hour),
+		},
+		failAlias: "broken",
+		purged:    make(map[string]time.Time),
+	}
+
+	purger := New(slog.New(slog.NewTextHandler(io.Discard, nil)), provider, Options{
+		Interval:   time.Hour,
+		Retention:  30 * 24 * time.Hour,
+		Quarantine: 90 * 24 * time.Hour,
+		BatchSize:  10,
+	})
+
+	start := time.Now()
+	require.NoError(t, purger.PurgeOnce(context.Background()))
+
+	require.Len(t, provider.purged, 2)
+	require.Contains(t, provider.purged, "old")
+	require.Contains(t, provider.purged, "older")
+	require.NotContains(t, provider.purged, "recent")
+
+	// The quarantine counts from the deletion, not from the purge.
+	require.Equal(t, provider.links[0].DeletedAt.Add(90*24*time.Hour), provider.purged["old"])
+
+	require.WithinDuration(t, start.Add(-30*24*time.Hour), provider.deletedCut, time.Second)
+	require.WithinDuration(t, start, provider.releasedCut, time.Second)
+
+	require.Len(t, provider.audited, 2)
+	for _, entry := range provider.audited {
+		require.Equal(t, audit.ActionPurge, entry.Action)
+		require.Empty(t, entry.ActorEmail)
+		require.NotEmpty(t, entry.Before)
+		require.Empty(t, entry.After)
+	}
+}
+
+func TestPurgeOnceBatchSize(t *testing.T) {
+	provider := &fakeProvider{
+		links: []domain.Link{
+			deletedAgo("a", 40*24*time.Hour),
+			deletedAgo("b", 40*24*time.Hour),
+			deletedAgo("c", 40*24*time.Hour),
+		},
+		purged: make(map[string]time.Time),
+	}
+
+	purger := New(slog.New(slog.NewTextHandler(io.Discard, nil)), provider, Options{
+		Retention: 30 * 24 * time.Hour,
+		BatchSize: 2,
+	})
+
+	require.NoError(t, purger.PurgeOnce(context.Background()))
+	require.Len(t, provider.purged, 2)
+}
+
+func TestRunWithoutInterval(t *testing.T) {
+	provider := &fakeProvider{purged: make(map[string]time.Time)}
+
+	for _, interval := range []time.Duration{0, -time.Hour} {
+		purger := New(slog.New(slog.NewTextHandler(io.Discard, nil)), provider, Options{Interval: interval})
+		require.Equal(t, DefaultInterval, purger.opts.Interval)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.NotPanics(t, func() { purger.Run(ctx) })
+	}
+}
@@ -0,0 +1,79 @@
+package url
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+)
+
+// AuditLog returns the audit log entries matching the filter, newest first.
+// Only admins may read the audit log.
+func (s *Service) AuditLog(ctx context.Context, requesterID int64, filter audit.Filter) ([]audit.Entry, error) {
+	const op = "url.Service.AuditLog"
+
+	if err := filter.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.requireAdmin(ctx, requesterID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	entries, err := s.provider.AuditLog(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get audit log: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+// record appends an action on the link to the audit log with snapshots of the
+// link before and after it; either may be nil. The operation has already
+// happened, so a failure is logged rather than returned.
+func (s *Service) record(ctx context.Context, action, alias string, asAdmin bool, before, after *domain.Link) {
+	log := s.log.With(slog.String("action", action), slog.String("alias", alias))
+
+	entry := audit.NewEntry(ctx, action, alias)
+	entry.AsAdmin = asAdmin
+	entry.Before = snapshot(log, before)
+	entry.After = snapshot(log, after)
+
+	s.appendAudit(ctx, log, entry)
+}
+
+// recordFolder appends an action on the folder with the given ID to the audit
+// log, like record does for links.
+func (s *Service) recordFolder(ctx context.Context, action string, id int64, asAdmin bool, before, after *domain.Folder) {
+	log := s.log.With(slog.String("action", action), slog.Int64("folder_id", id))
+
+	entry := audit.NewSubjectEntry(ctx, action, audit.SubjectFolder, id)
+	entry.AsAdmin = asAdmin
+	entry.Before = snapshot(log, before)
+	entry.After = snapshot(log, after)
+
+	s.appendAudit(ctx, log, entry)
+}
+
+func (s *Service) appendAudit(ctx context.Context, log *slog.Logger, entry audit.Entry) {
+	if err := s.provider.AppendAudit(ctx, entry); err != nil {
+		log.Error("failed to record audit entry", slog.String("error", err.Error()))
+	}
+}
+
+// snapshot encodes v for an audit entry; a nil v has no snapshot.
+func snapshot[T any](log *slog.Logger, v *T) json.RawMessage {
+	if v == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		log.Error("failed to encode audit snapshot", slog.String("error", err.Error()))
+		return nil
+	}
+
+	return raw
+}
@@ -5,40 +5,43 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
+	"url-shortener/internal/domain/audit"
 	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
 	"url-shortener/internal/storage"
 )
 
+// Delete moves the link to the trash, from where it can be restored until it
+// is purged. Only the owner, workspace editors and admins may delete a link.
 func (s *Service) Delete(ctx context.Context, alias, requesterEmail string, requesterID int64) error {
 	const op = "url.Service.Delete"
 
-	ownerEmail, err := s.provider.UrlOwner(ctx, alias)
+	link, err := s.provider.Link(ctx, alias)
 	if err != nil {
 		if errors.Is(err, storage.ErrURLNotFound) {
 			return fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
 		}
-		return fmt.Errorf("%s: failed to get url owner: %w", op, err)
+		return fmt.Errorf("%s: failed to get link: %w", op, err)
 	}
 
-	if ownerEmail != requesterEmail {
-		isAdmin, err := s.adminChecker.IsAdmin(ctx, requesterID)
-		if err != nil {
-			return fmt.Errorf("%s: failed to check admin status: %w", op, err)
-		}
-
-		if !isAdmin {
-			return fmt.Errorf("%s: %w", op, domain.ErrPermissionDenied)
-		}
+	asAdmin, err := s.authorize(ctx, link.Owner(), workspace.RoleEditor, requesterEmail, requesterID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
-		s.log.Info("admin deleting url", slog.String("alias", alias), slog.String("owner", ownerEmail))
+	if asAdmin {
+		s.log.Info("admin deleting url", slog.String("alias", alias), slog.String("owner", link.OwnerEmail))
 	}
 
-	if err = s.provider.DeleteURL(ctx, alias); err != nil {
+	if err = s.provider.DeleteURL(ctx, alias, requesterEmail, time.Now()); err != nil {
 		if errors.Is(err, storage.ErrURLNotFound) {
 			return fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
 		}
 		return fmt.Errorf("%s: failed to delete url: %w", op, err)
 	}
 
+	s.record(ctx, audit.ActionDelete, alias, asAdmin, &link, nil)
+
 	return nil
 }
@@ -0,0 +1,185 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/storage"
+)
+
+// CreateFolder creates a personal folder for the user, or a folder of the
+// workspace with the given ID, which only its editors and admins may create.
+func (s *Service) CreateFolder(ctx context.Context, name string, workspaceID int64, userEmail string, userID int64) (domain.Folder, error) {
+	const op = "url.Service.CreateFolder"
+
+	name = strings.TrimSpace(name)
+	if err := domain.ValidateFolderName(name); err != nil {
+		return domain.Folder{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, asAdmin, err := s.scope(ctx, workspaceID, workspace.RoleEditor, userEmail, userID)
+	if err != nil {
+		return domain.Folder{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	folder, err := s.provider.CreateFolder(ctx, domain.Folder{
+		Name:        name,
+		OwnerEmail:  userEmail,
+		OwnerUID:    userID,
+		WorkspaceID: workspaceID,
+		CreatedAt:   time.Now(),
+	})
+	if err != nil {
+		return domain.Folder{}, fmt.Errorf("%s: failed to create folder: %w", op, err)
+	}
+
+	s.recordFolder(ctx, audit.ActionCreate, folder.ID, asAdmin, nil, &folder)
+
+	return folder, nil
+}
+
+// Folders returns the personal folders of the user, or the folders of the
+// workspace with the given ID, which only its members and admins may list.
+func (s *Service) Folders(ctx context.Context, workspaceID int64, userEmail string, userID int64) ([]domain.Folder, error) {
+	const op = "url.Service.Folders"
+
+	owner, _, err := s.scope(ctx, workspaceID, workspace.RoleViewer, userEmail, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	folders, err := s.provider.Folders(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list folders: %w", op, err)
+	}
+
+	return folders, nil
+}
+
+// RenameFolder renames the folder. Only the owner, workspace editors and
+// admins may rename a folder.
+func (s *Service) RenameFolder(ctx context.Context, id int64, name, requesterEmail string, requesterID int64) (domain.Folder, error) {
+	const op = "url.Service.RenameFolder"
+
+	name = strings.TrimSpace(name)
+	if err := domain.ValidateFolderName(name); err != nil {
+		return domain.Folder{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	folder, asAdmin, err := s.manageFolder(ctx, id, requesterEmail, requesterID)
+	if err != nil {
+		return domain.Folder{}, fmt.Errorf("%s: %w", op, err)
+	}
+	before := folder
+
+	if err = s.provider.RenameFolder(ctx, id, name); err != nil {
+		if errors.Is(err, storage.ErrFolderNotFound) {
+			return domain.Folder{}, fmt.Errorf("%s: %w", op, domain.ErrFolderNotFound)
+		}
+		return domain.Folder{}, fmt.Errorf("%s: failed to rename folder: %w", op, err)
+	}
+
+	folder.Name = name
+	s.recordFolder(ctx, audit.ActionUpdate, id, asAdmin, &before, &folder)
+
+	return folder, nil
+}
+
+// DeleteFolder deletes the folder. Its links are kept and taken out of the
+// folder. Only the owner, workspace editors and admins may delete a folder.
+func (s *Service) DeleteFolder(ctx context.Context, id int64, requesterEmail string, requesterID int64) error {
+	const op = "url.Service.DeleteFolder"
+
+	folder, asAdmin, err := s.manageFolder(ctx, id, requesterEmail, requesterID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = s.provider.DeleteFolder(ctx, id); err != nil {
+		if errors.Is(err, storage.ErrFolderNotFound) {
+			return fmt.Errorf("%s: %w", op, domain.ErrFolderNotFound)
+		}
+		return fmt.Errorf("%s: failed to delete folder: %w", op, err)
+	}
+
+	s.recordFolder(ctx, audit.ActionDelete, id, asAdmin, &folder, nil)
+
+	return nil
+}
+
+// TagStats returns the number of live links and their clicks per tag of the
+// user's personal links, or of the links of the workspace with the given ID,
+// which only its members and admins may see.
+func (s *Service) TagStats(ctx context.Context, workspaceID int64, userEmail string, userID int64) ([]domain.TagStats, error) {
+	const op = "url.Service.TagStats"
+
+	owner, _, err := s.scope(ctx, workspaceID, workspace.RoleViewer, userEmail, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stats, err := s.provider.TagStats(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get tag stats: %w", op, err)
+	}
+
+	return stats, nil
+}
+
+// manageFolder returns the folder with the given ID if the requester may
+// change it, and reports whether access was granted through admin rights.
+func (s *Service) manageFolder(ctx context.Context, id int64, requesterEmail string, requesterID int64) (domain.Folder, bool, error) {
+	folder, err := s.folder(ctx, id)
+	if err != nil {
+		return domain.Folder{}, false, err
+	}
+
+	asAdmin, err := s.authorize(ctx, folder.Owner(), workspace.RoleEditor, requesterEmail, requesterID)
+	if err != nil {
+		return domain.Folder{}, false, err
+	}
+
+	if asAdmin {
+		s.log.Info("admin managing folder", slog.Int64("folder_id", id), slog.String("owner", folder.OwnerEmail))
+	}
+
+	return folder, asAdmin, nil
+}
+
+// checkFolder makes sure a link of owner may be put into the folder with the
+// given ID: the folder has to belong to the same owner. Folders of someone
+// else are reported as not found.
+func (s *Service) checkFolder(ctx context.Context, id int64, owner domain.Owner) error {
+	if id == 0 {
+		return nil
+	}
+
+	folder, err := s.folder(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !folder.Owner().SameAs(owner) {
+		return domain.ErrFolderNotFound
+	}
+
+	return nil
+}
+
+// folder returns the folder with the given ID.
+func (s *Service) folder(ctx context.Context, id int64) (domain.Folder, error) {
+	folder, err := s.provider.Folder(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrFolderNotFound) {
+			return domain.Folder{}, domain.ErrFolderNotFound
+		}
+		return domain.Folder{}, fmt.Errorf("failed to get folder: %w", err)
+	}
+	return folder, nil
+}
@@ -0,0 +1,55 @@
+package url_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFoldersAreAudited(t *testing.T) {
+	const admin = int64(99)
+
+	s, st := newServiceWith(t, config.URLConfig{}, adminUID(admin))
+	ctx := context.Background()
+
+	folder, err := s.CreateFolder(ctx, "Campaigns", 0, ownerEmail, ownerUID)
+	require.NoError(t, err)
+	_, err = s.RenameFolder(ctx, folder.ID, "Launch", "root@example.com", admin)
+	require.NoError(t, err)
+	require.NoError(t, s.DeleteFolder(ctx, folder.ID, ownerEmail, ownerUID))
+
+	entries, err := st.AuditLog(ctx, audit.Filter{Subject: audit.SubjectFolder, SubjectID: folder.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	deleted, renamed, created := entries[0], entries[1], entries[2]
+
+	require.Equal(t, audit.ActionCreate, created.Action)
+	require.False(t, created.AsAdmin)
+	require.Nil(t, created.Before)
+	require.Equal(t, "Campaigns", folderName(t, created.After))
+
+	require.Equal(t, audit.ActionUpdate, renamed.Action)
+	require.True(t, renamed.AsAdmin)
+	require.Equal(t, "Campaigns", folderName(t, renamed.Before))
+	require.Equal(t, "Launch", folderName(t, renamed.After))
+
+	require.Equal(t, audit.ActionDelete, deleted.Action)
+	require.False(t, deleted.AsAdmin)
+	require.Equal(t, "Launch", folderName(t, deleted.Before))
+	require.Nil(t, deleted.After)
+}
+
+func folderName(t *testing.T, snapshot json.RawMessage) string {
+	t.Helper()
+
+	var folder domain.Folder
+	require.NoError(t, json.Unmarshal(snapshot, &folder))
+	return folder.Name
+}
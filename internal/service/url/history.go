@@ -0,0 +1,98 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/storage"
+)
+
+// History returns the destination changes of the link, newest first. Only the
+// owner, workspace members and admins may see the history of a link.
+func (s *Service) History(ctx context.Context, alias, requesterEmail string, requesterID int64) ([]domain.DestinationChange, error) {
+	const op = "url.Service.History"
+
+	owner, err := s.provider.UrlOwner(ctx, alias)
+	if err != nil {
+		if errors.Is(err, storage.ErrURLNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
+		}
+		return nil, fmt.Errorf("%s: failed to get url owner: %w", op, err)
+	}
+
+	if _, err = s.authorize(ctx, owner, workspace.RoleViewer, requesterEmail, requesterID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	history, err := s.provider.URLHistory(ctx, alias)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get url history: %w", op, err)
+	}
+
+	return history, nil
+}
+
+// Revert points the link back to the destination it had before the change
+// with the given ID and returns the updated link. The revert is recorded as a
+// new change and the old destination has to pass the same checks as a new
+// one. Only the owner, workspace editors and admins may revert a link.
+func (s *Service) Revert(ctx context.Context, alias string, changeID int64, requesterEmail string, requesterID int64) (domain.Link, error) {
+	const op = "url.Service.Revert"
+
+	before, err := s.provider.Link(ctx, alias)
+	if err != nil {
+		if errors.Is(err, storage.ErrURLNotFound) {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
+		}
+		return domain.Link{}, fmt.Errorf("%s: failed to get link: %w", op, err)
+	}
+
+	asAdmin, err := s.authorize(ctx, before.Owner(), workspace.RoleEditor, requesterEmail, requesterID)
+	if err != nil {
+		return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	change, err := s.provider.DestinationChange(ctx, alias, changeID)
+	if err != nil {
+		if errors.Is(err, storage.ErrChangeNotFound) {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, domain.ErrChangeNotFound)
+		}
+		return domain.Link{}, fmt.Errorf("%s: failed to get destination change: %w", op, err)
+	}
+
+	if err = s.checkDestination(change.OldURL); err != nil {
+		return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	normalizedURL, err := s.canonicalizer.Canonicalize(change.OldURL)
+	if err != nil {
+		return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = s.provider.SetURL(ctx, alias, change.OldURL, normalizedURL, requesterEmail, time.Now()); err != nil {
+		if errors.Is(err, storage.ErrURLNotFound) {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
+		}
+		return domain.Link{}, fmt.Errorf("%s: failed to set url: %w", op, err)
+	}
+
+	s.log.Info("destination reverted",
+		slog.String("alias", alias),
+		slog.Int64("change_id", changeID),
+		slog.Bool("as_admin", asAdmin),
+	)
+
+	link, err := s.provider.Link(ctx, alias)
+	if err != nil {
+		return domain.Link{}, fmt.Errorf("%s: failed to get updated link: %w", op, err)
+	}
+
+	s.record(ctx, audit.ActionRevert, alias, asAdmin, &before, &link)
+
+	return link, nil
+}
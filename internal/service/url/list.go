@@ -0,0 +1,54 @@
+package url
+
+import (
+	"context"
+	"fmt"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+)
+
+// List returns the personal links of the given user that pass the filter,
+// newest first. With a workspace ID in the filter it returns the links of that
+// workspace instead, which only its members and admins may list.
+func (s *Service) List(ctx context.Context, ownerEmail string, ownerID int64, filter domain.ListFilter) ([]domain.Link, error) {
+	const op = "url.Service.List"
+
+	tags, err := domain.NormalizeTags(filter.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	filter.Tags = tags
+
+	owner, _, err := s.scope(ctx, filter.WorkspaceID, workspace.RoleViewer, ownerEmail, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	links, err := s.provider.ListURLs(ctx, owner, filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list urls: %w", op, err)
+	}
+
+	return links, nil
+}
+
+// scope returns the owner whose links the user works with: the user, or the
+// workspace with the given ID when the user's role there allows need. It
+// reports whether access was granted through admin rights.
+func (s *Service) scope(ctx context.Context, workspaceID int64, need workspace.Role, userEmail string, userID int64) (domain.Owner, bool, error) {
+	if workspaceID == 0 {
+		return domain.Owner{UID: userID, Email: userEmail}, false, nil
+	}
+
+	if _, err := s.workspace(ctx, workspaceID); err != nil {
+		return domain.Owner{}, false, err
+	}
+
+	owner := domain.Owner{WorkspaceID: workspaceID}
+	asAdmin, err := s.authorize(ctx, owner, need, userEmail, userID)
+	if err != nil {
+		return domain.Owner{}, false, err
+	}
+
+	return owner, asAdmin, nil
+}
@@ -0,0 +1,106 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/lib/metrics"
+	"url-shortener/internal/storage"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Unlock checks the password of a protected link and grants the visitor
+// access for the configured time. Wrong passwords are throttled per link and
+// client address; every attempt counts until one succeeds. Visitors without a
+// valid client address are not throttled, as they would all share one lock.
+func (s *Service) Unlock(ctx context.Context, alias, password string, clientIP netip.Addr) (domain.AccessGrant, error) {
+	const op = "url.Service.Unlock"
+
+	var key string
+	if clientIP.IsValid() {
+		key = alias + "|" + clientIP.String()
+		if retryAfter, ok := s.unlockAttempts.Attempt(key); !ok {
+			metrics.UnlockAttemptsTotal.WithLabelValues("throttled").Inc()
+			return domain.AccessGrant{}, fmt.Errorf("%s: %w", op, &domain.ThrottledError{RetryAfter: retryAfter})
+		}
+	} else {
+		s.log.Warn("password attempt without a client address is not throttled", slog.String("alias", alias))
+	}
+
+	link, err := s.provider.Link(ctx, alias)
+	if err != nil {
+		if errors.Is(err, storage.ErrURLNotFound) {
+			return domain.AccessGrant{}, fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
+		}
+		return domain.AccessGrant{}, fmt.Errorf("%s: failed to get link: %w", op, err)
+	}
+
+	if link.Protected {
+		err = bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			metrics.UnlockAttemptsTotal.WithLabelValues("wrong_password").Inc()
+			return domain.AccessGrant{}, fmt.Errorf("%s: %w", op, domain.ErrWrongPassword)
+		}
+		if err != nil {
+			return domain.AccessGrant{}, fmt.Errorf("%s: failed to check password: %w", op, err)
+		}
+	}
+
+	if key != "" {
+		s.unlockAttempts.Reset(key)
+	}
+	metrics.UnlockAttemptsTotal.WithLabelValues("success").Inc()
+
+	expiresAt := time.Now().Add(s.cfg.Password.UnlockTTL).Truncate(time.Second)
+	return domain.AccessGrant{
+		Token:     s.accessToken(link, expiresAt),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// hashPassword returns the bcrypt hash of a link password, or an empty string
+// for no password.
+func hashPassword(password string) (string, error) {
+	if password == "" {
+		return "", nil
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// accessToken returns "<expiry unix time>.<signature>". The signature covers
+// the password hash, so changing the password revokes earlier grants.
+func (s *Service) accessToken(link domain.Link, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	return expires + "." + s.unlockSigner.Sign(accessMessage(link, expires))
+}
+
+// validAccessToken reports whether token grants access to link now.
+func (s *Service) validAccessToken(link domain.Link, token string) bool {
+	expires, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() >= unix {
+		return false
+	}
+
+	return s.unlockSigner.Verify(accessMessage(link, expires), signature)
+}
+
+func accessMessage(link domain.Link, expires string) string {
+	return strings.Join([]string{"unlock", link.Alias, expires, link.PasswordHash}, "\x00")
+}
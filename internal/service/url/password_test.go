@@ -0,0 +1,46 @@
+package url_test
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+	domain "url-shortener/internal/domain/url"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnlockThrottle(t *testing.T) {
+	s := newService(t, config.URLConfig{Password: config.PasswordConfig{
+		UnlockTTL:     time.Hour,
+		MaxAttempts:   2,
+		AttemptWindow: time.Minute,
+	}})
+	ctx := context.Background()
+
+	alias, err := s.Shorten(ctx, "https://example.com/docs", "", ownerEmail, ownerUID, domain.ShortenOptions{Password: "correct horse"})
+	require.NoError(t, err)
+
+	client := netip.MustParseAddr("192.0.2.1")
+	for range 2 {
+		_, err = s.Unlock(ctx, alias, "wrong", client)
+		require.ErrorIs(t, err, domain.ErrWrongPassword)
+	}
+	var throttled *domain.ThrottledError
+	_, err = s.Unlock(ctx, alias, "correct horse", client)
+	require.ErrorAs(t, err, &throttled)
+
+	// Other clients are not affected.
+	_, err = s.Unlock(ctx, alias, "correct horse", netip.MustParseAddr("192.0.2.2"))
+	require.NoError(t, err)
+
+	// Visitors without a client address do not lock each other out.
+	for range 3 {
+		_, err = s.Unlock(ctx, alias, "wrong", netip.Addr{})
+		require.ErrorIs(t, err, domain.ErrWrongPassword)
+	}
+	_, err = s.Unlock(ctx, alias, "correct horse", netip.Addr{})
+	require.NoError(t, err)
+}
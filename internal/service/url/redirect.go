@@ -4,24 +4,183 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"time"
 	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/lib/metrics"
+	"url-shortener/internal/lib/useragent"
 	"url-shortener/internal/storage"
 )
 
-func (s *Service) RedirectURL(ctx context.Context, alias string) (string, error) {
+// RedirectURL resolves the alias to the destination and the status code to
+// redirect with. The first targeting rule matching the visitor's User-Agent
+// and country replaces the main destination; other visitors of a link with
+// split variants get a variant picked by weight, or the one they got before
+// for sticky variants. The UTM template of the link is added to the chosen
+// destination, then the extra path and query of the visit are forwarded for
+// links with passthrough enabled. Links marked "warn" and visits chosen for a
+// destination on a configured warn domain are flagged for the interstitial
+// page.
+//
+// Links outside their activation window fail with ErrLinkNotYetActive or
+// ErrLinkExpired, signed only links without a valid signed URL with
+// ErrSignatureRequired or ErrSignatureExpired. Password protected links need
+// a valid access token from Unlock. Followed visits use up a click of links
+// with a click limit; once it is reached the link is gone.
+func (s *Service) RedirectURL(ctx context.Context, alias string, visit domain.Visit) (domain.Redirect, error) {
 	const op = "url.Service.GetRedirectURL"
 
-	resURL, err := s.provider.Url(ctx, alias)
+	link, err := s.provider.Link(ctx, alias)
 	if err != nil {
 		if errors.Is(err, storage.ErrURLNotFound) {
-			return "", fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
+			return domain.Redirect{}, fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
 		}
-		return "", fmt.Errorf("%s: failed to get url: %w", op, err)
+		return domain.Redirect{}, fmt.Errorf("%s: failed to get url: %w", op, err)
 	}
 
-	if err = domain.ValidateURL(resURL); err != nil {
-		return "", fmt.Errorf("%s: stored url is invalid: %w", op, err)
+	if err = link.CheckActive(time.Now()); err != nil {
+		return domain.Redirect{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return resURL, nil
+	if err = s.checkSignature(link, &visit); err != nil {
+		return domain.Redirect{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if link.Protected && !s.validAccessToken(link, visit.AccessToken) {
+		return domain.Redirect{}, fmt.Errorf("%s: %w", op, domain.ErrPasswordRequired)
+	}
+
+	if link.Exhausted() {
+		return domain.Redirect{}, fmt.Errorf("%s: %w", op, domain.ErrLinkExhausted)
+	}
+
+	destination := link.URL
+	var variant string
+	if target, ok := domain.SelectTarget(link.Targeting, s.audience(link, visit)); ok {
+		destination = target
+	} else if len(link.Variants) > 0 {
+		v, ok := chooseVariant(link, visit)
+		if !ok {
+			return domain.Redirect{}, fmt.Errorf("%s: stored variants have no weight", op)
+		}
+		destination, variant = v.URL, v.Name
+	}
+
+	if err = domain.ValidateURL(destination); err != nil {
+		return domain.Redirect{}, fmt.Errorf("%s: stored url is invalid: %w", op, err)
+	}
+
+	// Warn domains are matched against the destination the visitor is sent
+	// to, which may be a targeting rule URL instead of the main one.
+	warn := link.Warn || s.warnDomains.MatchURL(destination)
+
+	if link.UTM != nil {
+		destination, err = domain.ApplyUTM(destination, *link.UTM)
+		if err != nil {
+			return domain.Redirect{}, fmt.Errorf("%s: failed to apply utm template: %w", op, err)
+		}
+	}
+
+	destination, err = domain.ApplyPassthrough(destination, link.Passthrough, visit)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPassthroughRequest) && link.Passthrough == domain.PassthroughOff {
+			// Extra path segments only exist for passthrough links.
+			return domain.Redirect{}, fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
+		}
+		return domain.Redirect{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Links stored before their destination was blocklisted must stop working.
+	// The forwarded parts may also complete a blocklisted URL prefix.
+	if entry, blocked := s.blocklist.Blocked(destination); blocked {
+		s.log.Warn("refused to redirect to blocklisted url", slog.String("alias", alias), slog.String("entry", entry))
+		metrics.BlockedURLsTotal.WithLabelValues("redirect").Inc()
+		return domain.Redirect{}, fmt.Errorf("%s: %w: matched %q", op, domain.ErrURLBlocked, entry)
+	}
+
+	statusCode := link.RedirectType
+	if domain.ValidateRedirectType(statusCode) != nil {
+		statusCode = domain.DefaultRedirectType
+	}
+
+	// The link may have been used up by concurrent visits since it was read.
+	if visit.Follow && link.MaxClicks > 0 {
+		if err = s.provider.ClaimClick(ctx, alias); err != nil {
+			if errors.Is(err, storage.ErrURLExhausted) {
+				return domain.Redirect{}, fmt.Errorf("%s: %w", op, domain.ErrLinkExhausted)
+			}
+			if errors.Is(err, storage.ErrURLNotFound) {
+				return domain.Redirect{}, fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
+			}
+			return domain.Redirect{}, fmt.Errorf("%s: failed to claim click: %w", op, err)
+		}
+	}
+
+	return domain.Redirect{
+		URL:        destination,
+		StatusCode: statusCode,
+		Title:      link.Title,
+		CreatedAt:  link.CreatedAt,
+		Warn:       warn,
+		UTMVersion: link.UTMVersion,
+		Varies: len(link.Targeting) > 0 || len(link.Variants) > 0 || link.Protected || link.MaxClicks > 0 || link.NotAfter != nil ||
+			link.SignedOnly,
+		Variant: variant,
+		// Only remember a variant the visitor does not already have.
+		StickyVariant: link.StickyVariants && variant != "" && variant != visit.Variant,
+	}, nil
+}
+
+// chooseVariant returns the variant the visitor got before while the link has
+// sticky variants and that variant is still active, and picks one by weight
+// otherwise.
+func chooseVariant(link domain.Link, visit domain.Visit) (domain.Variant, bool) {
+	if link.StickyVariants && visit.Variant != "" {
+		if v, ok := domain.FindVariant(link.Variants, visit.Variant); ok {
+			return v, true
+		}
+	}
+
+	total := domain.TotalWeight(link.Variants)
+	if total <= 0 {
+		return domain.Variant{}, false
+	}
+
+	return domain.PickVariant(link.Variants, rand.IntN(total))
+}
+
+// audience describes the visitor for the targeting rules of link. The country
+// is only looked up when a rule needs it; lookup failures leave it unknown.
+func (s *Service) audience(link domain.Link, visit domain.Visit) domain.Audience {
+	client := useragent.Parse(visit.UserAgent)
+	a := domain.Audience{
+		Platform: client.OS,
+		Device:   client.Device,
+	}
+
+	if s.geo != nil && visit.ClientIP.IsValid() && domain.HasCountryRules(link.Targeting) {
+		country, err := s.geo.Country(visit.ClientIP)
+		if err != nil {
+			s.log.Warn("failed to look up visitor country", slog.String("alias", link.Alias), slog.String("error", err.Error()))
+		}
+		a.Country = country
+	}
+
+	return a
+}
+
+// RecordClick stores a followed redirect of a link.
+func (s *Service) RecordClick(ctx context.Context, click domain.Click) error {
+	const op = "url.Service.RecordClick"
+
+	if click.ClickedAt.IsZero() {
+		click.ClickedAt = time.Now()
+	}
+
+	if err := s.provider.RecordClick(ctx, click); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
 }
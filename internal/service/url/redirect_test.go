@@ -0,0 +1,73 @@
+package url_test
+
+import (
+	"context"
+	"testing"
+
+	"url-shortener/internal/config"
+	domain "url-shortener/internal/domain/url"
+
+	"github.com/stretchr/testify/require"
+)
+
+const iPhoneUA = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1"
+
+func TestRedirectURLWarnsForChosenDestination(t *testing.T) {
+	cases := []struct {
+		name      string
+		url       string
+		opts      domain.ShortenOptions
+		userAgent string
+		wantURL   string
+		wantWarn  bool
+	}{
+		{
+			name:     "Main destination on a warn domain",
+			url:      "https://risky.example/page",
+			wantURL:  "https://risky.example/page",
+			wantWarn: true,
+		},
+		{
+			name:    "Main destination elsewhere",
+			url:     "https://safe.example/page",
+			wantURL: "https://safe.example/page",
+		},
+		{
+			name:      "Targeting rule sends to a warn domain",
+			url:       "https://safe.example/page",
+			opts:      domain.ShortenOptions{Targeting: []domain.TargetingRule{{Platform: "ios", URL: "https://risky.example/app"}}},
+			userAgent: iPhoneUA,
+			wantURL:   "https://risky.example/app",
+			wantWarn:  true,
+		},
+		{
+			name:      "Targeting rule sends away from a warn domain",
+			url:       "https://risky.example/page",
+			opts:      domain.ShortenOptions{Targeting: []domain.TargetingRule{{Platform: "ios", URL: "https://safe.example/app"}}},
+			userAgent: iPhoneUA,
+			wantURL:   "https://safe.example/app",
+		},
+		{
+			name:     "Link marked warn",
+			url:      "https://safe.example/page",
+			opts:     domain.ShortenOptions{Warn: true},
+			wantURL:  "https://safe.example/page",
+			wantWarn: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newService(t, config.URLConfig{WarnDomains: []string{"risky.example"}})
+			ctx := context.Background()
+
+			alias, err := s.Shorten(ctx, tc.url, "", ownerEmail, ownerUID, tc.opts)
+			require.NoError(t, err)
+
+			redirect, err := s.RedirectURL(ctx, alias, domain.Visit{UserAgent: tc.userAgent})
+			require.NoError(t, err)
+			require.Equal(t, tc.wantURL, redirect.URL)
+			require.Equal(t, tc.wantWarn, redirect.Warn)
+		})
+	}
+}
@@ -4,39 +4,245 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"time"
+	"url-shortener/internal/domain/audit"
 	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
 	"url-shortener/internal/lib/api/random"
+	"url-shortener/internal/lib/metrics"
 	"url-shortener/internal/storage"
 )
 
 // AliasLength defines the length of the generated alias.
 const AliasLength = 6
 
-// Shorten shortens the given original URL with the provided alias and user email.
-// If the alias is empty, a random alias of length AliasLength is generated.
+// Shorten shortens the given original URL with the provided alias for the user.
+// If the alias is empty, a random alias of length AliasLength is generated, or,
+// with deduplication enabled and no per-link settings, the user's existing
+// alias for the same canonical destination is returned. A link created in a
+// workspace belongs to the workspace, and only its editors and admins may
+// create one there. A folder given in the options has to belong to the owner
+// of the new link.
 // It returns the alias or an error if the operation fails.
-func (s *Service) Shorten(ctx context.Context, originalURL, alias, userEmail string) (string, error) {
+func (s *Service) Shorten(ctx context.Context, originalURL, alias, userEmail string, userID int64, opts domain.ShortenOptions) (string, error) {
 	const op = "url.Service.Shorten"
 
-	if err := domain.ValidateURL(originalURL); err != nil {
+	if err := s.checkDestination(originalURL); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if alias != "" {
+		if err := domain.ValidateAlias(alias); err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := domain.ValidateTitle(opts.Title); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := domain.ValidateNotes(opts.Notes); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := domain.ValidatePassthrough(opts.Passthrough); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.validateTargeting(opts.Targeting); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.validateVariants(opts.Variants); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := domain.ValidatePassword(opts.Password); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := domain.ValidateMaxClicks(opts.MaxClicks); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := domain.ValidateActivationWindow(opts.NotBefore, opts.NotAfter); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if opts.SignedOnly && s.signingKeys == nil {
+		return "", fmt.Errorf("%s: %w", op, domain.ErrSigningDisabled)
+	}
+
+	tags, err := domain.NormalizeTags(opts.Tags)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	owner := domain.Owner{UID: userID, Email: userEmail}
+	asAdmin := false
+	if opts.WorkspaceID != 0 {
+		if _, err := s.workspace(ctx, opts.WorkspaceID); err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+
+		owner = domain.Owner{WorkspaceID: opts.WorkspaceID}
+		asAdmin, err = s.authorize(ctx, owner, workspace.RoleEditor, userEmail, userID)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err = s.checkFolder(ctx, opts.FolderID, owner); err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		utm        *domain.UTMTemplate
+		utmVersion int
+	)
+	if opts.UTM != nil && !opts.UTM.IsZero() {
+		if err := domain.ValidateUTM(*opts.UTM); err != nil {
+			return "", fmt.Errorf("%s: %w", op, err)
+		}
+		utm, utmVersion = opts.UTM, 1
+	}
+
+	normalizedURL, err := s.canonicalizer.Canonicalize(originalURL)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	redirectType := opts.RedirectType
+	if redirectType == 0 {
+		redirectType = s.cfg.DefaultRedirectType
+	}
+	if err = domain.ValidateRedirectType(redirectType); err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
 	if alias == "" {
-		var err error
+		if s.dedupEnabled(opts) {
+			existing, err := s.provider.AliasByNormalizedURL(ctx, owner, normalizedURL, redirectType)
+			if err == nil {
+				return existing, nil
+			}
+			if !errors.Is(err, storage.ErrURLNotFound) {
+				return "", fmt.Errorf("%s: failed to look up existing alias: %w", op, err)
+			}
+		}
+
 		alias, err = random.NewRandomString(AliasLength)
 		if err != nil {
 			return "", fmt.Errorf("%s: failed to generate alias: %w", op, err)
 		}
 	}
 
-	err := s.provider.SaveURL(ctx, alias, originalURL, userEmail)
+	passwordHash, err := hashPassword(opts.Password)
 	if err != nil {
+		return "", fmt.Errorf("%s: failed to hash password: %w", op, err)
+	}
+
+	createdAt := time.Now()
+	link := domain.Link{
+		Alias:          alias,
+		URL:            originalURL,
+		NormalizedURL:  normalizedURL,
+		OwnerEmail:     userEmail,
+		OwnerUID:       userID,
+		WorkspaceID:    opts.WorkspaceID,
+		Tags:           tags,
+		FolderID:       opts.FolderID,
+		RedirectType:   redirectType,
+		Title:          opts.Title,
+		Notes:          opts.Notes,
+		CreatedAt:      &createdAt,
+		Warn:           opts.Warn,
+		Passthrough:    opts.Passthrough,
+		UTM:            utm,
+		UTMVersion:     utmVersion,
+		Targeting:      opts.Targeting,
+		Variants:       opts.Variants,
+		StickyVariants: opts.StickyVariants,
+		PasswordHash:   passwordHash,
+		Protected:      passwordHash != "",
+		MaxClicks:      opts.MaxClicks,
+		NotBefore:      opts.NotBefore,
+		NotAfter:       opts.NotAfter,
+		SignedOnly:     opts.SignedOnly,
+	}
+	if err = s.provider.SaveURL(ctx, link); err != nil {
 		if errors.Is(err, storage.ErrURLExists) {
 			return "", domain.ErrAliasExists
 		}
 		return "", fmt.Errorf("%s: failed to save url: %w", op, err)
 	}
 
+	metrics.URLsCreatedTotal.Inc()
+
+	s.record(ctx, audit.ActionCreate, alias, asAdmin, nil, &link)
+
 	return alias, nil
 }
+
+// dedupEnabled reports whether the request should reuse an existing alias,
+// falling back to the configured default when the request does not say. A
+// request customizing the link always gets a new one, as the existing link
+// may be set up differently.
+func (s *Service) dedupEnabled(opts domain.ShortenOptions) bool {
+	if opts.CustomizesLink() {
+		return false
+	}
+	if opts.Dedup != nil {
+		return *opts.Dedup
+	}
+	return s.cfg.Dedup
+}
+
+// checkDestination applies the destination policy and the blocklist to a URL
+// a link may redirect to.
+func (s *Service) checkDestination(rawURL string) error {
+	if err := s.policy.Validate(rawURL); err != nil {
+		return err
+	}
+
+	if entry, blocked := s.blocklist.Blocked(rawURL); blocked {
+		s.log.Warn("refused to shorten blocklisted url", slog.String("url", rawURL), slog.String("entry", entry))
+		metrics.BlockedURLsTotal.WithLabelValues("shorten").Inc()
+		return fmt.Errorf("%w: matched %q", domain.ErrURLBlocked, entry)
+	}
+
+	return nil
+}
+
+// validateTargeting checks the rule conditions and holds every rule URL to the
+// same checks as the main destination.
+func (s *Service) validateTargeting(rules []domain.TargetingRule) error {
+	if err := domain.ValidateTargeting(rules); err != nil {
+		return err
+	}
+
+	for _, rule := range rules {
+		if err := s.checkDestination(rule.URL); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateVariants checks the split variants and holds every variant URL to
+// the same checks as the main destination.
+func (s *Service) validateVariants(variants []domain.Variant) error {
+	if err := domain.ValidateVariants(variants); err != nil {
+		return err
+	}
+
+	for _, v := range variants {
+		if err := s.checkDestination(v.URL); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,183 @@
+package url_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"url-shortener/internal/config"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/lib/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShortenDedup(t *testing.T) {
+	const destination = "https://example.com/docs"
+
+	cases := []struct {
+		name    string
+		opts    domain.ShortenOptions
+		reuse   bool
+		protect bool
+	}{
+		{
+			name:  "Plain link is reused",
+			opts:  domain.ShortenOptions{Title: "Docs"},
+			reuse: true,
+		},
+		{
+			name:    "Password protected link is new",
+			opts:    domain.ShortenOptions{Password: "correct horse"},
+			protect: true,
+		},
+		{
+			name: "One-time link is new",
+			opts: domain.ShortenOptions{MaxClicks: 1},
+		},
+		{
+			name: "Link with a redirect type is new",
+			opts: domain.ShortenOptions{RedirectType: 301},
+		},
+		{
+			name: "Link with passthrough is new",
+			opts: domain.ShortenOptions{Passthrough: domain.PassthroughPreserve},
+		},
+		{
+			name: "Link marked warn is new",
+			opts: domain.ShortenOptions{Warn: true},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newService(t, config.URLConfig{Dedup: true})
+			ctx := context.Background()
+
+			existing, err := s.Shorten(ctx, destination, "", ownerEmail, ownerUID, domain.ShortenOptions{})
+			require.NoError(t, err)
+
+			alias, err := s.Shorten(ctx, destination, "", ownerEmail, ownerUID, tc.opts)
+			require.NoError(t, err)
+
+			if tc.reuse {
+				require.Equal(t, existing, alias)
+				return
+			}
+			require.NotEqual(t, existing, alias)
+
+			_, err = s.RedirectURL(ctx, alias, domain.Visit{})
+			if tc.protect {
+				require.ErrorIs(t, err, domain.ErrPasswordRequired)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestShortenDedupSkipsCustomizedLinks(t *testing.T) {
+	const destination = "https://example.com/docs"
+
+	notAfter := time.Now().Add(time.Hour)
+
+	cases := []struct {
+		name string
+		opts domain.ShortenOptions
+		// follow uses up the customized link before the plain one is made.
+		follow bool
+	}{
+		{name: "Password protected link", opts: domain.ShortenOptions{Password: "correct horse"}},
+		{name: "Link with a redirect type", opts: domain.ShortenOptions{RedirectType: 301}},
+		{name: "Link marked warn", opts: domain.ShortenOptions{Warn: true}},
+		{name: "Link with passthrough", opts: domain.ShortenOptions{Passthrough: domain.PassthroughPreserve}},
+		{name: "Link with a UTM template", opts: domain.ShortenOptions{UTM: &domain.UTMTemplate{Source: "newsletter"}}},
+		{name: "Link with targeting", opts: domain.ShortenOptions{Targeting: []domain.TargetingRule{{Platform: "ios", URL: "https://example.com/app"}}}},
+		{name: "Link with split variants", opts: domain.ShortenOptions{Variants: []domain.Variant{{Name: "a", URL: "https://example.com/a", Weight: 1}}}},
+		{name: "One-time link", opts: domain.ShortenOptions{MaxClicks: 1}, follow: true},
+		{name: "Link with an activation window", opts: domain.ShortenOptions{NotAfter: &notAfter}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newService(t, config.URLConfig{Dedup: true})
+			ctx := context.Background()
+
+			customized, err := s.Shorten(ctx, destination, "", ownerEmail, ownerUID, tc.opts)
+			require.NoError(t, err)
+			if tc.follow {
+				_, err = s.RedirectURL(ctx, customized, domain.Visit{Follow: true})
+				require.NoError(t, err)
+			}
+
+			alias, err := s.Shorten(ctx, destination, "", ownerEmail, ownerUID, domain.ShortenOptions{})
+			require.NoError(t, err)
+			require.NotEqual(t, customized, alias)
+
+			redirect, err := s.RedirectURL(ctx, alias, domain.Visit{})
+			require.NoError(t, err)
+			require.Equal(t, destination, redirect.URL)
+			require.False(t, redirect.Warn)
+
+			// The plain link is reused from now on.
+			again, err := s.Shorten(ctx, destination, "", ownerEmail, ownerUID, domain.ShortenOptions{})
+			require.NoError(t, err)
+			require.Equal(t, alias, again)
+		})
+	}
+}
+
+func TestShortenValidatesAliases(t *testing.T) {
+	cases := []struct {
+		name  string
+		alias string
+		opts  domain.ShortenOptions
+		valid bool
+	}{
+		{name: "Custom alias", alias: "urls", valid: true},
+		{name: "Passthrough link", alias: "docs", opts: domain.ShortenOptions{Passthrough: domain.PassthroughPreserve}, valid: true},
+		{name: "Route of the link API", alias: "url"},
+		{name: "Passthrough link on the link API route", alias: "url", opts: domain.ShortenOptions{Passthrough: domain.PassthroughPreserve}},
+		{name: "Trailing plus of the preview page", alias: "docs+"},
+		{name: "Extra path segment", alias: "docs/preview"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newService(t, config.URLConfig{})
+
+			alias, err := s.Shorten(context.Background(), "https://example.com/docs", tc.alias, ownerEmail, ownerUID, tc.opts)
+			if !tc.valid {
+				require.ErrorIs(t, err, domain.ErrInvalidAlias)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.alias, alias)
+		})
+	}
+}
+
+func TestShortenCountsCreatedLinks(t *testing.T) {
+	s := newService(t, config.URLConfig{Dedup: true})
+	ctx := context.Background()
+	created := counterValue(t, metrics.URLsCreatedTotal)
+
+	_, err := s.Shorten(ctx, "https://example.com/docs", "", ownerEmail, ownerUID, domain.ShortenOptions{})
+	require.NoError(t, err)
+	require.Equal(t, created+1, counterValue(t, metrics.URLsCreatedTotal))
+
+	// Reusing the existing alias creates nothing.
+	_, err = s.Shorten(ctx, "https://example.com/docs", "", ownerEmail, ownerUID, domain.ShortenOptions{})
+	require.NoError(t, err)
+	require.Equal(t, created+1, counterValue(t, metrics.URLsCreatedTotal))
+}
+
+func counterValue(t *testing.T, c prometheus.Counter) float64 {
+	t.Helper()
+
+	var m dto.Metric
+	require.NoError(t, c.Write(&m))
+	return m.GetCounter().GetValue()
+}
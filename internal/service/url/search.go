@@ -0,0 +1,39 @@
+package url
+
+import (
+	"context"
+	"fmt"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+)
+
+// Search looks for the words of the query in the alias, destination URL,
+// title, tags and notes of the user's personal links, or of the links of the
+// workspace given in the search, which only its members and admins may
+// search. It returns one page of live links, best match first.
+func (s *Service) Search(ctx context.Context, userEmail string, userID int64, search domain.Search) (domain.SearchPage, error) {
+	const op = "url.Service.Search"
+
+	if err := search.Validate(); err != nil {
+		return domain.SearchPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	owner, _, err := s.scope(ctx, search.WorkspaceID, workspace.RoleViewer, userEmail, userID)
+	if err != nil {
+		return domain.SearchPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// One more link than asked for tells whether there is a next page.
+	links, err := s.provider.SearchURLs(ctx, owner, search.Terms(), search.Limit+1, search.Offset)
+	if err != nil {
+		return domain.SearchPage{}, fmt.Errorf("%s: failed to search urls: %w", op, err)
+	}
+
+	page := domain.SearchPage{URLs: links}
+	if len(links) > search.Limit {
+		page.URLs = links[:search.Limit]
+		page.NextOffset = search.Offset + search.Limit
+	}
+
+	return page, nil
+}
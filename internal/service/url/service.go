@@ -2,32 +2,216 @@ package url
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"net/netip"
+	"time"
+	"url-shortener/internal/config"
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/lib/sign"
+	"url-shortener/internal/lib/throttle"
+	"url-shortener/internal/storage"
 )
 
 // Provider defines the interface for URL storage operations.
 type Provider interface {
-	SaveURL(ctx context.Context, alias, originalURL, ownerEmail string) error
-	UrlOwner(ctx context.Context, alias string) (string, error)
-	DeleteURL(ctx context.Context, alias string) error
-	Url(ctx context.Context, alias string) (string, error)
+	SaveURL(ctx context.Context, link domain.Link) error
+	UrlOwner(ctx context.Context, alias string) (domain.Owner, error)
+	BackfillOwnerUID(ctx context.Context, ownerEmail string, uid int64) (int, error)
+	DeleteURL(ctx context.Context, alias, deletedBy string, deletedAt time.Time) error
+	RestoreURL(ctx context.Context, alias string) error
+	PurgeURL(ctx context.Context, alias string, releasedAt time.Time) error
+	AliasByNormalizedURL(ctx context.Context, owner domain.Owner, normalizedURL string, redirectType int) (string, error)
+	Link(ctx context.Context, alias string) (domain.Link, error)
+	DeletedLink(ctx context.Context, alias string) (domain.Link, error)
+	ListURLs(ctx context.Context, owner domain.Owner, filter domain.ListFilter) ([]domain.Link, error)
+	SearchURLs(ctx context.Context, owner domain.Owner, terms []string, limit, offset int) ([]domain.Link, error)
+	ListDeletedURLs(ctx context.Context, owner domain.Owner) ([]domain.Link, error)
+	RecordClick(ctx context.Context, click domain.Click) error
+	ClicksByUTMVersion(ctx context.Context, alias string) ([]domain.UTMVersion, error)
+	ClicksByVariant(ctx context.Context, alias string) (map[string]int, error)
+	ClaimClick(ctx context.Context, alias string) error
+	SetURL(ctx context.Context, alias, rawURL, normalizedURL, changedBy string, changedAt time.Time) error
+	UpdateLink(ctx context.Context, alias string, upd domain.LinkUpdate, normalizedURL, changedBy string, changedAt time.Time) error
+	URLHistory(ctx context.Context, alias string) ([]domain.DestinationChange, error)
+	DestinationChange(ctx context.Context, alias string, id int64) (domain.DestinationChange, error)
+	SaveTransfer(ctx context.Context, transfer domain.Transfer) error
+	Transfer(ctx context.Context, alias string) (domain.Transfer, error)
+	IncomingTransfers(ctx context.Context, toEmail string, now time.Time) ([]domain.Transfer, error)
+	DeleteTransfer(ctx context.Context, alias string) error
+	TransferURL(ctx context.Context, alias, fromEmail, toEmail string, toUID int64) error
+	TransferAllURLs(ctx context.Context, fromEmail, toEmail string) ([]string, error)
+	AppendAudit(ctx context.Context, entry audit.Entry) error
+	AuditLog(ctx context.Context, filter audit.Filter) ([]audit.Entry, error)
+	Workspace(ctx context.Context, id int64) (workspace.Workspace, error)
+	WorkspaceMemberByUser(ctx context.Context, workspaceID int64, email string, uid int64) (workspace.Member, error)
+	BackfillMemberUID(ctx context.Context, email string, uid int64) (int, error)
+	CreateFolder(ctx context.Context, folder domain.Folder) (domain.Folder, error)
+	Folder(ctx context.Context, id int64) (domain.Folder, error)
+	Folders(ctx context.Context, owner domain.Owner) ([]domain.Folder, error)
+	RenameFolder(ctx context.Context, id int64, name string) error
+	DeleteFolder(ctx context.Context, id int64) error
+	TagStats(ctx context.Context, owner domain.Owner) ([]domain.TagStats, error)
 }
 
 type AdminChecker interface {
 	IsAdmin(ctx context.Context, userID int64) (bool, error)
 }
 
+// BlockChecker reports whether a destination is on the malicious URL
+// blocklist, returning the matching entry.
+type BlockChecker interface {
+	Blocked(rawURL string) (string, bool)
+}
+
+// CountryLocator returns the ISO 3166-1 alpha-2 code of the country an IP
+// address is located in, or an empty string when it is unknown.
+type CountryLocator interface {
+	Country(ip netip.Addr) (string, error)
+}
+
 type Service struct {
-	log          *slog.Logger
-	provider     Provider
-	adminChecker AdminChecker
+	log           *slog.Logger
+	provider      Provider
+	adminChecker  AdminChecker
+	blocklist     BlockChecker
+	geo           CountryLocator
+	cfg           config.URLConfig
+	canonicalizer *domain.Canonicalizer
+	policy        *domain.Policy
+	warnDomains   domain.DomainList
+	// unlockSigner signs the access tokens of protected links.
+	unlockSigner   *sign.Signer
+	unlockAttempts *throttle.Limiter
+	// signingKeys sign and verify signed URLs; nil disables them.
+	signingKeys *sign.Keyring
 }
 
-// New creates a new URL shortening service.
-func New(log *slog.Logger, provider Provider, adminChecker AdminChecker) *Service {
+// New creates a new URL shortening service. geo may be nil, in which case
+// country targeting rules never match; signingKeys may be nil, which disables
+// signed URLs. Without a configured cookie secret, access tokens of protected
+// links are signed with a random key.
+func New(log *slog.Logger, provider Provider, adminChecker AdminChecker, blocklist BlockChecker, geo CountryLocator, signingKeys *sign.Keyring, cfg config.URLConfig) *Service {
+	unlockSigner := sign.NewRandom()
+	if cfg.Password.CookieSecret != "" {
+		unlockSigner = sign.New([]byte(cfg.Password.CookieSecret))
+	}
+
 	return &Service{
 		log:          log,
 		provider:     provider,
 		adminChecker: adminChecker,
+		blocklist:    blocklist,
+		geo:          geo,
+		cfg:          cfg,
+		canonicalizer: domain.NewCanonicalizer(domain.CanonicalizeOptions{
+			StripDefaultPort: cfg.Canonicalize.StripDefaultPort,
+			StripFragment:    cfg.Canonicalize.StripFragment,
+			TrackingParams:   cfg.Canonicalize.TrackingParams,
+		}),
+		policy: domain.NewPolicy(domain.PolicyOptions{
+			ShortDomains:    cfg.Policy.ShortDomains,
+			BlockPrivateIPs: !cfg.Policy.AllowPrivateIPs,
+			DeniedDomains:   cfg.Policy.DeniedDomains,
+			AllowedDomains:  cfg.Policy.AllowedDomains,
+		}),
+		warnDomains:    domain.NewDomainList(cfg.WarnDomains),
+		unlockSigner:   unlockSigner,
+		unlockAttempts: throttle.New(cfg.Password.MaxAttempts, cfg.Password.AttemptWindow),
+		signingKeys:    signingKeys,
+	}
+}
+
+// authorize allows the owner of a link and admins to manage it; a workspace
+// link may instead be managed by the members of the workspace whose role
+// allows need. It reports whether access was granted through admin rights.
+// An owner matched by email because the link has no recorded owner ID claims
+// all such links of the email with their ID, and a member matched by email
+// claims all such memberships.
+func (s *Service) authorize(ctx context.Context, owner domain.Owner, need workspace.Role, requesterEmail string, requesterID int64) (bool, error) {
+	if owner.OwnedBy(requesterEmail, requesterID) {
+		if owner.UID == 0 && requesterID != 0 {
+			s.claimLinks(ctx, requesterEmail, requesterID)
+		}
+		return false, nil
+	}
+
+	if owner.WorkspaceID != 0 {
+		member, err := s.provider.WorkspaceMemberByUser(ctx, owner.WorkspaceID, requesterEmail, requesterID)
+		if err != nil && !errors.Is(err, storage.ErrMemberNotFound) {
+			return false, fmt.Errorf("failed to get workspace member: %w", err)
+		}
+		if err == nil && member.UID == 0 && requesterID != 0 {
+			s.claimMemberships(ctx, requesterEmail, requesterID)
+		}
+		if err == nil && member.Role.Allows(need) {
+			return false, nil
+		}
+	}
+
+	isAdmin, err := s.adminChecker.IsAdmin(ctx, requesterID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check admin status: %w", err)
+	}
+
+	if !isAdmin {
+		return false, domain.ErrPermissionDenied
+	}
+
+	return true, nil
+}
+
+// workspace returns the workspace with the given ID.
+func (s *Service) workspace(ctx context.Context, id int64) (workspace.Workspace, error) {
+	ws, err := s.provider.Workspace(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrWorkspaceNotFound) {
+			return workspace.Workspace{}, workspace.ErrWorkspaceNotFound
+		}
+		return workspace.Workspace{}, fmt.Errorf("failed to get workspace: %w", err)
+	}
+	return ws, nil
+}
+
+// requireAdmin allows only admins to go on.
+func (s *Service) requireAdmin(ctx context.Context, requesterID int64) error {
+	isAdmin, err := s.adminChecker.IsAdmin(ctx, requesterID)
+	if err != nil {
+		return fmt.Errorf("failed to check admin status: %w", err)
+	}
+
+	if !isAdmin {
+		return domain.ErrPermissionDenied
+	}
+
+	return nil
+}
+
+// claimLinks records uid as the owner ID of the links of email that have
+// none. Authorization does not depend on it, so a failure is only logged.
+func (s *Service) claimLinks(ctx context.Context, email string, uid int64) {
+	claimed, err := s.provider.BackfillOwnerUID(ctx, email, uid)
+	if err != nil {
+		s.log.Error("failed to backfill owner uid", slog.String("owner", email), slog.String("error", err.Error()))
+		return
+	}
+	if claimed > 0 {
+		s.log.Info("owner uid backfilled", slog.String("owner", email), slog.Int64("uid", uid), slog.Int("links", claimed))
+	}
+}
+
+// claimMemberships records uid as the user ID of the workspace memberships of
+// email that have none. Like claimLinks, a failure is only logged.
+func (s *Service) claimMemberships(ctx context.Context, email string, uid int64) {
+	claimed, err := s.provider.BackfillMemberUID(ctx, email, uid)
+	if err != nil {
+		s.log.Error("failed to backfill member uid", slog.String("member", email), slog.String("error", err.Error()))
+		return
+	}
+	if claimed > 0 {
+		s.log.Info("member uid backfilled", slog.String("member", email), slog.Int64("uid", uid), slog.Int("memberships", claimed))
 	}
 }
@@ -0,0 +1,74 @@
+package url_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"url-shortener/internal/config"
+	"url-shortener/internal/service/url"
+	"url-shortener/internal/storage/sqlite"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	ownerEmail = "owner@example.com"
+	ownerUID   = int64(1)
+)
+
+// noAdmins makes nobody an admin.
+type noAdmins struct{}
+
+func (noAdmins) IsAdmin(context.Context, int64) (bool, error) { return false, nil }
+
+// adminUID makes the user with the given ID an admin.
+type adminUID int64
+
+func (a adminUID) IsAdmin(_ context.Context, userID int64) (bool, error) {
+	return userID == int64(a), nil
+}
+
+// emptyBlocklist blocks nothing.
+type emptyBlocklist struct{}
+
+func (emptyBlocklist) Blocked(string) (string, bool) { return "", false }
+
+// newService creates a Service on a migrated SQLite database.
+func newService(t *testing.T, cfg config.URLConfig) *url.Service {
+	t.Helper()
+
+	s, _ := newServiceWith(t, cfg, noAdmins{})
+	return s
+}
+
+// newServiceWith creates a Service with the given admin checker on a migrated
+// SQLite database and returns the storage for checking what it wrote.
+func newServiceWith(t *testing.T, cfg config.URLConfig, adminChecker url.AdminChecker) (*url.Service, *sqlite.Storage) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "storage.db")
+
+	m, err := migrate.New("file://../../../migrations", "sqlite3://"+path)
+	require.NoError(t, err)
+	require.NoError(t, m.Up())
+	srcErr, dbErr := m.Close()
+	require.NoError(t, srcErr)
+	require.NoError(t, dbErr)
+
+	st, err := sqlite.New(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = st.Close() })
+
+	if cfg.DefaultRedirectType == 0 {
+		cfg.DefaultRedirectType = 302
+	}
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return url.New(log, st, adminChecker, emptyBlocklist{}, nil, nil, cfg), st
+}
@@ -0,0 +1,106 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/storage"
+)
+
+// SignURL mints a signed URL of the link that works until it expires, also
+// for links flagged signed only. Only the owner, workspace editors and admins
+// may sign a link.
+func (s *Service) SignURL(ctx context.Context, alias, requesterEmail string, requesterID int64, opts domain.SignOptions) (domain.SignedURL, error) {
+	const op = "url.Service.SignURL"
+
+	if s.signingKeys == nil {
+		return domain.SignedURL{}, fmt.Errorf("%s: %w", op, domain.ErrSigningDisabled)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.cfg.SignedURLs.DefaultTTL)
+	if opts.ExpiresAt != nil {
+		expiresAt = *opts.ExpiresAt
+	}
+	expiresAt = expiresAt.Truncate(time.Second)
+	if !expiresAt.After(now) || expiresAt.After(now.Add(s.cfg.SignedURLs.MaxTTL)) {
+		return domain.SignedURL{}, fmt.Errorf("%s: %w", op, domain.ErrInvalidSignatureExpiry)
+	}
+
+	if err := validateSignedPath(opts.ExtraPath, opts.RawQuery); err != nil {
+		return domain.SignedURL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	owner, err := s.provider.UrlOwner(ctx, alias)
+	if err != nil {
+		if errors.Is(err, storage.ErrURLNotFound) {
+			return domain.SignedURL{}, fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
+		}
+		return domain.SignedURL{}, fmt.Errorf("%s: failed to get url owner: %w", op, err)
+	}
+
+	if _, err = s.authorize(ctx, owner, workspace.RoleEditor, requesterEmail, requesterID); err != nil {
+		return domain.SignedURL{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	keyID, signature := s.signingKeys.Sign(domain.SignatureMessage(alias, opts.ExtraPath, opts.RawQuery, expiresAt))
+
+	path := "/" + url.PathEscape(alias)
+	if opts.ExtraPath != "" {
+		path += "/" + opts.ExtraPath
+	}
+	query := domain.AppendSignature(opts.RawQuery, domain.URLSignature{KeyID: keyID, Signature: signature, ExpiresAt: expiresAt})
+
+	return domain.SignedURL{
+		URL:       strings.TrimSuffix(s.cfg.BaseURL, "/") + path + "?" + query,
+		KeyID:     keyID,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// validateSignedPath checks that the extra path and query to sign are in the
+// escaped and encoded form the redirect handler passes in a Visit.
+func validateSignedPath(extraPath, rawQuery string) error {
+	if strings.HasPrefix(extraPath, "/") {
+		return domain.ErrInvalidSignedPath
+	}
+	if _, err := url.PathUnescape(extraPath); err != nil {
+		return domain.ErrInvalidSignedPath
+	}
+	if _, err := url.ParseQuery(rawQuery); err != nil {
+		return domain.ErrInvalidSignedPath
+	}
+	if _, _, signed := domain.SplitSignature(rawQuery); signed {
+		return domain.ErrInvalidSignedPath
+	}
+	return nil
+}
+
+// checkSignature verifies the signature parameters of the visit and, when
+// they are valid, removes them from its query so they are not forwarded. Links
+// flagged signed only fail without a valid, unexpired signature.
+func (s *Service) checkSignature(link domain.Link, visit *domain.Visit) error {
+	sig, rest, ok := domain.SplitSignature(visit.RawQuery)
+	valid := ok && s.signingKeys != nil &&
+		s.signingKeys.Verify(sig.KeyID, domain.SignatureMessage(link.Alias, visit.ExtraPath, rest, sig.ExpiresAt), sig.Signature)
+	if valid {
+		visit.RawQuery = rest
+	}
+
+	if !link.SignedOnly {
+		return nil
+	}
+	if !valid {
+		return domain.ErrSignatureRequired
+	}
+	if !time.Now().Before(sig.ExpiresAt) {
+		return domain.ErrSignatureExpired
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/storage"
+)
+
+// Stats returns the link with its metadata, including the last destination
+// health check, and its clicks broken down by UTM template version and split
+// variant. Only the owner, workspace members and admins may see it.
+func (s *Service) Stats(ctx context.Context, alias, requesterEmail string, requesterID int64) (domain.LinkStats, error) {
+	const op = "url.Service.Stats"
+
+	link, err := s.provider.Link(ctx, alias)
+	if err != nil {
+		if errors.Is(err, storage.ErrURLNotFound) {
+			return domain.LinkStats{}, fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
+		}
+		return domain.LinkStats{}, fmt.Errorf("%s: failed to get link: %w", op, err)
+	}
+
+	if _, err = s.authorize(ctx, link.Owner(), workspace.RoleViewer, requesterEmail, requesterID); err != nil {
+		return domain.LinkStats{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	versions, err := s.provider.ClicksByUTMVersion(ctx, alias)
+	if err != nil {
+		return domain.LinkStats{}, fmt.Errorf("%s: failed to count clicks: %w", op, err)
+	}
+
+	variantClicks, err := s.provider.ClicksByVariant(ctx, alias)
+	if err != nil {
+		return domain.LinkStats{}, fmt.Errorf("%s: failed to count variant clicks: %w", op, err)
+	}
+
+	stats := domain.LinkStats{
+		Link:          link,
+		UTMVersions:   versions,
+		VariantClicks: variantStats(link.Variants, variantClicks),
+	}
+	for _, v := range versions {
+		stats.Clicks += v.Clicks
+	}
+
+	return stats, nil
+}
+
+// variantStats lists the current variants in order, then removed variants
+// that still have clicks, by name.
+func variantStats(variants []domain.Variant, clicks map[string]int) []domain.VariantStats {
+	var stats []domain.VariantStats
+	current := make(map[string]struct{}, len(variants))
+	for _, v := range variants {
+		current[v.Name] = struct{}{}
+		stats = append(stats, domain.VariantStats{Variant: v, Clicks: clicks[v.Name]})
+	}
+
+	var removed []string
+	for name := range clicks {
+		if _, ok := current[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	slices.Sort(removed)
+	for _, name := range removed {
+		stats = append(stats, domain.VariantStats{Variant: domain.Variant{Name: name}, Clicks: clicks[name]})
+	}
+
+	return stats
+}
@@ -0,0 +1,258 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/storage"
+)
+
+// TransferOwnership hands the link over to toEmail. With acceptance required
+// the transfer waits for the recipient to accept it and is returned as
+// pending; otherwise the link changes owner right away. Only the owner and
+// admins may transfer a link; workspace links cannot be transferred.
+func (s *Service) TransferOwnership(ctx context.Context, alias, toEmail, requesterEmail string, requesterID int64, opts domain.TransferOptions) (domain.Transfer, error) {
+	const op = "url.Service.TransferOwnership"
+
+	link, err := s.provider.Link(ctx, alias)
+	if err != nil {
+		if errors.Is(err, storage.ErrURLNotFound) {
+			return domain.Transfer{}, fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
+		}
+		return domain.Transfer{}, fmt.Errorf("%s: failed to get link: %w", op, err)
+	}
+
+	asAdmin, err := s.authorize(ctx, link.Owner(), workspace.RoleAdmin, requesterEmail, requesterID)
+	if err != nil {
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if link.WorkspaceID != 0 {
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, domain.ErrWorkspaceLink)
+	}
+
+	if err = domain.ValidateRecipient(link.OwnerEmail, toEmail); err != nil {
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+	transfer := domain.Transfer{
+		Alias:       alias,
+		FromEmail:   link.OwnerEmail,
+		ToEmail:     toEmail,
+		RequestedBy: requesterEmail,
+		RequestedAt: now,
+	}
+
+	if s.requireAcceptance(opts) {
+		transfer.ExpiresAt = now.Add(s.cfg.Transfer.RequestTTL)
+		transfer.Pending = true
+
+		if err = s.provider.SaveTransfer(ctx, transfer); err != nil {
+			return domain.Transfer{}, fmt.Errorf("%s: failed to save transfer: %w", op, err)
+		}
+
+		s.log.Info("url transfer requested",
+			slog.String("alias", alias),
+			slog.String("to", toEmail),
+			slog.Bool("as_admin", asAdmin),
+		)
+		s.record(ctx, audit.ActionTransferRequest, alias, asAdmin, &link, nil)
+
+		return transfer, nil
+	}
+
+	// The recipient's user ID is not known until they manage the link.
+	after, err := s.transfer(ctx, alias, link.OwnerEmail, toEmail, 0)
+	if err != nil {
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Info("url transferred", slog.String("alias", alias), slog.String("to", toEmail), slog.Bool("as_admin", asAdmin))
+	s.record(ctx, audit.ActionTransfer, alias, asAdmin, &link, &after)
+
+	return transfer, nil
+}
+
+// AcceptTransfer completes the pending transfer of the link to the requester
+// and returns the link. Transfers to other users, expired transfers and
+// transfers of links that changed owner meanwhile are not found.
+func (s *Service) AcceptTransfer(ctx context.Context, alias, requesterEmail string, requesterID int64) (domain.Link, error) {
+	const op = "url.Service.AcceptTransfer"
+
+	transfer, err := s.provider.Transfer(ctx, alias)
+	if err != nil {
+		if errors.Is(err, storage.ErrTransferNotFound) {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, domain.ErrTransferNotFound)
+		}
+		return domain.Link{}, fmt.Errorf("%s: failed to get transfer: %w", op, err)
+	}
+
+	if transfer.ToEmail != requesterEmail || !time.Now().Before(transfer.ExpiresAt) {
+		return domain.Link{}, fmt.Errorf("%s: %w", op, domain.ErrTransferNotFound)
+	}
+
+	before, err := s.provider.Link(ctx, alias)
+	if err != nil {
+		if errors.Is(err, storage.ErrURLNotFound) {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, domain.ErrTransferNotFound)
+		}
+		return domain.Link{}, fmt.Errorf("%s: failed to get link: %w", op, err)
+	}
+
+	link, err := s.transfer(ctx, alias, transfer.FromEmail, requesterEmail, requesterID)
+	if err != nil {
+		if errors.Is(err, domain.ErrURLNotFound) {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, domain.ErrTransferNotFound)
+		}
+		return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.log.Info("url transfer accepted", slog.String("alias", alias), slog.String("from", transfer.FromEmail))
+	s.record(ctx, audit.ActionTransfer, alias, false, &before, &link)
+
+	return link, nil
+}
+
+// CancelTransfer drops the pending transfer of the link. The recipient may
+// decline it; the owner and admins may withdraw it.
+func (s *Service) CancelTransfer(ctx context.Context, alias, requesterEmail string, requesterID int64) error {
+	const op = "url.Service.CancelTransfer"
+
+	transfer, err := s.provider.Transfer(ctx, alias)
+	if err != nil {
+		if errors.Is(err, storage.ErrTransferNotFound) {
+			return fmt.Errorf("%s: %w", op, domain.ErrTransferNotFound)
+		}
+		return fmt.Errorf("%s: failed to get transfer: %w", op, err)
+	}
+
+	if transfer.ToEmail != requesterEmail {
+		owner, err := s.provider.UrlOwner(ctx, alias)
+		if err != nil && !errors.Is(err, storage.ErrURLNotFound) {
+			return fmt.Errorf("%s: failed to get url owner: %w", op, err)
+		}
+		// A transfer outlives a link that was deleted or changed owner
+		// meanwhile; it then belongs to the user who requested it.
+		if err != nil || owner.Email != transfer.FromEmail {
+			owner = domain.Owner{Email: transfer.FromEmail}
+		}
+
+		if _, err = s.authorize(ctx, owner, workspace.RoleAdmin, requesterEmail, requesterID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err = s.provider.DeleteTransfer(ctx, alias); err != nil {
+		if errors.Is(err, storage.ErrTransferNotFound) {
+			return fmt.Errorf("%s: %w", op, domain.ErrTransferNotFound)
+		}
+		return fmt.Errorf("%s: failed to delete transfer: %w", op, err)
+	}
+
+	s.log.Info("url transfer cancelled", slog.String("alias", alias), slog.String("requester", requesterEmail))
+
+	return nil
+}
+
+// IncomingTransfers returns the pending transfers to the requester, oldest first.
+func (s *Service) IncomingTransfers(ctx context.Context, requesterEmail string) ([]domain.Transfer, error) {
+	const op = "url.Service.IncomingTransfers"
+
+	transfers, err := s.provider.IncomingTransfers(ctx, requesterEmail, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list transfers: %w", op, err)
+	}
+
+	return transfers, nil
+}
+
+// TransferAll hands every personal link of fromEmail, including the ones in
+// the trash, over to toEmail without waiting for acceptance, e.g. when
+// fromEmail leaves the team. It returns the aliases of the moved links. Only
+// admins may transfer links in bulk.
+func (s *Service) TransferAll(ctx context.Context, fromEmail, toEmail string, requesterID int64) ([]string, error) {
+	const op = "url.Service.TransferAll"
+
+	if err := s.requireAdmin(ctx, requesterID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if fromEmail == "" {
+		return nil, fmt.Errorf("%s: %w", op, domain.ErrInvalidRecipient)
+	}
+	if err := domain.ValidateRecipient(fromEmail, toEmail); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	live, err := s.provider.ListURLs(ctx, domain.Owner{Email: fromEmail}, domain.ListFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list urls: %w", op, err)
+	}
+	deleted, err := s.provider.ListDeletedURLs(ctx, domain.Owner{Email: fromEmail})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list deleted urls: %w", op, err)
+	}
+
+	aliases, err := s.provider.TransferAllURLs(ctx, fromEmail, toEmail)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to transfer urls: %w", op, err)
+	}
+
+	s.log.Info("urls transferred in bulk",
+		slog.String("from", fromEmail),
+		slog.String("to", toEmail),
+		slog.Int("count", len(aliases)),
+	)
+
+	snapshots := make(map[string]domain.Link, len(live)+len(deleted))
+	for _, link := range append(live, deleted...) {
+		snapshots[link.Alias] = link
+	}
+	for _, alias := range aliases {
+		before, ok := snapshots[alias]
+		if !ok {
+			s.record(ctx, audit.ActionTransfer, alias, true, nil, nil)
+			continue
+		}
+		after := before
+		after.OwnerEmail = toEmail
+		after.OwnerUID = 0
+		s.record(ctx, audit.ActionTransfer, alias, true, &before, &after)
+	}
+
+	return aliases, nil
+}
+
+// transfer moves the link from fromEmail to toEmail, whose user ID may be
+// unknown, and returns the link with its new owner.
+func (s *Service) transfer(ctx context.Context, alias, fromEmail, toEmail string, toUID int64) (domain.Link, error) {
+	if err := s.provider.TransferURL(ctx, alias, fromEmail, toEmail, toUID); err != nil {
+		if errors.Is(err, storage.ErrURLNotFound) {
+			return domain.Link{}, domain.ErrURLNotFound
+		}
+		return domain.Link{}, fmt.Errorf("failed to transfer url: %w", err)
+	}
+
+	link, err := s.provider.Link(ctx, alias)
+	if err != nil {
+		return domain.Link{}, fmt.Errorf("failed to get transferred link: %w", err)
+	}
+
+	return link, nil
+}
+
+// requireAcceptance reports whether the transfer should wait for the
+// recipient, falling back to the configured default when the request does
+// not say.
+func (s *Service) requireAcceptance(opts domain.TransferOptions) bool {
+	if opts.RequireAcceptance != nil {
+		return *opts.RequireAcceptance
+	}
+	return s.cfg.Transfer.RequireAcceptance
+}
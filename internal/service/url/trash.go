@@ -0,0 +1,101 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/storage"
+)
+
+// Trash returns the deleted links of the requester, most recently deleted first.
+func (s *Service) Trash(ctx context.Context, requesterEmail string, requesterID int64) ([]domain.Link, error) {
+	const op = "url.Service.Trash"
+
+	links, err := s.provider.ListDeletedURLs(ctx, domain.Owner{UID: requesterID, Email: requesterEmail})
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list deleted urls: %w", op, err)
+	}
+
+	return links, nil
+}
+
+// Restore takes the link out of the trash and returns it. Only the owner,
+// workspace editors and admins may restore a link.
+func (s *Service) Restore(ctx context.Context, alias, requesterEmail string, requesterID int64) (domain.Link, error) {
+	const op = "url.Service.Restore"
+
+	deleted, asAdmin, err := s.deletedLink(ctx, alias, requesterEmail, requesterID)
+	if err != nil {
+		return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = s.provider.RestoreURL(ctx, alias); err != nil {
+		if errors.Is(err, storage.ErrURLNotFound) {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
+		}
+		return domain.Link{}, fmt.Errorf("%s: failed to restore url: %w", op, err)
+	}
+
+	s.log.Info("url restored", slog.String("alias", alias), slog.String("deleted_by", deleted.DeletedBy))
+
+	link, err := s.provider.Link(ctx, alias)
+	if err != nil {
+		return domain.Link{}, fmt.Errorf("%s: failed to get restored link: %w", op, err)
+	}
+
+	s.record(ctx, audit.ActionRestore, alias, asAdmin, &deleted, &link)
+
+	return link, nil
+}
+
+// Purge permanently removes the link from the trash. Its alias stays reserved
+// until the quarantine that started with the deletion ends. Only the owner,
+// workspace editors and admins may purge a link.
+func (s *Service) Purge(ctx context.Context, alias, requesterEmail string, requesterID int64) error {
+	const op = "url.Service.Purge"
+
+	deleted, asAdmin, err := s.deletedLink(ctx, alias, requesterEmail, requesterID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = s.provider.PurgeURL(ctx, alias, deleted.DeletedAt.Add(s.cfg.Trash.Quarantine)); err != nil {
+		if errors.Is(err, storage.ErrURLNotFound) {
+			return fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
+		}
+		return fmt.Errorf("%s: failed to purge url: %w", op, err)
+	}
+
+	s.log.Info("url purged", slog.String("alias", alias), slog.String("requester", requesterEmail))
+
+	s.record(ctx, audit.ActionPurge, alias, asAdmin, &deleted, nil)
+
+	return nil
+}
+
+// deletedLink returns the link from the trash if the requester may manage it.
+// It reports whether access was granted through admin rights.
+func (s *Service) deletedLink(ctx context.Context, alias, requesterEmail string, requesterID int64) (domain.Link, bool, error) {
+	link, err := s.provider.DeletedLink(ctx, alias)
+	if err != nil {
+		if errors.Is(err, storage.ErrURLNotFound) {
+			return domain.Link{}, false, domain.ErrURLNotFound
+		}
+		return domain.Link{}, false, fmt.Errorf("failed to get deleted link: %w", err)
+	}
+
+	asAdmin, err := s.authorize(ctx, link.Owner(), workspace.RoleEditor, requesterEmail, requesterID)
+	if err != nil {
+		return domain.Link{}, false, err
+	}
+
+	if asAdmin {
+		s.log.Info("admin managing deleted url", slog.String("alias", alias), slog.String("owner", link.OwnerEmail))
+	}
+
+	return link, asAdmin, nil
+}
@@ -0,0 +1,121 @@
+package url
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/storage"
+)
+
+// Update applies the changes to the link at once and returns the updated link;
+// when one of them fails, the link is left unchanged. Only the owner,
+// workspace editors and admins may update a link.
+func (s *Service) Update(ctx context.Context, alias, requesterEmail string, requesterID int64, upd domain.LinkUpdate) (domain.Link, error) {
+	const op = "url.Service.Update"
+
+	if upd.IsEmpty() {
+		return domain.Link{}, fmt.Errorf("%s: %w", op, domain.ErrEmptyUpdate)
+	}
+
+	var normalizedURL string
+	if upd.URL != nil {
+		if err := s.checkDestination(*upd.URL); err != nil {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		var err error
+		normalizedURL, err = s.canonicalizer.Canonicalize(*upd.URL)
+		if err != nil {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if upd.UTM != nil {
+		if err := domain.ValidateUTM(*upd.UTM); err != nil {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if upd.Targeting != nil {
+		if err := s.validateTargeting(*upd.Targeting); err != nil {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if upd.Variants != nil {
+		if err := s.validateVariants(*upd.Variants); err != nil {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if upd.Notes != nil {
+		if err := domain.ValidateNotes(*upd.Notes); err != nil {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if upd.SignedOnly != nil && *upd.SignedOnly && s.signingKeys == nil {
+		return domain.Link{}, fmt.Errorf("%s: %w", op, domain.ErrSigningDisabled)
+	}
+
+	var tags []string
+	if upd.Tags != nil {
+		var err error
+		tags, err = domain.NormalizeTags(*upd.Tags)
+		if err != nil {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	before, err := s.provider.Link(ctx, alias)
+	if err != nil {
+		if errors.Is(err, storage.ErrURLNotFound) {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
+		}
+		return domain.Link{}, fmt.Errorf("%s: failed to get link: %w", op, err)
+	}
+
+	asAdmin, err := s.authorize(ctx, before.Owner(), workspace.RoleEditor, requesterEmail, requesterID)
+	if err != nil {
+		return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if asAdmin {
+		s.log.Info("admin updating url", slog.String("alias", alias), slog.String("owner", before.OwnerEmail))
+	}
+
+	if upd.FolderID != nil {
+		if err = s.checkFolder(ctx, *upd.FolderID, before.Owner()); err != nil {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if upd.Tags != nil {
+		upd.Tags = &tags
+	}
+
+	if err = s.provider.UpdateLink(ctx, alias, upd, normalizedURL, requesterEmail, time.Now()); err != nil {
+		if errors.Is(err, storage.ErrURLNotFound) {
+			return domain.Link{}, fmt.Errorf("%s: %w", op, domain.ErrURLNotFound)
+		}
+		return domain.Link{}, fmt.Errorf("%s: failed to update link: %w", op, err)
+	}
+
+	link, err := s.provider.Link(ctx, alias)
+	if err != nil {
+		return domain.Link{}, fmt.Errorf("%s: failed to get updated link: %w", op, err)
+	}
+
+	if upd.UTM != nil {
+		s.log.Info("utm template changed", slog.String("alias", alias), slog.Int("version", link.UTMVersion))
+	}
+
+	s.record(ctx, audit.ActionUpdate, alias, asAdmin, &before, &link)
+
+	return link, nil
+}
@@ -0,0 +1,286 @@
+package workspace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/storage"
+)
+
+// Provider defines the storage operations the workspace service needs.
+type Provider interface {
+	CreateWorkspace(ctx context.Context, ws workspace.Workspace, creatorUID int64) (workspace.Workspace, error)
+	Workspace(ctx context.Context, id int64) (workspace.Workspace, error)
+	Workspaces(ctx context.Context, memberEmail string, memberUID int64) ([]workspace.Workspace, error)
+	WorkspaceMember(ctx context.Context, workspaceID int64, email string) (workspace.Member, error)
+	WorkspaceMemberByUser(ctx context.Context, workspaceID int64, email string, uid int64) (workspace.Member, error)
+	BackfillMemberUID(ctx context.Context, email string, uid int64) (int, error)
+	WorkspaceMembers(ctx context.Context, workspaceID int64) ([]workspace.Member, error)
+	SaveWorkspaceMember(ctx context.Context, member workspace.Member) error
+	DeleteWorkspaceMember(ctx context.Context, workspaceID int64, email string) error
+	AppendAudit(ctx context.Context, entry audit.Entry) error
+}
+
+type AdminChecker interface {
+	IsAdmin(ctx context.Context, userID int64) (bool, error)
+}
+
+// Service manages workspaces and their members.
+type Service struct {
+	log          *slog.Logger
+	provider     Provider
+	adminChecker AdminChecker
+}
+
+// New creates a new workspace service.
+func New(log *slog.Logger, provider Provider, adminChecker AdminChecker) *Service {
+	return &Service{
+		log:          log,
+		provider:     provider,
+		adminChecker: adminChecker,
+	}
+}
+
+// Create creates a workspace with the requester as its first admin.
+func (s *Service) Create(ctx context.Context, name, requesterEmail string, requesterID int64) (workspace.Workspace, error) {
+	const op = "workspace.Service.Create"
+
+	name = strings.TrimSpace(name)
+	if err := workspace.ValidateName(name); err != nil {
+		return workspace.Workspace{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ws, err := s.provider.CreateWorkspace(ctx, workspace.Workspace{
+		Name:      name,
+		CreatedBy: requesterEmail,
+		CreatedAt: time.Now(),
+	}, requesterID)
+	if err != nil {
+		return workspace.Workspace{}, fmt.Errorf("%s: failed to create workspace: %w", op, err)
+	}
+
+	s.log.Info("workspace created", slog.Int64("workspace_id", ws.ID), slog.String("created_by", requesterEmail))
+	s.record(ctx, audit.ActionCreate, ws.ID, false, nil, ws)
+
+	return ws, nil
+}
+
+// List returns the workspaces the requester is a member of, with their role
+// in each.
+func (s *Service) List(ctx context.Context, requesterEmail string, requesterID int64) ([]workspace.Workspace, error) {
+	const op = "workspace.Service.List"
+
+	workspaces, err := s.provider.Workspaces(ctx, requesterEmail, requesterID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list workspaces: %w", op, err)
+	}
+
+	return workspaces, nil
+}
+
+// Members returns the members of the workspace. Only its members and admins
+// may see them.
+func (s *Service) Members(ctx context.Context, workspaceID int64, requesterEmail string, requesterID int64) ([]workspace.Member, error) {
+	const op = "workspace.Service.Members"
+
+	if _, err := s.authorize(ctx, workspaceID, workspace.RoleViewer, requesterEmail, requesterID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	members, err := s.provider.WorkspaceMembers(ctx, workspaceID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list members: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// SetMember adds email to the workspace with the given role, or changes the
+// role of an existing member, and returns the membership. The last admin of a
+// workspace cannot be demoted. Only workspace admins and admins may manage
+// members.
+func (s *Service) SetMember(ctx context.Context, workspaceID int64, email string, role workspace.Role, requesterEmail string, requesterID int64) (workspace.Member, error) {
+	const op = "workspace.Service.SetMember"
+
+	if err := workspace.ValidateMember(email); err != nil {
+		return workspace.Member{}, fmt.Errorf("%s: %w", op, err)
+	}
+	if !role.Valid() {
+		return workspace.Member{}, fmt.Errorf("%s: %w", op, workspace.ErrInvalidRole)
+	}
+
+	asAdmin, err := s.authorize(ctx, workspaceID, workspace.RoleAdmin, requesterEmail, requesterID)
+	if err != nil {
+		return workspace.Member{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var before any
+	member, err := s.provider.WorkspaceMember(ctx, workspaceID, email)
+	switch {
+	case err == nil:
+		before = member
+		member.Role = role
+	case errors.Is(err, storage.ErrMemberNotFound):
+		member = workspace.Member{
+			WorkspaceID: workspaceID,
+			Email:       email,
+			Role:        role,
+			AddedBy:     requesterEmail,
+			AddedAt:     time.Now(),
+		}
+	default:
+		return workspace.Member{}, fmt.Errorf("%s: failed to get member: %w", op, err)
+	}
+
+	if err = s.provider.SaveWorkspaceMember(ctx, member); err != nil {
+		if errors.Is(err, storage.ErrWorkspaceNotFound) {
+			return workspace.Member{}, fmt.Errorf("%s: %w", op, workspace.ErrWorkspaceNotFound)
+		}
+		if errors.Is(err, storage.ErrLastAdmin) {
+			return workspace.Member{}, fmt.Errorf("%s: %w", op, workspace.ErrLastAdmin)
+		}
+		return workspace.Member{}, fmt.Errorf("%s: failed to save member: %w", op, err)
+	}
+
+	s.log.Info("workspace member saved",
+		slog.Int64("workspace_id", workspaceID),
+		slog.String("member", email),
+		slog.String("role", string(role)),
+		slog.String("requester", requesterEmail),
+	)
+	s.record(ctx, audit.ActionSetMember, workspaceID, asAdmin, before, member)
+
+	return member, nil
+}
+
+// RemoveMember removes email from the workspace. Members may leave on their
+// own; otherwise only workspace admins and admins may remove members. The
+// last admin of a workspace cannot be removed.
+func (s *Service) RemoveMember(ctx context.Context, workspaceID int64, email, requesterEmail string, requesterID int64) error {
+	const op = "workspace.Service.RemoveMember"
+
+	member, err := s.provider.WorkspaceMember(ctx, workspaceID, email)
+	if err != nil && !errors.Is(err, storage.ErrMemberNotFound) {
+		return fmt.Errorf("%s: failed to get member: %w", op, err)
+	}
+	found := err == nil
+
+	// Members leaving on their own are matched as in authorization, so that
+	// an email that changed hands cannot remove its former holder.
+	need := workspace.RoleAdmin
+	if found && member.Is(requesterEmail, requesterID) {
+		need = workspace.RoleViewer
+	}
+	asAdmin, err := s.authorize(ctx, workspaceID, need, requesterEmail, requesterID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !found {
+		return fmt.Errorf("%s: %w", op, workspace.ErrMemberNotFound)
+	}
+
+	if err = s.provider.DeleteWorkspaceMember(ctx, workspaceID, email); err != nil {
+		if errors.Is(err, storage.ErrMemberNotFound) {
+			return fmt.Errorf("%s: %w", op, workspace.ErrMemberNotFound)
+		}
+		if errors.Is(err, storage.ErrLastAdmin) {
+			return fmt.Errorf("%s: %w", op, workspace.ErrLastAdmin)
+		}
+		return fmt.Errorf("%s: failed to delete member: %w", op, err)
+	}
+
+	s.log.Info("workspace member removed",
+		slog.Int64("workspace_id", workspaceID),
+		slog.String("member", email),
+		slog.String("requester", requesterEmail),
+	)
+	s.record(ctx, audit.ActionRemoveMember, workspaceID, asAdmin, member, nil)
+
+	return nil
+}
+
+// authorize allows members of the workspace whose role allows need and admins
+// to go on, and reports whether access was granted through admin rights. It
+// fails with workspace.ErrWorkspaceNotFound for a workspace that does not
+// exist. A member matched by email because the membership has no recorded
+// user ID claims all such memberships of the email with their ID.
+func (s *Service) authorize(ctx context.Context, workspaceID int64, need workspace.Role, requesterEmail string, requesterID int64) (bool, error) {
+	if _, err := s.provider.Workspace(ctx, workspaceID); err != nil {
+		if errors.Is(err, storage.ErrWorkspaceNotFound) {
+			return false, workspace.ErrWorkspaceNotFound
+		}
+		return false, fmt.Errorf("failed to get workspace: %w", err)
+	}
+
+	member, err := s.provider.WorkspaceMemberByUser(ctx, workspaceID, requesterEmail, requesterID)
+	if err != nil && !errors.Is(err, storage.ErrMemberNotFound) {
+		return false, fmt.Errorf("failed to get workspace member: %w", err)
+	}
+	if err == nil && member.UID == 0 && requesterID != 0 {
+		s.claimMemberships(ctx, requesterEmail, requesterID)
+	}
+	if err == nil && member.Role.Allows(need) {
+		return false, nil
+	}
+
+	isAdmin, err := s.adminChecker.IsAdmin(ctx, requesterID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check admin status: %w", err)
+	}
+
+	if !isAdmin {
+		return false, domain.ErrPermissionDenied
+	}
+
+	return true, nil
+}
+
+// claimMemberships records uid as the user ID of the memberships of email
+// that have none. Authorization does not depend on it, so a failure is only
+// logged.
+func (s *Service) claimMemberships(ctx context.Context, email string, uid int64) {
+	claimed, err := s.provider.BackfillMemberUID(ctx, email, uid)
+	if err != nil {
+		s.log.Error("failed to backfill member uid", slog.String("member", email), slog.String("error", err.Error()))
+		return
+	}
+	if claimed > 0 {
+		s.log.Info("member uid backfilled", slog.String("member", email), slog.Int64("uid", uid), slog.Int("memberships", claimed))
+	}
+}
+
+// record appends an action on the workspace to the audit log with snapshots
+// of what it changed before and after it; either may be nil. The operation
+// has already happened, so a failure is logged rather than returned.
+func (s *Service) record(ctx context.Context, action string, workspaceID int64, asAdmin bool, before, after any) {
+	entry := audit.NewSubjectEntry(ctx, action, audit.SubjectWorkspace, workspaceID)
+	entry.AsAdmin = asAdmin
+
+	var err error
+	if before != nil {
+		if entry.Before, err = json.Marshal(before); err != nil {
+			s.log.Error("failed to encode audit snapshot", slog.Int64("workspace_id", workspaceID), slog.String("error", err.Error()))
+		}
+	}
+	if after != nil {
+		if entry.After, err = json.Marshal(after); err != nil {
+			s.log.Error("failed to encode audit snapshot", slog.Int64("workspace_id", workspaceID), slog.String("error", err.Error()))
+		}
+	}
+
+	if err = s.provider.AppendAudit(ctx, entry); err != nil {
+		s.log.Error("failed to record audit entry",
+			slog.String("action", action),
+			slog.Int64("workspace_id", workspaceID),
+			slog.String("error", err.Error()),
+		)
+	}
+}
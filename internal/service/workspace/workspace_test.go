@@ -0,0 +1,146 @@
+package workspace_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	service "url-shortener/internal/service/workspace"
+	"url-shortener/internal/storage/sqlite"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	adminEmail = "admin@example.com"
+	adminUID   = int64(1)
+	siteAdmin  = int64(99)
+)
+
+// admins makes the user with the given ID an admin.
+type admins int64
+
+func (a admins) IsAdmin(_ context.Context, userID int64) (bool, error) {
+	return userID == int64(a), nil
+}
+
+// newService creates a Service on a migrated SQLite database and returns the
+// storage for checking the audit log.
+func newService(t *testing.T) (*service.Service, *sqlite.Storage) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "storage.db")
+
+	m, err := migrate.New("file://../../../migrations", "sqlite3://"+path)
+	require.NoError(t, err)
+	require.NoError(t, m.Up())
+	srcErr, dbErr := m.Close()
+	require.NoError(t, srcErr)
+	require.NoError(t, dbErr)
+
+	st, err := sqlite.New(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = st.Close() })
+
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return service.New(log, st, admins(siteAdmin)), st
+}
+
+func TestMembersAreAudited(t *testing.T) {
+	s, st := newService(t)
+	ctx := context.Background()
+
+	ws, err := s.Create(ctx, "Marketing", adminEmail, adminUID)
+	require.NoError(t, err)
+
+	_, err = s.SetMember(ctx, ws.ID, "viewer@example.com", workspace.RoleViewer, adminEmail, adminUID)
+	require.NoError(t, err)
+	_, err = s.SetMember(ctx, ws.ID, "viewer@example.com", workspace.RoleEditor, "root@example.com", siteAdmin)
+	require.NoError(t, err)
+	require.NoError(t, s.RemoveMember(ctx, ws.ID, "viewer@example.com", adminEmail, adminUID))
+
+	entries, err := st.AuditLog(ctx, audit.Filter{Subject: audit.SubjectWorkspace, SubjectID: ws.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+
+	remove, promote, add, create := entries[0], entries[1], entries[2], entries[3]
+
+	require.Equal(t, audit.ActionCreate, create.Action)
+	require.Nil(t, create.Before)
+	require.NotNil(t, create.After)
+
+	require.Equal(t, audit.ActionSetMember, add.Action)
+	require.False(t, add.AsAdmin)
+	require.Nil(t, add.Before)
+	requireRole(t, workspace.RoleViewer, add.After)
+
+	require.Equal(t, audit.ActionSetMember, promote.Action)
+	require.True(t, promote.AsAdmin)
+	requireRole(t, workspace.RoleViewer, promote.Before)
+	requireRole(t, workspace.RoleEditor, promote.After)
+
+	require.Equal(t, audit.ActionRemoveMember, remove.Action)
+	requireRole(t, workspace.RoleEditor, remove.Before)
+	require.Nil(t, remove.After)
+}
+
+func TestMembersMatchedByUserID(t *testing.T) {
+	s, _ := newService(t)
+	ctx := context.Background()
+
+	ws, err := s.Create(ctx, "Marketing", adminEmail, adminUID)
+	require.NoError(t, err)
+	_, err = s.SetMember(ctx, ws.ID, "viewer@example.com", workspace.RoleViewer, adminEmail, adminUID)
+	require.NoError(t, err)
+
+	// The invited viewer signs in and claims the membership with their ID.
+	_, err = s.Members(ctx, ws.ID, "viewer@example.com", 2)
+	require.NoError(t, err)
+
+	// Their ID still matches after the email changes, and the old email no
+	// longer grants access to whoever holds it next.
+	_, err = s.Members(ctx, ws.ID, "renamed@example.com", 2)
+	require.NoError(t, err)
+	_, err = s.Members(ctx, ws.ID, "viewer@example.com", 3)
+	require.ErrorIs(t, err, domain.ErrPermissionDenied)
+	require.ErrorIs(t, s.RemoveMember(ctx, ws.ID, "viewer@example.com", "viewer@example.com", 3), domain.ErrPermissionDenied)
+
+	workspaces, err := s.List(ctx, "renamed@example.com", 2)
+	require.NoError(t, err)
+	require.Len(t, workspaces, 1)
+
+	require.NoError(t, s.RemoveMember(ctx, ws.ID, "viewer@example.com", "renamed@example.com", 2))
+}
+
+func TestLastAdminStays(t *testing.T) {
+	s, _ := newService(t)
+	ctx := context.Background()
+
+	ws, err := s.Create(ctx, "Marketing", adminEmail, adminUID)
+	require.NoError(t, err)
+
+	_, err = s.SetMember(ctx, ws.ID, adminEmail, workspace.RoleEditor, adminEmail, adminUID)
+	require.ErrorIs(t, err, workspace.ErrLastAdmin)
+	require.ErrorIs(t, s.RemoveMember(ctx, ws.ID, adminEmail, adminEmail, adminUID), workspace.ErrLastAdmin)
+
+	_, err = s.SetMember(ctx, ws.ID, "second@example.com", workspace.RoleAdmin, adminEmail, adminUID)
+	require.NoError(t, err)
+	require.NoError(t, s.RemoveMember(ctx, ws.ID, adminEmail, adminEmail, adminUID))
+}
+
+func requireRole(t *testing.T, want workspace.Role, snapshot json.RawMessage) {
+	t.Helper()
+
+	var member workspace.Member
+	require.NoError(t, json.Unmarshal(snapshot, &member))
+	require.Equal(t, want, member.Role)
+}
@@ -3,6 +3,9 @@ package instrumented
 import (
 	"context"
 	"time"
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
 	"url-shortener/internal/lib/metrics"
 	"url-shortener/internal/storage"
 )
@@ -15,34 +18,342 @@ func New(next storage.Storage) *Storage {
 	return &Storage{next: next}
 }
 
-func (s *Storage) SaveURL(ctx context.Context, alias, originalURL, ownerEmail string) error {
+func (s *Storage) SaveURL(ctx context.Context, link domain.Link) error {
 	const op = "SaveURL"
 	start := time.Now()
-	err := s.next.SaveURL(ctx, alias, originalURL, ownerEmail)
+	err := s.next.SaveURL(ctx, link)
 	s.recordMetrics(op, err, start)
 	return err
 }
-func (s *Storage) Url(ctx context.Context, alias string) (string, error) {
-	const op = "Url"
+func (s *Storage) AliasByNormalizedURL(ctx context.Context, owner domain.Owner, normalizedURL string, redirectType int) (string, error) {
+	const op = "AliasByNormalizedURL"
 	start := time.Now()
-	url, err := s.next.Url(ctx, alias)
+	alias, err := s.next.AliasByNormalizedURL(ctx, owner, normalizedURL, redirectType)
 	s.recordMetrics(op, err, start)
-	return url, err
+	return alias, err
 }
-func (s *Storage) UrlOwner(ctx context.Context, alias string) (string, error) {
+func (s *Storage) UrlOwner(ctx context.Context, alias string) (domain.Owner, error) {
 	const op = "UrlOwner"
 	start := time.Now()
 	owner, err := s.next.UrlOwner(ctx, alias)
 	s.recordMetrics(op, err, start)
 	return owner, err
 }
-func (s *Storage) DeleteURL(ctx context.Context, alias string) error {
+func (s *Storage) BackfillOwnerUID(ctx context.Context, ownerEmail string, uid int64) (int, error) {
+	const op = "BackfillOwnerUID"
+	start := time.Now()
+	backfilled, err := s.next.BackfillOwnerUID(ctx, ownerEmail, uid)
+	s.recordMetrics(op, err, start)
+	return backfilled, err
+}
+func (s *Storage) DeleteURL(ctx context.Context, alias, deletedBy string, deletedAt time.Time) error {
 	const op = "DeleteURL"
 	start := time.Now()
-	err := s.next.DeleteURL(ctx, alias)
+	err := s.next.DeleteURL(ctx, alias, deletedBy, deletedAt)
+	s.recordMetrics(op, err, start)
+	return err
+}
+func (s *Storage) RestoreURL(ctx context.Context, alias string) error {
+	const op = "RestoreURL"
+	start := time.Now()
+	err := s.next.RestoreURL(ctx, alias)
+	s.recordMetrics(op, err, start)
+	return err
+}
+func (s *Storage) PurgeURL(ctx context.Context, alias string, releasedAt time.Time) error {
+	const op = "PurgeURL"
+	start := time.Now()
+	err := s.next.PurgeURL(ctx, alias, releasedAt)
+	s.recordMetrics(op, err, start)
+	return err
+}
+func (s *Storage) ReleaseAliases(ctx context.Context, now time.Time) (int, error) {
+	const op = "ReleaseAliases"
+	start := time.Now()
+	released, err := s.next.ReleaseAliases(ctx, now)
+	s.recordMetrics(op, err, start)
+	return released, err
+}
+func (s *Storage) Link(ctx context.Context, alias string) (domain.Link, error) {
+	const op = "Link"
+	start := time.Now()
+	link, err := s.next.Link(ctx, alias)
+	s.recordMetrics(op, err, start)
+	return link, err
+}
+func (s *Storage) DeletedLink(ctx context.Context, alias string) (domain.Link, error) {
+	const op = "DeletedLink"
+	start := time.Now()
+	link, err := s.next.DeletedLink(ctx, alias)
+	s.recordMetrics(op, err, start)
+	return link, err
+}
+func (s *Storage) ListURLs(ctx context.Context, owner domain.Owner, filter domain.ListFilter) ([]domain.Link, error) {
+	const op = "ListURLs"
+	start := time.Now()
+	links, err := s.next.ListURLs(ctx, owner, filter)
+	s.recordMetrics(op, err, start)
+	return links, err
+}
+func (s *Storage) SearchURLs(ctx context.Context, owner domain.Owner, terms []string, limit, offset int) ([]domain.Link, error) {
+	const op = "SearchURLs"
+	start := time.Now()
+	links, err := s.next.SearchURLs(ctx, owner, terms, limit, offset)
+	s.recordMetrics(op, err, start)
+	return links, err
+}
+func (s *Storage) ListDeletedURLs(ctx context.Context, owner domain.Owner) ([]domain.Link, error) {
+	const op = "ListDeletedURLs"
+	start := time.Now()
+	links, err := s.next.ListDeletedURLs(ctx, owner)
+	s.recordMetrics(op, err, start)
+	return links, err
+}
+func (s *Storage) DeletedURLsBefore(ctx context.Context, deletedBefore time.Time, limit int) ([]domain.Link, error) {
+	const op = "DeletedURLsBefore"
+	start := time.Now()
+	links, err := s.next.DeletedURLsBefore(ctx, deletedBefore, limit)
+	s.recordMetrics(op, err, start)
+	return links, err
+}
+func (s *Storage) URLsToCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]domain.Link, error) {
+	const op = "URLsToCheck"
+	start := time.Now()
+	links, err := s.next.URLsToCheck(ctx, checkedBefore, limit)
+	s.recordMetrics(op, err, start)
+	return links, err
+}
+func (s *Storage) SaveHealthCheck(ctx context.Context, alias string, statusCode int, checkedAt time.Time, checkErr string) error {
+	const op = "SaveHealthCheck"
+	start := time.Now()
+	err := s.next.SaveHealthCheck(ctx, alias, statusCode, checkedAt, checkErr)
+	s.recordMetrics(op, err, start)
+	return err
+}
+func (s *Storage) CountBrokenURLs(ctx context.Context) (int, error) {
+	const op = "CountBrokenURLs"
+	start := time.Now()
+	count, err := s.next.CountBrokenURLs(ctx)
+	s.recordMetrics(op, err, start)
+	return count, err
+}
+func (s *Storage) RecordClick(ctx context.Context, click domain.Click) error {
+	const op = "RecordClick"
+	start := time.Now()
+	err := s.next.RecordClick(ctx, click)
+	s.recordMetrics(op, err, start)
+	return err
+}
+func (s *Storage) ClicksByUTMVersion(ctx context.Context, alias string) ([]domain.UTMVersion, error) {
+	const op = "ClicksByUTMVersion"
+	start := time.Now()
+	versions, err := s.next.ClicksByUTMVersion(ctx, alias)
+	s.recordMetrics(op, err, start)
+	return versions, err
+}
+func (s *Storage) ClicksByVariant(ctx context.Context, alias string) (map[string]int, error) {
+	const op = "ClicksByVariant"
+	start := time.Now()
+	clicks, err := s.next.ClicksByVariant(ctx, alias)
+	s.recordMetrics(op, err, start)
+	return clicks, err
+}
+func (s *Storage) ClaimClick(ctx context.Context, alias string) error {
+	const op = "ClaimClick"
+	start := time.Now()
+	err := s.next.ClaimClick(ctx, alias)
+	s.recordMetrics(op, err, start)
+	return err
+}
+func (s *Storage) SetURL(ctx context.Context, alias, rawURL, normalizedURL, changedBy string, changedAt time.Time) error {
+	const op = "SetURL"
+	start := time.Now()
+	err := s.next.SetURL(ctx, alias, rawURL, normalizedURL, changedBy, changedAt)
+	s.recordMetrics(op, err, start)
+	return err
+}
+func (s *Storage) UpdateLink(ctx context.Context, alias string, upd domain.LinkUpdate, normalizedURL, changedBy string, changedAt time.Time) error {
+	const op = "UpdateLink"
+	start := time.Now()
+	err := s.next.UpdateLink(ctx, alias, upd, normalizedURL, changedBy, changedAt)
+	s.recordMetrics(op, err, start)
+	return err
+}
+func (s *Storage) URLHistory(ctx context.Context, alias string) ([]domain.DestinationChange, error) {
+	const op = "URLHistory"
+	start := time.Now()
+	history, err := s.next.URLHistory(ctx, alias)
+	s.recordMetrics(op, err, start)
+	return history, err
+}
+func (s *Storage) DestinationChange(ctx context.Context, alias string, id int64) (domain.DestinationChange, error) {
+	const op = "DestinationChange"
+	start := time.Now()
+	change, err := s.next.DestinationChange(ctx, alias, id)
+	s.recordMetrics(op, err, start)
+	return change, err
+}
+func (s *Storage) SaveTransfer(ctx context.Context, transfer domain.Transfer) error {
+	const op = "SaveTransfer"
+	start := time.Now()
+	err := s.next.SaveTransfer(ctx, transfer)
 	s.recordMetrics(op, err, start)
 	return err
 }
+func (s *Storage) Transfer(ctx context.Context, alias string) (domain.Transfer, error) {
+	const op = "Transfer"
+	start := time.Now()
+	transfer, err := s.next.Transfer(ctx, alias)
+	s.recordMetrics(op, err, start)
+	return transfer, err
+}
+func (s *Storage) IncomingTransfers(ctx context.Context, toEmail string, now time.Time) ([]domain.Transfer, error) {
+	const op = "IncomingTransfers"
+	start := time.Now()
+	transfers, err := s.next.IncomingTransfers(ctx, toEmail, now)
+	s.recordMetrics(op, err, start)
+	return transfers, err
+}
+func (s *Storage) DeleteTransfer(ctx context.Context, alias string) error {
+	const op = "DeleteTransfer"
+	start := time.Now()
+	err := s.next.DeleteTransfer(ctx, alias)
+	s.recordMetrics(op, err, start)
+	return err
+}
+func (s *Storage) TransferURL(ctx context.Context, alias, fromEmail, toEmail string, toUID int64) error {
+	const op = "TransferURL"
+	start := time.Now()
+	err := s.next.TransferURL(ctx, alias, fromEmail, toEmail, toUID)
+	s.recordMetrics(op, err, start)
+	return err
+}
+func (s *Storage) TransferAllURLs(ctx context.Context, fromEmail, toEmail string) ([]string, error) {
+	const op = "TransferAllURLs"
+	start := time.Now()
+	aliases, err := s.next.TransferAllURLs(ctx, fromEmail, toEmail)
+	s.recordMetrics(op, err, start)
+	return aliases, err
+}
+func (s *Storage) AppendAudit(ctx context.Context, entry audit.Entry) error {
+	const op = "AppendAudit"
+	start := time.Now()
+	err := s.next.AppendAudit(ctx, entry)
+	s.recordMetrics(op, err, start)
+	return err
+}
+func (s *Storage) AuditLog(ctx context.Context, filter audit.Filter) ([]audit.Entry, error) {
+	const op = "AuditLog"
+	start := time.Now()
+	entries, err := s.next.AuditLog(ctx, filter)
+	s.recordMetrics(op, err, start)
+	return entries, err
+}
+func (s *Storage) CreateWorkspace(ctx context.Context, ws workspace.Workspace, creatorUID int64) (workspace.Workspace, error) {
+	const op = "CreateWorkspace"
+	start := time.Now()
+	created, err := s.next.CreateWorkspace(ctx, ws, creatorUID)
+	s.recordMetrics(op, err, start)
+	return created, err
+}
+func (s *Storage) Workspace(ctx context.Context, id int64) (workspace.Workspace, error) {
+	const op = "Workspace"
+	start := time.Now()
+	ws, err := s.next.Workspace(ctx, id)
+	s.recordMetrics(op, err, start)
+	return ws, err
+}
+func (s *Storage) Workspaces(ctx context.Context, memberEmail string, memberUID int64) ([]workspace.Workspace, error) {
+	const op = "Workspaces"
+	start := time.Now()
+	workspaces, err := s.next.Workspaces(ctx, memberEmail, memberUID)
+	s.recordMetrics(op, err, start)
+	return workspaces, err
+}
+func (s *Storage) WorkspaceMember(ctx context.Context, workspaceID int64, email string) (workspace.Member, error) {
+	const op = "WorkspaceMember"
+	start := time.Now()
+	member, err := s.next.WorkspaceMember(ctx, workspaceID, email)
+	s.recordMetrics(op, err, start)
+	return member, err
+}
+func (s *Storage) WorkspaceMemberByUser(ctx context.Context, workspaceID int64, email string, uid int64) (workspace.Member, error) {
+	const op = "WorkspaceMemberByUser"
+	start := time.Now()
+	member, err := s.next.WorkspaceMemberByUser(ctx, workspaceID, email, uid)
+	s.recordMetrics(op, err, start)
+	return member, err
+}
+func (s *Storage) BackfillMemberUID(ctx context.Context, email string, uid int64) (int, error) {
+	const op = "BackfillMemberUID"
+	start := time.Now()
+	backfilled, err := s.next.BackfillMemberUID(ctx, email, uid)
+	s.recordMetrics(op, err, start)
+	return backfilled, err
+}
+func (s *Storage) WorkspaceMembers(ctx context.Context, workspaceID int64) ([]workspace.Member, error) {
+	const op = "WorkspaceMembers"
+	start := time.Now()
+	members, err := s.next.WorkspaceMembers(ctx, workspaceID)
+	s.recordMetrics(op, err, start)
+	return members, err
+}
+func (s *Storage) SaveWorkspaceMember(ctx context.Context, member workspace.Member) error {
+	const op = "SaveWorkspaceMember"
+	start := time.Now()
+	err := s.next.SaveWorkspaceMember(ctx, member)
+	s.recordMetrics(op, err, start)
+	return err
+}
+func (s *Storage) DeleteWorkspaceMember(ctx context.Context, workspaceID int64, email string) error {
+	const op = "DeleteWorkspaceMember"
+	start := time.Now()
+	err := s.next.DeleteWorkspaceMember(ctx, workspaceID, email)
+	s.recordMetrics(op, err, start)
+	return err
+}
+func (s *Storage) CreateFolder(ctx context.Context, folder domain.Folder) (domain.Folder, error) {
+	const op = "CreateFolder"
+	start := time.Now()
+	created, err := s.next.CreateFolder(ctx, folder)
+	s.recordMetrics(op, err, start)
+	return created, err
+}
+func (s *Storage) Folder(ctx context.Context, id int64) (domain.Folder, error) {
+	const op = "Folder"
+	start := time.Now()
+	folder, err := s.next.Folder(ctx, id)
+	s.recordMetrics(op, err, start)
+	return folder, err
+}
+func (s *Storage) Folders(ctx context.Context, owner domain.Owner) ([]domain.Folder, error) {
+	const op = "Folders"
+	start := time.Now()
+	folders, err := s.next.Folders(ctx, owner)
+	s.recordMetrics(op, err, start)
+	return folders, err
+}
+func (s *Storage) RenameFolder(ctx context.Context, id int64, name string) error {
+	const op = "RenameFolder"
+	start := time.Now()
+	err := s.next.RenameFolder(ctx, id, name)
+	s.recordMetrics(op, err, start)
+	return err
+}
+func (s *Storage) DeleteFolder(ctx context.Context, id int64) error {
+	const op = "DeleteFolder"
+	start := time.Now()
+	err := s.next.DeleteFolder(ctx, id)
+	s.recordMetrics(op, err, start)
+	return err
+}
+func (s *Storage) TagStats(ctx context.Context, owner domain.Owner) ([]domain.TagStats, error) {
+	const op = "TagStats"
+	start := time.Now()
+	stats, err := s.next.TagStats(ctx, owner)
+	s.recordMetrics(op, err, start)
+	return stats, err
+}
 func (s *Storage) Close() error {
 	return s.next.Close()
 }
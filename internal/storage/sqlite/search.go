@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	domain "url-shortener/internal/domain/url"
+)
+
+// searchTriggers keep the FTS5 search index in sync with the links. Builds
+// without FTS5 drop them, since writing to the links would fail on the
+// missing module otherwise.
+var searchTriggers = []string{
+	"urls_search_insert",
+	"urls_search_update",
+	"urls_search_delete",
+	"urls_search_tag_insert",
+	"urls_search_tag_delete",
+}
+
+// SearchURLs retrieves up to limit live links of the given owner whose alias,
+// destination URL, title, tags or notes match all of the terms, best match
+// first, skipping the first offset matches. Every term has to match the start
+// of a word. Builds with the sqlite_fts5 tag rank the links with FTS5; other
+// builds fall back to pattern matching with the same column weights.
+func (s *Storage) SearchURLs(ctx context.Context, owner domain.Owner, terms []string, limit, offset int) ([]domain.Link, error) {
+	const op = "storage.sqlite.SearchURLs"
+
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	cond, args := ownerCondition(owner)
+	query, args := searchQuery(cond, args, terms, limit, offset)
+
+	links, err := s.queryLinks(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return links, nil
+}
@@ -0,0 +1,128 @@
+//go:build sqlite_fts5
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// FullTextSearch reports whether links are searched with the FTS5 index.
+const FullTextSearch = true
+
+// The search index is an FTS5 table whose rowid is the ID of the link. The
+// driver compiles FTS5 in only with the sqlite_fts5 build tag, so the index
+// is created when the storage is opened rather than by a migration, which
+// has to run on every build.
+var searchSchema = []string{
+	"CREATE VIRTUAL TABLE IF NOT EXISTS urls_search USING fts5(alias, url, title, tags, notes)",
+	`CREATE TRIGGER IF NOT EXISTS urls_search_insert AFTER INSERT ON urls BEGIN
+		INSERT INTO urls_search(rowid, alias, url, title, tags, notes) VALUES(new.id, new.alias, new.url, new.title, '', new.notes);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS urls_search_update AFTER UPDATE OF alias, url, title, notes ON urls BEGIN
+		UPDATE urls_search SET alias = new.alias, url = new.url, title = new.title, notes = new.notes WHERE rowid = new.id;
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS urls_search_delete AFTER DELETE ON urls BEGIN
+		DELETE FROM urls_search WHERE rowid = old.id;
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS urls_search_tag_insert AFTER INSERT ON link_tags BEGIN
+		UPDATE urls_search SET tags = (SELECT group_concat(tag, ' ') FROM link_tags WHERE alias = new.alias)
+		WHERE rowid = (SELECT id FROM urls WHERE alias = new.alias);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS urls_search_tag_delete AFTER DELETE ON link_tags BEGIN
+		UPDATE urls_search SET tags = COALESCE((SELECT group_concat(tag, ' ') FROM link_tags WHERE alias = old.alias), '')
+		WHERE rowid = (SELECT id FROM urls WHERE alias = old.alias);
+	END`,
+}
+
+// prepareSearch creates the search index. When any of its triggers is
+// missing, because the index is new or the database was used by a build
+// without FTS5, or the index predates one of its columns, the index is
+// rebuilt from the links. A database that is not migrated yet is left alone.
+func prepareSearch(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var migrated int
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM pragma_table_info('urls') WHERE name = 'notes'").Scan(&migrated)
+	if err != nil {
+		return err
+	}
+	if migrated == 0 {
+		return nil
+	}
+
+	placeholders := strings.Repeat("?, ", len(searchTriggers)-1) + "?"
+	args := make([]any, len(searchTriggers))
+	for i, name := range searchTriggers {
+		args[i] = name
+	}
+
+	var triggers int
+	err = tx.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name IN ("+placeholders+")", args...,
+	).Scan(&triggers)
+	if err != nil {
+		return err
+	}
+
+	var notes int
+	err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM pragma_table_info('urls_search') WHERE name = 'notes'").Scan(&notes)
+	if err != nil {
+		return err
+	}
+	if triggers == len(searchTriggers) && notes == 1 {
+		return nil
+	}
+
+	for _, name := range searchTriggers {
+		if _, err = tx.ExecContext(ctx, "DROP TRIGGER IF EXISTS "+name); err != nil {
+			return fmt.Errorf("failed to drop search trigger: %w", err)
+		}
+	}
+	if _, err = tx.ExecContext(ctx, "DROP TABLE IF EXISTS urls_search"); err != nil {
+		return fmt.Errorf("failed to drop search index: %w", err)
+	}
+
+	for _, stmt := range searchSchema {
+		if _, err = tx.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to create search index: %w", err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO urls_search(rowid, alias, url, title, tags, notes)
+		SELECT id, alias, url, title, COALESCE((SELECT group_concat(tag, ' ') FROM link_tags t WHERE t.alias = urls.alias), ''), notes
+		FROM urls`,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild search index: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// searchQuery returns the query selecting the links that match the owner
+// condition and all of the terms, each as the prefix of a word. The links are
+// ranked by BM25 with matches in the alias weighing most, then the title,
+// the tags, the notes and the destination URL.
+func searchQuery(cond string, condArgs []any, terms []string, limit, offset int) (string, []any) {
+	match := make([]string, len(terms))
+	for i, term := range terms {
+		match[i] = `"` + term + `"*`
+	}
+
+	query := "SELECT " + linkColumns + " FROM urls " +
+		"JOIN (SELECT rowid AS search_id, bm25(urls_search, 10.0, 1.0, 5.0, 3.0, 2.0) AS score FROM urls_search WHERE urls_search MATCH ?) " +
+		"ON search_id = id " +
+		"WHERE " + cond + " AND deleted_at IS NULL " +
+		"ORDER BY score, id DESC LIMIT ? OFFSET ?"
+
+	args := append([]any{strings.Join(match, " ")}, condArgs...)
+	return query, append(args, limit, offset)
+}
@@ -0,0 +1,58 @@
+//go:build sqlite_fts5
+
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/storage/sqlite"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchIndexWithoutNotesIsRebuilt(t *testing.T) {
+	s, path := newStorageAt(t)
+	ctx := context.Background()
+	require.NoError(t, s.SaveURL(ctx, domain.Link{
+		Alias:         "print",
+		URL:           "https://example.org/p",
+		NormalizedURL: "https://example.org/p",
+		OwnerEmail:    "owner@example.com",
+		RedirectType:  302,
+		Notes:         "Handed out with the brochure",
+	}))
+	require.NoError(t, s.Close())
+
+	// Put back the index of a build from before notes were searchable.
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	for _, stmt := range []string{
+		"DROP TRIGGER urls_search_insert",
+		"DROP TRIGGER urls_search_update",
+		"DROP TABLE urls_search",
+		"CREATE VIRTUAL TABLE urls_search USING fts5(alias, url, title, tags)",
+		`CREATE TRIGGER urls_search_insert AFTER INSERT ON urls BEGIN
+			INSERT INTO urls_search(rowid, alias, url, title, tags) VALUES(new.id, new.alias, new.url, new.title, '');
+		END`,
+		`CREATE TRIGGER urls_search_update AFTER UPDATE OF alias, url, title ON urls BEGIN
+			UPDATE urls_search SET alias = new.alias, url = new.url, title = new.title WHERE rowid = new.id;
+		END`,
+		"INSERT INTO urls_search(rowid, alias, url, title, tags) SELECT id, alias, url, title, '' FROM urls",
+	} {
+		_, err = db.Exec(stmt)
+		require.NoError(t, err, stmt)
+	}
+	require.NoError(t, db.Close())
+
+	reopened, err := sqlite.New(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reopened.Close() })
+
+	links, err := reopened.SearchURLs(ctx, domain.Owner{Email: "owner@example.com"}, []string{"brochure"}, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	require.Equal(t, "print", links[0].Alias)
+}
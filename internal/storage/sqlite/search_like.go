@@ -0,0 +1,72 @@
+//go:build !sqlite_fts5
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// FullTextSearch reports whether links are searched with the FTS5 index. It
+// is false in builds without the sqlite_fts5 tag, which fall back to pattern
+// matching that scans every link.
+const FullTextSearch = false
+
+// prepareSearch drops the triggers of an FTS5 search index left behind by a
+// build with FTS5, which would make writing to the links fail without it.
+func prepareSearch(ctx context.Context, db *sql.DB) error {
+	for _, name := range searchTriggers {
+		if _, err := db.ExecContext(ctx, "DROP TRIGGER IF EXISTS "+name); err != nil {
+			return fmt.Errorf("failed to drop search trigger: %w", err)
+		}
+	}
+	return nil
+}
+
+// wordStart matches a column whose lowercase text has a word starting with the
+// pattern argument, given as "*" + wordBoundary + term + "*". The column is
+// prefixed with a space so that its first word counts as well.
+func wordStart(column string) string {
+	return "(' ' || lower(" + column + ") GLOB ?)"
+}
+
+// wordBoundary matches the character before a word: like the FTS5 tokenizer,
+// anything that is not an ASCII letter or digit separates words, while
+// non-ASCII characters are taken as letters.
+const wordBoundary = "[^a-z0-9\u0080-\U0010FFFF]"
+
+// searchQuery returns the query selecting the links that match the owner
+// condition and all of the terms, each as the prefix of a word in their alias,
+// destination URL, title, tags or notes, as the FTS5 search does. Unlike FTS5
+// it only folds the case of ASCII letters and keeps diacritics. The links are
+// ranked by the columns the terms occur in, weighed like the FTS5 ranking:
+// the alias most, then the title, the tags, the notes and the destination
+// URL.
+func searchQuery(cond string, condArgs []any, terms []string, limit, offset int) (string, []any) {
+	tagged := "alias IN (SELECT alias FROM link_tags WHERE " + wordStart("tag") + ")"
+	match := fmt.Sprintf("%s OR %s OR %s OR %s OR %s", wordStart("alias"), wordStart("url"), wordStart("title"), tagged, wordStart("notes"))
+	rank := fmt.Sprintf("10 * %s + %s + 5 * %s + 3 * (%s) + 2 * %s", wordStart("alias"), wordStart("url"), wordStart("title"), tagged, wordStart("notes"))
+
+	var (
+		where, score         []string
+		whereArgs, scoreArgs []any
+	)
+	for _, term := range terms {
+		pattern := "*" + wordBoundary + term + "*"
+		where = append(where, "("+match+")")
+		score = append(score, rank)
+		for range 5 {
+			whereArgs = append(whereArgs, pattern)
+			scoreArgs = append(scoreArgs, pattern)
+		}
+	}
+
+	query := "SELECT " + linkColumns + " FROM urls " +
+		"WHERE " + cond + " AND deleted_at IS NULL AND " + strings.Join(where, " AND ") + " " +
+		"ORDER BY " + strings.Join(score, " + ") + " DESC, id DESC LIMIT ? OFFSET ?"
+
+	args := append(append(condArgs, whereArgs...), scoreArgs...)
+	return query, append(args, limit, offset)
+}
@@ -3,9 +3,14 @@ package sqlite
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
 	"url-shortener/internal/storage"
 
 	"github.com/mattn/go-sqlite3"
@@ -45,6 +50,14 @@ func New(storagePath string) (*Storage, error) {
 		return nil, fmt.Errorf("%s: failed to ping database: %w", op, pingErr)
 	}
 
+	if err = prepareSearch(context.Background(), db); err != nil {
+		if closeErr := db.Close(); closeErr != nil {
+			return nil, fmt.Errorf("%s: %w", op, errors.Join(err, closeErr))
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
 	return &Storage{db: db}, nil
 }
 
@@ -53,17 +66,50 @@ func (s *Storage) Close() error {
 	return s.db.Close()
 }
 
-// SaveURL saves the original URL with the given alias.
-func (s *Storage) SaveURL(ctx context.Context, alias, originalURL, ownerEmail string) error {
+// SaveURL saves the link under its alias together with its split variants. A
+// link with a UTM template version also gets the first entry of its template
+// history. Aliases of deleted links stay taken until their quarantine ends.
+func (s *Storage) SaveURL(ctx context.Context, link domain.Link) error {
 	const op = "storage.sqlite.SaveURL"
 
-	stmt, err := s.db.PrepareContext(ctx, "INSERT INTO urls(alias, url, owner_email) VALUES(?, ?, ?)")
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
-	defer func() { _ = stmt.Close() }()
+	defer func() { _ = tx.Rollback() }()
+
+	createdAt := nullTime(link.CreatedAt)
+
+	var utm domain.UTMTemplate
+	if link.UTM != nil {
+		utm = *link.UTM
+	}
+
+	targeting, err := encodeTargeting(link.Targeting)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var quarantined bool
+	err = tx.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM alias_quarantine WHERE alias = ? AND released_at > ?)",
+		link.Alias, time.Now().UTC(),
+	).Scan(&quarantined)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if quarantined {
+		return fmt.Errorf("%s: %w", op, storage.ErrURLExists)
+	}
 
-	_, err = stmt.ExecContext(ctx, alias, originalURL, ownerEmail)
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO urls(alias, url, normalized_url, owner_email, owner_uid, workspace_id, folder_id, redirect_type, title, notes, created_at, warn, passthrough, "+
+			"utm_source, utm_medium, utm_campaign, utm_content, utm_term, utm_version, targeting, sticky_variants, password_hash, max_clicks, not_before, not_after, signed_only) "+
+			"VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		link.Alias, link.URL, link.NormalizedURL, link.OwnerEmail, link.OwnerUID, link.WorkspaceID, link.FolderID, link.RedirectType, link.Title, link.Notes, createdAt, link.Warn, link.Passthrough,
+		utm.Source, utm.Medium, utm.Campaign, utm.Content, utm.Term, link.UTMVersion, targeting, link.StickyVariants, link.PasswordHash, link.MaxClicks,
+		nullTime(link.NotBefore), nullTime(link.NotAfter), link.SignedOnly,
+	)
 	if err != nil {
 		var sqliteErr sqlite3.Error
 		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
@@ -72,23 +118,85 @@ func (s *Storage) SaveURL(ctx context.Context, alias, originalURL, ownerEmail st
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if link.UTMVersion > 0 {
+		changedAt := time.Now()
+		if link.CreatedAt != nil {
+			changedAt = *link.CreatedAt
+		}
+		if err = insertUTMVersion(ctx, tx, link.Alias, link.UTMVersion, utm, changedAt); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err = insertVariants(ctx, tx, link.Alias, link.Variants); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = insertTags(ctx, tx, link.Alias, link.Tags); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
 	return nil
 }
 
-// Url retrieves the original URL for the given alias.
-func (s *Storage) Url(ctx context.Context, alias string) (string, error) {
-	const op = "storage.sqlite.Url"
+func insertVariants(ctx context.Context, tx *sql.Tx, alias string, variants []domain.Variant) error {
+	for i, v := range variants {
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO variants(alias, position, name, url, weight) VALUES(?, ?, ?, ?, ?)",
+			alias, i, v.Name, v.URL, v.Weight,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertTags(ctx context.Context, tx *sql.Tx, alias string, tags []string) error {
+	for _, tag := range tags {
+		if _, err := tx.ExecContext(ctx, "INSERT INTO link_tags(alias, tag) VALUES(?, ?)", alias, tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func insertUTMVersion(ctx context.Context, tx *sql.Tx, alias string, version int, utm domain.UTMTemplate, createdAt time.Time) error {
+	_, err := tx.ExecContext(ctx,
+		"INSERT INTO utm_templates(alias, version, source, medium, campaign, content, term, created_at) VALUES(?, ?, ?, ?, ?, ?, ?, ?)",
+		alias, version, utm.Source, utm.Medium, utm.Campaign, utm.Content, utm.Term, createdAt.UTC(),
+	)
+	return err
+}
+
+// AliasByNormalizedURL retrieves the alias the owner already uses for the given
+// normalized URL. Only links that redirect every visitor straight to it with
+// the given redirect type, for good, are considered: links with a password,
+// signed only mode, a warning, passthrough, a UTM template, targeting rules,
+// split variants, a click limit or an activation window are not.
+func (s *Storage) AliasByNormalizedURL(ctx context.Context, owner domain.Owner, normalizedURL string, redirectType int) (string, error) {
+	const op = "storage.sqlite.AliasByNormalizedURL"
 
-	stmt, err := s.db.PrepareContext(ctx, "SELECT url FROM urls WHERE alias = ?")
+	cond, args := ownerCondition(owner)
+	stmt, err := s.db.PrepareContext(ctx, `SELECT alias FROM urls WHERE `+cond+` AND normalized_url = ? AND redirect_type = ? AND deleted_at IS NULL
+		AND password_hash = '' AND signed_only = 0 AND warn = 0 AND passthrough = '' AND targeting = ''
+		AND max_clicks = 0 AND not_before IS NULL AND not_after IS NULL
+		AND utm_source = '' AND utm_medium = '' AND utm_campaign = '' AND utm_content = '' AND utm_term = ''
+		AND NOT EXISTS (SELECT 1 FROM variants v WHERE v.alias = urls.alias)
+		ORDER BY id LIMIT 1`)
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 	defer func() { _ = stmt.Close() }()
 
-	row := stmt.QueryRowContext(ctx, alias)
+	row := stmt.QueryRowContext(ctx, append(args, normalizedURL, redirectType)...)
 
-	var originalURL string
-	err = row.Scan(&originalURL)
+	var alias string
+	err = row.Scan(&alias)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return "", fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
@@ -96,38 +204,200 @@ func (s *Storage) Url(ctx context.Context, alias string) (string, error) {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
-	return originalURL, nil
+	return alias, nil
 }
 
-// UrlOwner retrieves the owner email for the given alias.
-func (s *Storage) UrlOwner(ctx context.Context, alias string) (string, error) {
+// UrlOwner retrieves the owner of the given alias. Deleted links are not
+// found.
+func (s *Storage) UrlOwner(ctx context.Context, alias string) (domain.Owner, error) {
 	const op = "storage.sqlite.UrlOwner"
 
-	stmt, err := s.db.PrepareContext(ctx, "SELECT owner_email FROM urls WHERE alias = ?")
+	stmt, err := s.db.PrepareContext(ctx, "SELECT owner_uid, owner_email, workspace_id FROM urls WHERE alias = ? AND deleted_at IS NULL")
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return domain.Owner{}, fmt.Errorf("%s: %w", op, err)
 	}
 	defer func() { _ = stmt.Close() }()
 
 	row := stmt.QueryRowContext(ctx, alias)
 
-	var ownerEmail string
-	err = row.Scan(&ownerEmail)
+	var owner domain.Owner
+	err = row.Scan(&owner.UID, &owner.Email, &owner.WorkspaceID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return "", fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+			return domain.Owner{}, fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
 		}
-		return "", fmt.Errorf("%s: %w", op, err)
+		return domain.Owner{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return owner, nil
+}
+
+// ownerCondition returns the WHERE condition matching the links of the owner
+// and its arguments. A workspace owner matches the links of the workspace.
+// A user matches their personal links: without a user ID only the email is
+// compared; with one, links recorded under the ID match, and so do links
+// without a recorded ID that carry the email.
+func ownerCondition(owner domain.Owner) (string, []any) {
+	if owner.WorkspaceID != 0 {
+		return "workspace_id = ?", []any{owner.WorkspaceID}
+	}
+	if owner.UID == 0 {
+		return "(workspace_id = 0 AND owner_email = ?)", []any{owner.Email}
+	}
+	return "(workspace_id = 0 AND (owner_uid = ? OR (owner_uid = 0 AND owner_email = ?)))", []any{owner.UID, owner.Email}
+}
+
+// BackfillOwnerUID records uid as the owner ID of the links of ownerEmail
+// that have none yet and returns how many there were.
+func (s *Storage) BackfillOwnerUID(ctx context.Context, ownerEmail string, uid int64) (int, error) {
+	const op = "storage.sqlite.BackfillOwnerUID"
+
+	result, err := s.db.ExecContext(ctx, "UPDATE urls SET owner_uid = ? WHERE owner_uid = 0 AND owner_email = ?", uid, ownerEmail)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	backfilled, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(backfilled), nil
+}
+
+// OwnersWithoutUID counts the links without a recorded owner ID per owner
+// email.
+func (s *Storage) OwnersWithoutUID(ctx context.Context) (map[string]int, error) {
+	const op = "storage.sqlite.OwnersWithoutUID"
+
+	rows, err := s.db.QueryContext(ctx, "SELECT owner_email, COUNT(*) FROM urls WHERE owner_uid = 0 GROUP BY owner_email")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	owners := make(map[string]int)
+	for rows.Next() {
+		var (
+			email string
+			count int
+		)
+		if err = rows.Scan(&email, &count); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		owners[email] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return owners, nil
+}
+
+// NormalizeURLs stores the canonical form of the destination URL, as given by
+// canonicalize, as the normalized URL of the links that still have their raw
+// destination there. The normalized_url migration could only copy the raw
+// URL, so links created before it never matched a deduplicated request. Links
+// whose URL canonicalize rejects keep the raw URL. It returns the number of
+// links updated.
+func (s *Storage) NormalizeURLs(ctx context.Context, canonicalize func(string) (string, error)) (int, error) {
+	const op = "storage.sqlite.NormalizeURLs"
+
+	raw, err := s.rawNormalizedURLs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	normalized := make(map[int64]string, len(raw))
+	for id, rawURL := range raw {
+		canonical, err := canonicalize(rawURL)
+		if err != nil || canonical == rawURL {
+			continue
+		}
+		normalized[id] = canonical
+	}
+
+	if len(normalized) == 0 {
+		return 0, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for id, canonical := range normalized {
+		if _, err = tx.ExecContext(ctx, "UPDATE urls SET normalized_url = ? WHERE id = ?", canonical, id); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return len(normalized), nil
+}
+
+// rawNormalizedURLs returns the destination URLs of the links whose
+// normalized URL is the destination URL as is, by link ID.
+func (s *Storage) rawNormalizedURLs(ctx context.Context) (map[int64]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT id, url FROM urls WHERE normalized_url = url")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	urls := make(map[int64]string)
+	for rows.Next() {
+		var (
+			id     int64
+			rawURL string
+		)
+		if err = rows.Scan(&id, &rawURL); err != nil {
+			return nil, err
+		}
+		urls[id] = rawURL
 	}
 
-	return ownerEmail, nil
+	return urls, rows.Err()
 }
 
-// DeleteURL removes the URL with the given alias from storage.
-func (s *Storage) DeleteURL(ctx context.Context, alias string) error {
+// DeleteURL moves the link with the given alias to the trash. The link keeps
+// its alias, clicks and settings until it is purged.
+func (s *Storage) DeleteURL(ctx context.Context, alias, deletedBy string, deletedAt time.Time) error {
 	const op = "storage.sqlite.DeleteURL"
 
-	stmt, err := s.db.PrepareContext(ctx, "DELETE FROM urls WHERE alias = ?")
+	stmt, err := s.db.PrepareContext(ctx, "UPDATE urls SET deleted_at = ?, deleted_by = ? WHERE alias = ? AND deleted_at IS NULL")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	result, err := stmt.ExecContext(ctx, deletedAt.UTC(), deletedBy, alias)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	return nil
+}
+
+// RestoreURL takes the link with the given alias out of the trash.
+func (s *Storage) RestoreURL(ctx context.Context, alias string) error {
+	const op = "storage.sqlite.RestoreURL"
+
+	stmt, err := s.db.PrepareContext(ctx, "UPDATE urls SET deleted_at = NULL, deleted_by = '' WHERE alias = ? AND deleted_at IS NOT NULL")
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -149,3 +419,1620 @@ func (s *Storage) DeleteURL(ctx context.Context, alias string) error {
 
 	return nil
 }
+
+// PurgeURL permanently removes the link with the given alias from the trash
+// together with its clicks, UTM template history, split variants, destination
+// history and pending transfer. The alias stays reserved until releasedAt.
+func (s *Storage) PurgeURL(ctx context.Context, alias string, releasedAt time.Time) error {
+	const op = "storage.sqlite.PurgeURL"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM urls WHERE alias = ? AND deleted_at IS NOT NULL", alias)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM clicks WHERE alias = ?", alias); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM utm_templates WHERE alias = ?", alias); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM variants WHERE alias = ?", alias); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM url_history WHERE alias = ?", alias); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM url_transfers WHERE alias = ?", alias); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM link_tags WHERE alias = ?", alias); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if releasedAt.After(time.Now()) {
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO alias_quarantine(alias, released_at) VALUES(?, ?) "+
+				"ON CONFLICT(alias) DO UPDATE SET released_at = excluded.released_at",
+			alias, releasedAt.UTC(),
+		)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ReleaseAliases forgets the quarantined aliases released before now and
+// returns how many there were.
+func (s *Storage) ReleaseAliases(ctx context.Context, now time.Time) (int, error) {
+	const op = "storage.sqlite.ReleaseAliases"
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM alias_quarantine WHERE released_at <= ?", now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	released, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(released), nil
+}
+
+// nullTime stores an optional time in UTC.
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+// linkColumns lists the urls columns scanned by scanLink, in order. Variants
+// live in their own table and are added by attachVariants.
+const linkColumns = "alias, url, normalized_url, owner_email, owner_uid, workspace_id, folder_id, redirect_type, title, notes, created_at, warn, passthrough, " +
+	"utm_source, utm_medium, utm_campaign, utm_content, utm_term, utm_version, targeting, sticky_variants, password_hash, max_clicks, clicks_used, not_before, not_after, signed_only, deleted_at, deleted_by, " +
+	"last_status_code, last_checked_at, last_check_error"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanLink(row rowScanner) (domain.Link, error) {
+	var (
+		link      domain.Link
+		utm       domain.UTMTemplate
+		targeting string
+		createdAt sql.NullTime
+		notBefore sql.NullTime
+		notAfter  sql.NullTime
+		deletedAt sql.NullTime
+		checkedAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&link.Alias,
+		&link.URL,
+		&link.NormalizedURL,
+		&link.OwnerEmail,
+		&link.OwnerUID,
+		&link.WorkspaceID,
+		&link.FolderID,
+		&link.RedirectType,
+		&link.Title,
+		&link.Notes,
+		&createdAt,
+		&link.Warn,
+		&link.Passthrough,
+		&utm.Source,
+		&utm.Medium,
+		&utm.Campaign,
+		&utm.Content,
+		&utm.Term,
+		&link.UTMVersion,
+		&targeting,
+		&link.StickyVariants,
+		&link.PasswordHash,
+		&link.MaxClicks,
+		&link.ClicksUsed,
+		&notBefore,
+		&notAfter,
+		&link.SignedOnly,
+		&deletedAt,
+		&link.DeletedBy,
+		&link.Health.StatusCode,
+		&checkedAt,
+		&link.Health.Error,
+	)
+	if err != nil {
+		return domain.Link{}, err
+	}
+
+	if createdAt.Valid {
+		link.CreatedAt = &createdAt.Time
+	}
+	if notBefore.Valid {
+		link.NotBefore = &notBefore.Time
+	}
+	if notAfter.Valid {
+		link.NotAfter = &notAfter.Time
+	}
+	if deletedAt.Valid {
+		link.DeletedAt = &deletedAt.Time
+	}
+	if !utm.IsZero() {
+		link.UTM = &utm
+	}
+	if targeting != "" {
+		if err = json.Unmarshal([]byte(targeting), &link.Targeting); err != nil {
+			return domain.Link{}, fmt.Errorf("failed to decode targeting rules: %w", err)
+		}
+	}
+	link.Protected = link.PasswordHash != ""
+	if checkedAt.Valid {
+		link.Health.CheckedAt = &checkedAt.Time
+		link.Health.Broken = domain.IsBrokenStatus(link.Health.StatusCode)
+	}
+
+	return link, nil
+}
+
+func (s *Storage) queryLinks(ctx context.Context, query string, args ...any) ([]domain.Link, error) {
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var links []domain.Link
+	for rows.Next() {
+		link, err := scanLink(rows)
+		if err != nil {
+			return nil, err
+		}
+		links = append(links, link)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err = s.attachVariants(ctx, links); err != nil {
+		return nil, err
+	}
+
+	if err = s.attachTags(ctx, links); err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// attachVariants loads the split variants of the links with a single query.
+func (s *Storage) attachVariants(ctx context.Context, links []domain.Link) error {
+	if len(links) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(links))
+	args := make([]any, 0, len(links))
+	for i, link := range links {
+		index[link.Alias] = i
+		args = append(args, link.Alias)
+	}
+
+	placeholders := strings.Repeat("?, ", len(args)-1) + "?"
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT alias, name, url, weight FROM variants WHERE alias IN ("+placeholders+") ORDER BY alias, position",
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to load variants: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var (
+			alias string
+			v     domain.Variant
+		)
+		if err = rows.Scan(&alias, &v.Name, &v.URL, &v.Weight); err != nil {
+			return fmt.Errorf("failed to load variants: %w", err)
+		}
+		if i, ok := index[alias]; ok {
+			links[i].Variants = append(links[i].Variants, v)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to load variants: %w", err)
+	}
+
+	return nil
+}
+
+// attachTags loads the tags of the links with a single query.
+func (s *Storage) attachTags(ctx context.Context, links []domain.Link) error {
+	if len(links) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(links))
+	args := make([]any, 0, len(links))
+	for i, link := range links {
+		index[link.Alias] = i
+		args = append(args, link.Alias)
+	}
+
+	placeholders := strings.Repeat("?, ", len(args)-1) + "?"
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT alias, tag FROM link_tags WHERE alias IN ("+placeholders+") ORDER BY alias, tag",
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to load tags: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var alias, tag string
+		if err = rows.Scan(&alias, &tag); err != nil {
+			return fmt.Errorf("failed to load tags: %w", err)
+		}
+		if i, ok := index[alias]; ok {
+			links[i].Tags = append(links[i].Tags, tag)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to load tags: %w", err)
+	}
+
+	return nil
+}
+
+// Link retrieves the link with the given alias. Deleted links are not found.
+func (s *Storage) Link(ctx context.Context, alias string) (domain.Link, error) {
+	const op = "storage.sqlite.Link"
+
+	link, err := s.link(ctx, "SELECT "+linkColumns+" FROM urls WHERE alias = ? AND deleted_at IS NULL", alias)
+	if err != nil {
+		return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return link, nil
+}
+
+// DeletedLink retrieves the link with the given alias from the trash.
+func (s *Storage) DeletedLink(ctx context.Context, alias string) (domain.Link, error) {
+	const op = "storage.sqlite.DeletedLink"
+
+	link, err := s.link(ctx, "SELECT "+linkColumns+" FROM urls WHERE alias = ? AND deleted_at IS NOT NULL", alias)
+	if err != nil {
+		return domain.Link{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return link, nil
+}
+
+// link retrieves the single link selected by query together with its split
+// variants.
+func (s *Storage) link(ctx context.Context, query string, args ...any) (domain.Link, error) {
+	stmt, err := s.db.PrepareContext(ctx, query)
+	if err != nil {
+		return domain.Link{}, err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	link, err := scanLink(stmt.QueryRowContext(ctx, args...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Link{}, storage.ErrURLNotFound
+		}
+		return domain.Link{}, err
+	}
+
+	links := []domain.Link{link}
+	if err = s.attachVariants(ctx, links); err != nil {
+		return domain.Link{}, err
+	}
+
+	if err = s.attachTags(ctx, links); err != nil {
+		return domain.Link{}, err
+	}
+
+	return links[0], nil
+}
+
+// ListURLs retrieves the links of the given owner that pass the tag and
+// folder filters, newest first. The workspace of the filter is ignored in
+// favour of the owner.
+func (s *Storage) ListURLs(ctx context.Context, owner domain.Owner, filter domain.ListFilter) ([]domain.Link, error) {
+	const op = "storage.sqlite.ListURLs"
+
+	cond, args := ownerCondition(owner)
+	for _, tag := range filter.Tags {
+		cond += " AND alias IN (SELECT alias FROM link_tags WHERE tag = ?)"
+		args = append(args, tag)
+	}
+	if filter.FolderID != 0 {
+		cond += " AND folder_id = ?"
+		args = append(args, filter.FolderID)
+	}
+
+	links, err := s.queryLinks(ctx, "SELECT "+linkColumns+" FROM urls WHERE "+cond+" AND deleted_at IS NULL ORDER BY id DESC", args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return links, nil
+}
+
+// ListDeletedURLs retrieves the links in the trash of the given owner, most
+// recently deleted first.
+func (s *Storage) ListDeletedURLs(ctx context.Context, owner domain.Owner) ([]domain.Link, error) {
+	const op = "storage.sqlite.ListDeletedURLs"
+
+	cond, args := ownerCondition(owner)
+	links, err := s.queryLinks(ctx,
+		"SELECT "+linkColumns+" FROM urls WHERE "+cond+" AND deleted_at IS NOT NULL ORDER BY deleted_at DESC, id DESC",
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return links, nil
+}
+
+// DeletedURLsBefore retrieves up to limit links moved to the trash before
+// deletedBefore, longest deleted first.
+func (s *Storage) DeletedURLsBefore(ctx context.Context, deletedBefore time.Time, limit int) ([]domain.Link, error) {
+	const op = "storage.sqlite.DeletedURLsBefore"
+
+	links, err := s.queryLinks(ctx,
+		"SELECT "+linkColumns+" FROM urls WHERE deleted_at IS NOT NULL AND deleted_at < ? ORDER BY deleted_at LIMIT ?",
+		deletedBefore.UTC(), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return links, nil
+}
+
+// URLsToCheck retrieves up to limit links whose destination was never checked
+// or was last checked before checkedBefore, least recently checked first.
+func (s *Storage) URLsToCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]domain.Link, error) {
+	const op = "storage.sqlite.URLsToCheck"
+
+	links, err := s.queryLinks(ctx,
+		"SELECT "+linkColumns+" FROM urls WHERE deleted_at IS NULL AND (last_checked_at IS NULL OR last_checked_at < ?) "+
+			"ORDER BY last_checked_at IS NOT NULL, last_checked_at LIMIT ?",
+		checkedBefore.UTC(), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return links, nil
+}
+
+// SaveHealthCheck records the result of a destination health check.
+func (s *Storage) SaveHealthCheck(ctx context.Context, alias string, statusCode int, checkedAt time.Time, checkErr string) error {
+	const op = "storage.sqlite.SaveHealthCheck"
+
+	stmt, err := s.db.PrepareContext(ctx, "UPDATE urls SET last_status_code = ?, last_checked_at = ?, last_check_error = ? WHERE alias = ?")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	result, err := stmt.ExecContext(ctx, statusCode, checkedAt.UTC(), checkErr, alias)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	return nil
+}
+
+// CountBrokenURLs returns the number of checked links whose last check failed.
+func (s *Storage) CountBrokenURLs(ctx context.Context) (int, error) {
+	const op = "storage.sqlite.CountBrokenURLs"
+
+	stmt, err := s.db.PrepareContext(ctx, "SELECT COUNT(*) FROM urls WHERE deleted_at IS NULL AND last_checked_at IS NOT NULL AND (last_status_code = 0 OR last_status_code >= 400)")
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	var count int
+	err = stmt.QueryRowContext(ctx).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+// setUTMTemplate replaces the UTM template of a link within tx and records it
+// as a new template version, which it returns. Bumping the version in the
+// UPDATE itself takes the write lock before the version is read, so
+// concurrent changes get distinct versions.
+func setUTMTemplate(ctx context.Context, tx *sql.Tx, alias string, utm domain.UTMTemplate, changedAt time.Time) (int, error) {
+	var version int
+	err := tx.QueryRowContext(ctx,
+		"UPDATE urls SET utm_source = ?, utm_medium = ?, utm_campaign = ?, utm_content = ?, utm_term = ?, utm_version = utm_version + 1 WHERE alias = ? RETURNING utm_version",
+		utm.Source, utm.Medium, utm.Campaign, utm.Content, utm.Term, alias,
+	).Scan(&version)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, storage.ErrURLNotFound
+		}
+		return 0, err
+	}
+
+	if err = insertUTMVersion(ctx, tx, alias, version, utm, changedAt); err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// RecordClick stores a followed redirect.
+func (s *Storage) RecordClick(ctx context.Context, click domain.Click) error {
+	const op = "storage.sqlite.RecordClick"
+
+	stmt, err := s.db.PrepareContext(ctx, "INSERT INTO clicks(alias, utm_version, variant, clicked_at) VALUES(?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	_, err = stmt.ExecContext(ctx, click.Alias, click.UTMVersion, click.Variant, click.ClickedAt.UTC())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ClicksByUTMVersion counts the clicks of a link per UTM template version. Every
+// recorded template version is listed, including versions without clicks.
+func (s *Storage) ClicksByUTMVersion(ctx context.Context, alias string) ([]domain.UTMVersion, error) {
+	const op = "storage.sqlite.ClicksByUTMVersion"
+
+	stmt, err := s.db.PrepareContext(ctx, `
+		SELECT v.version,
+			COALESCE(t.source, ''), COALESCE(t.medium, ''), COALESCE(t.campaign, ''), COALESCE(t.content, ''), COALESCE(t.term, ''),
+			t.created_at,
+			(SELECT COUNT(*) FROM clicks c WHERE c.alias = ? AND c.utm_version = v.version)
+		FROM (
+			SELECT version FROM utm_templates WHERE alias = ?
+			UNION
+			SELECT DISTINCT utm_version FROM clicks WHERE alias = ?
+		) v
+		LEFT JOIN utm_templates t ON t.alias = ? AND t.version = v.version
+		ORDER BY v.version`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	rows, err := stmt.QueryContext(ctx, alias, alias, alias, alias)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var versions []domain.UTMVersion
+	for rows.Next() {
+		var (
+			v         domain.UTMVersion
+			createdAt sql.NullTime
+		)
+		err = rows.Scan(
+			&v.Version,
+			&v.Template.Source,
+			&v.Template.Medium,
+			&v.Template.Campaign,
+			&v.Template.Content,
+			&v.Template.Term,
+			&createdAt,
+			&v.Clicks,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if createdAt.Valid {
+			v.CreatedAt = &createdAt.Time
+		}
+		versions = append(versions, v)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return versions, nil
+}
+
+// encodeTargeting stores targeting rules as a JSON array, keeping their order.
+// A link without rules gets an empty string.
+func encodeTargeting(rules []domain.TargetingRule) (string, error) {
+	if len(rules) == 0 {
+		return "", nil
+	}
+
+	encoded, err := json.Marshal(rules)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode targeting rules: %w", err)
+	}
+
+	return string(encoded), nil
+}
+
+// SetURL repoints the link to a new destination and records the change in its
+// destination history. The health of the old destination is forgotten.
+// Setting the current destination again changes nothing.
+func (s *Storage) SetURL(ctx context.Context, alias, rawURL, normalizedURL, changedBy string, changedAt time.Time) error {
+	const op = "storage.sqlite.SetURL"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var oldURL string
+	err = tx.QueryRowContext(ctx, "SELECT url FROM urls WHERE alias = ? AND deleted_at IS NULL", alias).Scan(&oldURL)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if oldURL == rawURL {
+		return nil
+	}
+
+	if err = setURL(ctx, tx, alias, oldURL, rawURL, normalizedURL, changedBy, changedAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// setURL repoints the link from oldURL to rawURL within tx, records the
+// change in the destination history and forgets the health of the old
+// destination.
+func setURL(ctx context.Context, tx *sql.Tx, alias, oldURL, rawURL, normalizedURL, changedBy string, changedAt time.Time) error {
+	_, err := tx.ExecContext(ctx,
+		"UPDATE urls SET url = ?, normalized_url = ?, last_status_code = 0, last_checked_at = NULL, last_check_error = '' WHERE alias = ?",
+		rawURL, normalizedURL, alias,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO url_history(alias, old_url, new_url, changed_by, changed_at) VALUES(?, ?, ?, ?, ?)",
+		alias, oldURL, rawURL, changedBy, changedAt.UTC(),
+	)
+	return err
+}
+
+// UpdateLink applies all changes of upd to a link in one transaction, so a
+// failing change leaves the link as it was. A new destination is handled as by
+// SetURL, with normalizedURL as its canonical form, and a new UTM template is
+// recorded as a new template version; tags are stored as given.
+func (s *Storage) UpdateLink(ctx context.Context, alias string, upd domain.LinkUpdate, normalizedURL, changedBy string, changedAt time.Time) error {
+	const op = "storage.sqlite.UpdateLink"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var oldURL string
+	err = tx.QueryRowContext(ctx, "SELECT url FROM urls WHERE alias = ? AND deleted_at IS NULL", alias).Scan(&oldURL)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if upd.URL != nil && *upd.URL != oldURL {
+		if err = setURL(ctx, tx, alias, oldURL, *upd.URL, normalizedURL, changedBy, changedAt); err != nil {
+			return fmt.Errorf("%s: failed to set url: %w", op, err)
+		}
+	}
+
+	if upd.UTM != nil {
+		if _, err = setUTMTemplate(ctx, tx, alias, *upd.UTM, changedAt); err != nil {
+			return fmt.Errorf("%s: failed to set utm template: %w", op, err)
+		}
+	}
+
+	if upd.Targeting != nil {
+		targeting, err := encodeTargeting(*upd.Targeting)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if _, err = tx.ExecContext(ctx, "UPDATE urls SET targeting = ? WHERE alias = ?", targeting, alias); err != nil {
+			return fmt.Errorf("%s: failed to set targeting rules: %w", op, err)
+		}
+	}
+
+	if upd.Variants != nil {
+		if _, err = tx.ExecContext(ctx, "DELETE FROM variants WHERE alias = ?", alias); err != nil {
+			return fmt.Errorf("%s: failed to set variants: %w", op, err)
+		}
+		if err = insertVariants(ctx, tx, alias, *upd.Variants); err != nil {
+			return fmt.Errorf("%s: failed to set variants: %w", op, err)
+		}
+	}
+
+	if upd.StickyVariants != nil {
+		if _, err = tx.ExecContext(ctx, "UPDATE urls SET sticky_variants = ? WHERE alias = ?", *upd.StickyVariants, alias); err != nil {
+			return fmt.Errorf("%s: failed to set sticky variants: %w", op, err)
+		}
+	}
+
+	if upd.SignedOnly != nil {
+		if _, err = tx.ExecContext(ctx, "UPDATE urls SET signed_only = ? WHERE alias = ?", *upd.SignedOnly, alias); err != nil {
+			return fmt.Errorf("%s: failed to set signed only: %w", op, err)
+		}
+	}
+
+	if upd.Tags != nil {
+		if _, err = tx.ExecContext(ctx, "DELETE FROM link_tags WHERE alias = ?", alias); err != nil {
+			return fmt.Errorf("%s: failed to set tags: %w", op, err)
+		}
+		if err = insertTags(ctx, tx, alias, *upd.Tags); err != nil {
+			return fmt.Errorf("%s: failed to set tags: %w", op, err)
+		}
+	}
+
+	if upd.FolderID != nil {
+		if _, err = tx.ExecContext(ctx, "UPDATE urls SET folder_id = ? WHERE alias = ?", *upd.FolderID, alias); err != nil {
+			return fmt.Errorf("%s: failed to set folder: %w", op, err)
+		}
+	}
+
+	if upd.Notes != nil {
+		if _, err = tx.ExecContext(ctx, "UPDATE urls SET notes = ? WHERE alias = ?", *upd.Notes, alias); err != nil {
+			return fmt.Errorf("%s: failed to set notes: %w", op, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// URLHistory retrieves the destination changes of a link, newest first.
+func (s *Storage) URLHistory(ctx context.Context, alias string) ([]domain.DestinationChange, error) {
+	const op = "storage.sqlite.URLHistory"
+
+	stmt, err := s.db.PrepareContext(ctx,
+		"SELECT id, old_url, new_url, changed_by, changed_at FROM url_history WHERE alias = ? ORDER BY id DESC",
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	rows, err := stmt.QueryContext(ctx, alias)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var history []domain.DestinationChange
+	for rows.Next() {
+		var c domain.DestinationChange
+		if err = rows.Scan(&c.ID, &c.OldURL, &c.NewURL, &c.ChangedBy, &c.ChangedAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		history = append(history, c)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return history, nil
+}
+
+// DestinationChange retrieves one destination change of a link.
+func (s *Storage) DestinationChange(ctx context.Context, alias string, id int64) (domain.DestinationChange, error) {
+	const op = "storage.sqlite.DestinationChange"
+
+	stmt, err := s.db.PrepareContext(ctx,
+		"SELECT id, old_url, new_url, changed_by, changed_at FROM url_history WHERE alias = ? AND id = ?",
+	)
+	if err != nil {
+		return domain.DestinationChange{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	var c domain.DestinationChange
+	err = stmt.QueryRowContext(ctx, alias, id).Scan(&c.ID, &c.OldURL, &c.NewURL, &c.ChangedBy, &c.ChangedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.DestinationChange{}, fmt.Errorf("%s: %w", op, storage.ErrChangeNotFound)
+		}
+		return domain.DestinationChange{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return c, nil
+}
+
+// ClicksByVariant counts the clicks of a link per split variant. Clicks made
+// while the link had no variants are not included.
+func (s *Storage) ClicksByVariant(ctx context.Context, alias string) (map[string]int, error) {
+	const op = "storage.sqlite.ClicksByVariant"
+
+	stmt, err := s.db.PrepareContext(ctx, "SELECT variant, COUNT(*) FROM clicks WHERE alias = ? AND variant != '' GROUP BY variant")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	rows, err := stmt.QueryContext(ctx, alias)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	clicks := make(map[string]int)
+	for rows.Next() {
+		var (
+			variant string
+			count   int
+		)
+		if err = rows.Scan(&variant, &count); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		clicks[variant] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return clicks, nil
+}
+
+// ClaimClick uses up one click of a link. The limit check and the increment
+// are a single statement, so concurrent redirects can never exceed the click
+// limit. It returns storage.ErrURLExhausted once the limit is reached.
+// Deleted links are not found.
+func (s *Storage) ClaimClick(ctx context.Context, alias string) error {
+	const op = "storage.sqlite.ClaimClick"
+
+	result, err := s.db.ExecContext(ctx,
+		"UPDATE urls SET clicks_used = clicks_used + 1 WHERE alias = ? AND deleted_at IS NULL AND (max_clicks = 0 OR clicks_used < max_clicks)",
+		alias,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		var exists bool
+		err = s.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM urls WHERE alias = ? AND deleted_at IS NULL)", alias).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if !exists {
+			return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, storage.ErrURLExhausted)
+	}
+
+	return nil
+}
+
+// SaveTransfer stores a pending ownership transfer, replacing an earlier
+// transfer of the same link.
+func (s *Storage) SaveTransfer(ctx context.Context, transfer domain.Transfer) error {
+	const op = "storage.sqlite.SaveTransfer"
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO url_transfers(alias, from_email, to_email, requested_by, requested_at, expires_at)
+		VALUES(?, ?, ?, ?, ?, ?)
+		ON CONFLICT(alias) DO UPDATE SET from_email = excluded.from_email, to_email = excluded.to_email,
+		requested_by = excluded.requested_by, requested_at = excluded.requested_at, expires_at = excluded.expires_at`,
+		transfer.Alias, transfer.FromEmail, transfer.ToEmail, transfer.RequestedBy,
+		transfer.RequestedAt.UTC(), transfer.ExpiresAt.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+const transferColumns = "alias, from_email, to_email, requested_by, requested_at, expires_at"
+
+func scanTransfer(row rowScanner) (domain.Transfer, error) {
+	t := domain.Transfer{Pending: true}
+	err := row.Scan(&t.Alias, &t.FromEmail, &t.ToEmail, &t.RequestedBy, &t.RequestedAt, &t.ExpiresAt)
+	return t, err
+}
+
+// Transfer retrieves the pending ownership transfer of a link.
+func (s *Storage) Transfer(ctx context.Context, alias string) (domain.Transfer, error) {
+	const op = "storage.sqlite.Transfer"
+
+	t, err := scanTransfer(s.db.QueryRowContext(ctx,
+		"SELECT "+transferColumns+" FROM url_transfers WHERE alias = ?", alias,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Transfer{}, fmt.Errorf("%s: %w", op, storage.ErrTransferNotFound)
+		}
+		return domain.Transfer{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return t, nil
+}
+
+// IncomingTransfers retrieves the transfers of live links to toEmail that
+// have not expired at now, oldest first.
+func (s *Storage) IncomingTransfers(ctx context.Context, toEmail string, now time.Time) ([]domain.Transfer, error) {
+	const op = "storage.sqlite.IncomingTransfers"
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT t.alias, t.from_email, t.to_email, t.requested_by, t.requested_at, t.expires_at
+		FROM url_transfers t JOIN urls u ON u.alias = t.alias
+		WHERE t.to_email = ? AND t.expires_at > ? AND u.deleted_at IS NULL
+		ORDER BY t.requested_at`,
+		toEmail, now.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var transfers []domain.Transfer
+	for rows.Next() {
+		t, err := scanTransfer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		transfers = append(transfers, t)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return transfers, nil
+}
+
+// DeleteTransfer removes the pending ownership transfer of a link.
+func (s *Storage) DeleteTransfer(ctx context.Context, alias string) error {
+	const op = "storage.sqlite.DeleteTransfer"
+
+	result, err := s.db.ExecContext(ctx, "DELETE FROM url_transfers WHERE alias = ?", alias)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrTransferNotFound)
+	}
+
+	return nil
+}
+
+// TransferURL hands the live link with the given alias from fromEmail over to
+// toEmail, recorded under toUID, which is zero when the recipient's ID is not
+// known yet, takes it out of its folder and drops its pending transfer. It
+// returns storage.ErrURLNotFound when the link is gone, no longer owned by
+// fromEmail or owned by a workspace.
+func (s *Storage) TransferURL(ctx context.Context, alias, fromEmail, toEmail string, toUID int64) error {
+	const op = "storage.sqlite.TransferURL"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx,
+		"UPDATE urls SET owner_email = ?, owner_uid = ?, folder_id = 0 WHERE alias = ? AND owner_email = ? AND workspace_id = 0 AND deleted_at IS NULL",
+		toEmail, toUID, alias, fromEmail,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrURLNotFound)
+	}
+
+	if _, err = tx.ExecContext(ctx, "DELETE FROM url_transfers WHERE alias = ?", alias); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// TransferAllURLs hands every personal link of fromEmail, including the ones
+// in the trash, over to toEmail, takes them out of their folders and drops
+// their pending transfers. The links are matched by email and keep no owner ID
+// until the recipient claims them. It returns the aliases of the moved links.
+func (s *Storage) TransferAllURLs(ctx context.Context, fromEmail, toEmail string) ([]string, error) {
+	const op = "storage.sqlite.TransferAllURLs"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	rows, err := tx.QueryContext(ctx, "SELECT alias FROM urls WHERE owner_email = ? AND workspace_id = 0 ORDER BY alias", fromEmail)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var aliases []string
+	for rows.Next() {
+		var alias string
+		if err = rows.Scan(&alias); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		aliases = append(aliases, alias)
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	_ = rows.Close()
+
+	if _, err = tx.ExecContext(ctx, "UPDATE urls SET owner_email = ?, owner_uid = 0, folder_id = 0 WHERE owner_email = ? AND workspace_id = 0", toEmail, fromEmail); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM url_transfers WHERE from_email = ?", fromEmail); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return aliases, nil
+}
+
+// AppendAudit appends an entry to the audit log. The table refuses updates
+// and deletes, so entries cannot be changed once written.
+func (s *Storage) AppendAudit(ctx context.Context, entry audit.Entry) error {
+	const op = "storage.sqlite.AppendAudit"
+
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO audit_log(created_at, action, subject, subject_id, alias, actor_uid, actor_email, as_admin, request_id, client_ip, before, after)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.CreatedAt.UTC(), entry.Action, entry.Subject, entry.SubjectID, entry.Alias, entry.ActorUID, entry.ActorEmail, entry.AsAdmin,
+		entry.RequestID, entry.ClientIP, nullJSON(entry.Before), nullJSON(entry.After),
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AuditLog retrieves the audit log entries matching the filter, newest first.
+func (s *Storage) AuditLog(ctx context.Context, filter audit.Filter) ([]audit.Entry, error) {
+	const op = "storage.sqlite.AuditLog"
+
+	var (
+		conds []string
+		args  []any
+	)
+	if filter.ActorUID != 0 {
+		conds, args = append(conds, "actor_uid = ?"), append(args, filter.ActorUID)
+	}
+	if filter.ActorEmail != "" {
+		conds, args = append(conds, "actor_email = ?"), append(args, filter.ActorEmail)
+	}
+	if filter.Alias != "" {
+		conds, args = append(conds, "alias = ?"), append(args, filter.Alias)
+	}
+	if filter.Subject != "" {
+		conds, args = append(conds, "subject = ?"), append(args, filter.Subject)
+	}
+	if filter.SubjectID != 0 {
+		conds, args = append(conds, "subject_id = ?"), append(args, filter.SubjectID)
+	}
+	if filter.From != nil {
+		conds, args = append(conds, "created_at >= ?"), append(args, filter.From.UTC())
+	}
+	if filter.To != nil {
+		conds, args = append(conds, "created_at < ?"), append(args, filter.To.UTC())
+	}
+	if filter.BeforeID != 0 {
+		conds, args = append(conds, "id < ?"), append(args, filter.BeforeID)
+	}
+
+	query := "SELECT id, created_at, action, subject, subject_id, alias, actor_uid, actor_email, as_admin, request_id, client_ip, before, after FROM audit_log"
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY id DESC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []audit.Entry
+	for rows.Next() {
+		var (
+			e             audit.Entry
+			before, after sql.NullString
+		)
+		err = rows.Scan(&e.ID, &e.CreatedAt, &e.Action, &e.Subject, &e.SubjectID, &e.Alias, &e.ActorUID, &e.ActorEmail, &e.AsAdmin,
+			&e.RequestID, &e.ClientIP, &before, &after)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		if before.Valid {
+			e.Before = json.RawMessage(before.String)
+		}
+		if after.Valid {
+			e.After = json.RawMessage(after.String)
+		}
+		entries = append(entries, e)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return entries, nil
+}
+
+func nullJSON(raw json.RawMessage) sql.NullString {
+	if len(raw) == 0 {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(raw), Valid: true}
+}
+
+// CreateWorkspace saves the workspace with its creator, whose user ID is
+// creatorUID, as the first admin and returns it with its ID.
+func (s *Storage) CreateWorkspace(ctx context.Context, ws workspace.Workspace, creatorUID int64) (workspace.Workspace, error) {
+	const op = "storage.sqlite.CreateWorkspace"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return workspace.Workspace{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx,
+		"INSERT INTO workspaces(name, created_by, created_at) VALUES(?, ?, ?)",
+		ws.Name, ws.CreatedBy, ws.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return workspace.Workspace{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ws.ID, err = result.LastInsertId()
+	if err != nil {
+		return workspace.Workspace{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO workspace_members(workspace_id, email, uid, role, added_by, added_at) VALUES(?, ?, ?, ?, ?, ?)",
+		ws.ID, ws.CreatedBy, creatorUID, workspace.RoleAdmin, ws.CreatedBy, ws.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return workspace.Workspace{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return workspace.Workspace{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ws.Role = workspace.RoleAdmin
+
+	return ws, nil
+}
+
+// Workspace retrieves the workspace with the given ID.
+func (s *Storage) Workspace(ctx context.Context, id int64) (workspace.Workspace, error) {
+	const op = "storage.sqlite.Workspace"
+
+	var ws workspace.Workspace
+	err := s.db.QueryRowContext(ctx,
+		"SELECT id, name, created_by, created_at FROM workspaces WHERE id = ?", id,
+	).Scan(&ws.ID, &ws.Name, &ws.CreatedBy, &ws.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return workspace.Workspace{}, fmt.Errorf("%s: %w", op, storage.ErrWorkspaceNotFound)
+		}
+		return workspace.Workspace{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ws, nil
+}
+
+// Workspaces retrieves the workspaces the user is a member of, matched as in
+// WorkspaceMemberByUser, with their role in each, oldest first.
+func (s *Storage) Workspaces(ctx context.Context, memberEmail string, memberUID int64) ([]workspace.Workspace, error) {
+	const op = "storage.sqlite.Workspaces"
+
+	cond, args := memberCondition(memberEmail, memberUID)
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT w.id, w.name, w.created_by, w.created_at, m.role
+		FROM workspaces w JOIN workspace_members m ON m.workspace_id = w.id
+		WHERE `+cond+`
+		ORDER BY w.id, m.uid DESC`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var workspaces []workspace.Workspace
+	for rows.Next() {
+		var ws workspace.Workspace
+		if err = rows.Scan(&ws.ID, &ws.Name, &ws.CreatedBy, &ws.CreatedAt, &ws.Role); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		// The membership recorded under the user ID comes first and wins
+		// over one invited by email.
+		if n := len(workspaces); n > 0 && workspaces[n-1].ID == ws.ID {
+			continue
+		}
+		workspaces = append(workspaces, ws)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return workspaces, nil
+}
+
+const memberColumns = "workspace_id, email, uid, role, added_by, added_at"
+
+func scanMember(row rowScanner) (workspace.Member, error) {
+	var m workspace.Member
+	err := row.Scan(&m.WorkspaceID, &m.Email, &m.UID, &m.Role, &m.AddedBy, &m.AddedAt)
+	return m, err
+}
+
+// memberCondition returns the WHERE condition matching the memberships of a
+// user and its arguments, like ownerCondition does for links: without a user
+// ID only the email is compared; with one, memberships recorded under the ID
+// match, and so do memberships without a recorded ID that carry the email.
+func memberCondition(email string, uid int64) (string, []any) {
+	if uid == 0 {
+		return "m.email = ?", []any{email}
+	}
+	return "(m.uid = ? OR (m.uid = 0 AND m.email = ?))", []any{uid, email}
+}
+
+// WorkspaceMember retrieves the membership of email in the workspace.
+func (s *Storage) WorkspaceMember(ctx context.Context, workspaceID int64, email string) (workspace.Member, error) {
+	const op = "storage.sqlite.WorkspaceMember"
+
+	m, err := scanMember(s.db.QueryRowContext(ctx,
+		"SELECT "+memberColumns+" FROM workspace_members WHERE workspace_id = ? AND email = ?",
+		workspaceID, email,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return workspace.Member{}, fmt.Errorf("%s: %w", op, storage.ErrMemberNotFound)
+		}
+		return workspace.Member{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return m, nil
+}
+
+// WorkspaceMemberByUser retrieves the membership of the user with the given
+// email and ID in the workspace, matched as in memberCondition. A membership
+// recorded under the ID wins over one invited by email.
+func (s *Storage) WorkspaceMemberByUser(ctx context.Context, workspaceID int64, email string, uid int64) (workspace.Member, error) {
+	const op = "storage.sqlite.WorkspaceMemberByUser"
+
+	cond, args := memberCondition(email, uid)
+	m, err := scanMember(s.db.QueryRowContext(ctx,
+		"SELECT "+memberColumns+" FROM workspace_members m WHERE workspace_id = ? AND "+cond+" ORDER BY uid DESC LIMIT 1",
+		append([]any{workspaceID}, args...)...,
+	))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return workspace.Member{}, fmt.Errorf("%s: %w", op, storage.ErrMemberNotFound)
+		}
+		return workspace.Member{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return m, nil
+}
+
+// BackfillMemberUID records uid as the user ID of the memberships of email
+// that have none yet and returns how many there were.
+func (s *Storage) BackfillMemberUID(ctx context.Context, email string, uid int64) (int, error) {
+	const op = "storage.sqlite.BackfillMemberUID"
+
+	result, err := s.db.ExecContext(ctx, "UPDATE workspace_members SET uid = ? WHERE uid = 0 AND email = ?", uid, email)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	backfilled, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return int(backfilled), nil
+}
+
+// WorkspaceMembers retrieves the members of the workspace in the order they
+// were added.
+func (s *Storage) WorkspaceMembers(ctx context.Context, workspaceID int64) ([]workspace.Member, error) {
+	const op = "storage.sqlite.WorkspaceMembers"
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT "+memberColumns+" FROM workspace_members WHERE workspace_id = ? ORDER BY added_at, email",
+		workspaceID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var members []workspace.Member
+	for rows.Next() {
+		m, err := scanMember(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		members = append(members, m)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// SaveWorkspaceMember adds the member to its workspace, or changes the role
+// of an existing member, who keeps when and by whom they were added. It fails
+// with storage.ErrLastAdmin when the change would leave the workspace without
+// an admin.
+func (s *Storage) SaveWorkspaceMember(ctx context.Context, member workspace.Member) error {
+	const op = "storage.sqlite.SaveWorkspaceMember"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO workspace_members(workspace_id, email, uid, role, added_by, added_at)
+		VALUES(?, ?, ?, ?, ?, ?)
+		ON CONFLICT(workspace_id, email) DO UPDATE SET role = excluded.role`,
+		member.WorkspaceID, member.Email, member.UID, member.Role, member.AddedBy, member.AddedAt.UTC(),
+	)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintForeignKey {
+			return fmt.Errorf("%s: %w", op, storage.ErrWorkspaceNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if member.Role != workspace.RoleAdmin {
+		if err = keepWorkspaceAdmin(ctx, tx, member.WorkspaceID); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteWorkspaceMember removes email from the workspace. It fails with
+// storage.ErrLastAdmin when email is the last admin of the workspace.
+func (s *Storage) DeleteWorkspaceMember(ctx context.Context, workspaceID int64, email string) error {
+	const op = "storage.sqlite.DeleteWorkspaceMember"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx,
+		"DELETE FROM workspace_members WHERE workspace_id = ? AND email = ?", workspaceID, email,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrMemberNotFound)
+	}
+
+	if err = keepWorkspaceAdmin(ctx, tx, workspaceID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// keepWorkspaceAdmin fails with storage.ErrLastAdmin when the workspace has
+// no admin left. Called after the write in the same transaction, it sees the
+// change while holding the write lock, so two admins stepping down at once
+// cannot both leave.
+func keepWorkspaceAdmin(ctx context.Context, tx *sql.Tx, workspaceID int64) error {
+	var admins int
+	err := tx.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM workspace_members WHERE workspace_id = ? AND role = ?", workspaceID, workspace.RoleAdmin,
+	).Scan(&admins)
+	if err != nil {
+		return err
+	}
+
+	if admins == 0 {
+		return storage.ErrLastAdmin
+	}
+
+	return nil
+}
+
+// CreateFolder saves the folder and returns it with its ID.
+func (s *Storage) CreateFolder(ctx context.Context, folder domain.Folder) (domain.Folder, error) {
+	const op = "storage.sqlite.CreateFolder"
+
+	result, err := s.db.ExecContext(ctx,
+		"INSERT INTO folders(name, owner_email, owner_uid, workspace_id, created_at) VALUES(?, ?, ?, ?, ?)",
+		folder.Name, folder.OwnerEmail, folder.OwnerUID, folder.WorkspaceID, folder.CreatedAt.UTC(),
+	)
+	if err != nil {
+		return domain.Folder{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	folder.ID, err = result.LastInsertId()
+	if err != nil {
+		return domain.Folder{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return folder, nil
+}
+
+const folderColumns = "id, name, owner_email, owner_uid, workspace_id, created_at"
+
+func scanFolder(row rowScanner) (domain.Folder, error) {
+	var f domain.Folder
+	err := row.Scan(&f.ID, &f.Name, &f.OwnerEmail, &f.OwnerUID, &f.WorkspaceID, &f.CreatedAt)
+	return f, err
+}
+
+// Folder retrieves the folder with the given ID.
+func (s *Storage) Folder(ctx context.Context, id int64) (domain.Folder, error) {
+	const op = "storage.sqlite.Folder"
+
+	f, err := scanFolder(s.db.QueryRowContext(ctx, "SELECT "+folderColumns+" FROM folders WHERE id = ?", id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Folder{}, fmt.Errorf("%s: %w", op, storage.ErrFolderNotFound)
+		}
+		return domain.Folder{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return f, nil
+}
+
+// Folders retrieves the folders of the given owner sorted by name.
+func (s *Storage) Folders(ctx context.Context, owner domain.Owner) ([]domain.Folder, error) {
+	const op = "storage.sqlite.Folders"
+
+	cond, args := ownerCondition(owner)
+	rows, err := s.db.QueryContext(ctx, "SELECT "+folderColumns+" FROM folders WHERE "+cond+" ORDER BY name, id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var folders []domain.Folder
+	for rows.Next() {
+		f, err := scanFolder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		folders = append(folders, f)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return folders, nil
+}
+
+// RenameFolder changes the name of the folder with the given ID.
+func (s *Storage) RenameFolder(ctx context.Context, id int64, name string) error {
+	const op = "storage.sqlite.RenameFolder"
+
+	result, err := s.db.ExecContext(ctx, "UPDATE folders SET name = ? WHERE id = ?", name, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrFolderNotFound)
+	}
+
+	return nil
+}
+
+// DeleteFolder removes the folder with the given ID. Its links, including the
+// ones in the trash, stay and are taken out of the folder.
+func (s *Storage) DeleteFolder(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.DeleteFolder"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM folders WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrFolderNotFound)
+	}
+
+	if _, err = tx.ExecContext(ctx, "UPDATE urls SET folder_id = 0 WHERE folder_id = ?", id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// TagStats counts, per tag, the live links of the given owner that carry the
+// tag and the clicks those links received, sorted by tag.
+func (s *Storage) TagStats(ctx context.Context, owner domain.Owner) ([]domain.TagStats, error) {
+	const op = "storage.sqlite.TagStats"
+
+	cond, args := ownerCondition(owner)
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT t.tag, COUNT(*), COALESCE(SUM(c.clicks), 0)
+		FROM link_tags t
+		JOIN urls u ON u.alias = t.alias
+		LEFT JOIN (SELECT alias, COUNT(*) AS clicks FROM clicks GROUP BY alias) c ON c.alias = t.alias
+		WHERE `+cond+` AND u.deleted_at IS NULL
+		GROUP BY t.tag
+		ORDER BY t.tag`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var stats []domain.TagStats
+	for rows.Next() {
+		var ts domain.TagStats
+		if err = rows.Scan(&ts.Tag, &ts.Links, &ts.Clicks); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		stats = append(stats, ts)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return stats, nil
+}
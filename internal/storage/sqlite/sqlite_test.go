@@ -0,0 +1,1138 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
+	"url-shortener/internal/storage"
+	"url-shortener/internal/storage/sqlite"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// newStorage creates a migrated database in a temporary directory.
+func newStorage(t *testing.T) *sqlite.Storage {
+	t.Helper()
+
+	s, _ := newStorageAt(t)
+	return s
+}
+
+// newStorageAt is newStorage that also returns the path of the database.
+func newStorageAt(t *testing.T) (*sqlite.Storage, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "storage.db")
+
+	m, err := migrate.New("file://../../../migrations", "sqlite3://"+path)
+	require.NoError(t, err)
+	require.NoError(t, m.Up())
+	srcErr, dbErr := m.Close()
+	require.NoError(t, srcErr)
+	require.NoError(t, dbErr)
+
+	s, err := sqlite.New(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.Close() })
+
+	return s, path
+}
+
+func saveLink(t *testing.T, s *sqlite.Storage, alias string, maxClicks int) {
+	t.Helper()
+
+	err := s.SaveURL(context.Background(), domain.Link{
+		Alias:         alias,
+		URL:           "https://example.com/" + alias,
+		NormalizedURL: "https://example.com/" + alias,
+		OwnerEmail:    "owner@example.com",
+		RedirectType:  302,
+		MaxClicks:     maxClicks,
+	})
+	require.NoError(t, err)
+}
+
+// updateLink applies upd to the link without changing its destination.
+func updateLink(s *sqlite.Storage, alias string, upd domain.LinkUpdate) error {
+	return s.UpdateLink(context.Background(), alias, upd, "", "owner@example.com", time.Now())
+}
+
+func TestClaimClickConcurrent(t *testing.T) {
+	const (
+		maxClicks = 5
+		visitors  = 50
+	)
+
+	s := newStorage(t)
+	ctx := context.Background()
+	saveLink(t, s, "limited", maxClicks)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		claimed   int
+		exhausted int
+	)
+	start := make(chan struct{})
+	for range visitors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			err := s.ClaimClick(ctx, "limited")
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				claimed++
+			case errors.Is(err, storage.ErrURLExhausted):
+				exhausted++
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	require.Equal(t, maxClicks, claimed)
+	require.Equal(t, visitors-maxClicks, exhausted)
+
+	link, err := s.Link(ctx, "limited")
+	require.NoError(t, err)
+	require.Equal(t, maxClicks, link.ClicksUsed)
+	require.True(t, link.Exhausted())
+}
+
+func TestUpdateLinkUTMConcurrent(t *testing.T) {
+	const changes = 20
+
+	s := newStorage(t)
+	ctx := context.Background()
+	saveLink(t, s, "campaign", 0)
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := range changes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			utm := domain.UTMTemplate{Source: fmt.Sprintf("source-%d", i)}
+			if err := updateLink(s, "campaign", domain.LinkUpdate{UTM: &utm}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	link, err := s.Link(ctx, "campaign")
+	require.NoError(t, err)
+	require.Equal(t, changes, link.UTMVersion)
+
+	// Every change got its own template version.
+	history, err := s.ClicksByUTMVersion(ctx, "campaign")
+	require.NoError(t, err)
+	require.Len(t, history, changes)
+	for i, version := range history {
+		require.Equal(t, i+1, version.Version)
+	}
+}
+
+func TestClaimClick(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	saveLink(t, s, "once", 1)
+	saveLink(t, s, "unlimited", 0)
+
+	require.NoError(t, s.ClaimClick(ctx, "once"))
+	require.ErrorIs(t, s.ClaimClick(ctx, "once"), storage.ErrURLExhausted)
+
+	for range 3 {
+		require.NoError(t, s.ClaimClick(ctx, "unlimited"))
+	}
+	link, err := s.Link(ctx, "unlimited")
+	require.NoError(t, err)
+	require.Equal(t, 3, link.ClicksUsed)
+	require.False(t, link.Exhausted())
+
+	require.ErrorIs(t, s.ClaimClick(ctx, "missing"), storage.ErrURLNotFound)
+
+	require.NoError(t, s.DeleteURL(ctx, "unlimited", "owner@example.com", time.Now()))
+	require.ErrorIs(t, s.ClaimClick(ctx, "unlimited"), storage.ErrURLNotFound)
+}
+
+func TestActivationWindow(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	notBefore := time.Date(2026, time.May, 1, 11, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	err := s.SaveURL(ctx, domain.Link{
+		Alias:         "launch",
+		URL:           "https://example.com/launch",
+		NormalizedURL: "https://example.com/launch",
+		OwnerEmail:    "owner@example.com",
+		RedirectType:  302,
+		NotBefore:     &notBefore,
+	})
+	require.NoError(t, err)
+	saveLink(t, s, "always", 0)
+
+	links, err := s.ListURLs(ctx, domain.Owner{Email: "owner@example.com"}, domain.ListFilter{})
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+
+	require.Equal(t, "always", links[0].Alias)
+	require.Nil(t, links[0].NotBefore)
+	require.Nil(t, links[0].NotAfter)
+
+	require.Equal(t, "launch", links[1].Alias)
+	require.NotNil(t, links[1].NotBefore)
+	require.True(t, links[1].NotBefore.Equal(notBefore))
+	require.Nil(t, links[1].NotAfter)
+}
+
+func TestUpdateLinkSignedOnly(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	saveLink(t, s, "invite", 0)
+
+	link, err := s.Link(ctx, "invite")
+	require.NoError(t, err)
+	require.False(t, link.SignedOnly)
+
+	signedOnly := true
+	require.NoError(t, updateLink(s, "invite", domain.LinkUpdate{SignedOnly: &signedOnly}))
+	link, err = s.Link(ctx, "invite")
+	require.NoError(t, err)
+	require.True(t, link.SignedOnly)
+}
+
+func TestSoftDelete(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	saveLink(t, s, "gone", 0)
+	saveLink(t, s, "kept", 0)
+
+	deletedAt := time.Now().Add(-time.Hour)
+	require.NoError(t, s.DeleteURL(ctx, "gone", "admin@example.com", deletedAt))
+	require.ErrorIs(t, s.DeleteURL(ctx, "gone", "admin@example.com", deletedAt), storage.ErrURLNotFound)
+
+	_, err := s.Link(ctx, "gone")
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+	_, err = s.UrlOwner(ctx, "gone")
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	links, err := s.ListURLs(ctx, domain.Owner{Email: "owner@example.com"}, domain.ListFilter{})
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	require.Equal(t, "kept", links[0].Alias)
+
+	deleted, err := s.ListDeletedURLs(ctx, domain.Owner{Email: "owner@example.com"})
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+	require.Equal(t, "admin@example.com", deleted[0].DeletedBy)
+	require.True(t, deleted[0].DeletedAt.Equal(deletedAt))
+
+	// The alias stays taken while the link is in the trash.
+	err = s.SaveURL(ctx, domain.Link{Alias: "gone", URL: "https://example.com/new", NormalizedURL: "https://example.com/new", OwnerEmail: "other@example.com", RedirectType: 302})
+	require.ErrorIs(t, err, storage.ErrURLExists)
+
+	require.NoError(t, s.RestoreURL(ctx, "gone"))
+	require.ErrorIs(t, s.RestoreURL(ctx, "gone"), storage.ErrURLNotFound)
+	link, err := s.Link(ctx, "gone")
+	require.NoError(t, err)
+	require.Nil(t, link.DeletedAt)
+	require.Empty(t, link.DeletedBy)
+}
+
+func TestPurgeURL(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	saveLink(t, s, "quarantined", 0)
+	saveLink(t, s, "released", 0)
+	saveLink(t, s, "active", 0)
+
+	now := time.Now()
+	require.NoError(t, s.DeleteURL(ctx, "quarantined", "owner@example.com", now.Add(-48*time.Hour)))
+	require.NoError(t, s.DeleteURL(ctx, "released", "owner@example.com", now.Add(-time.Hour)))
+
+	due, err := s.DeletedURLsBefore(ctx, now.Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	require.Equal(t, "quarantined", due[0].Alias)
+
+	require.ErrorIs(t, s.PurgeURL(ctx, "active", now.Add(time.Hour)), storage.ErrURLNotFound)
+	require.NoError(t, s.PurgeURL(ctx, "quarantined", now.Add(time.Hour)))
+	require.NoError(t, s.PurgeURL(ctx, "released", now.Add(-time.Minute)))
+
+	_, err = s.DeletedLink(ctx, "quarantined")
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	// A quarantined alias cannot be reused until it is released.
+	err = s.SaveURL(ctx, domain.Link{Alias: "quarantined", URL: "https://example.com/new", NormalizedURL: "https://example.com/new", OwnerEmail: "other@example.com", RedirectType: 302})
+	require.ErrorIs(t, err, storage.ErrURLExists)
+	saveLink(t, s, "released", 0)
+
+	released, err := s.ReleaseAliases(ctx, now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, released)
+	saveLink(t, s, "quarantined", 0)
+}
+
+func TestSetURLHistory(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	saveLink(t, s, "docs", 0)
+	require.NoError(t, s.SaveHealthCheck(ctx, "docs", 404, time.Now(), ""))
+
+	changedAt := time.Now().Add(-time.Minute)
+	require.NoError(t, s.SetURL(ctx, "docs", "https://example.com/v2", "https://example.com/v2", "owner@example.com", changedAt))
+	require.NoError(t, s.SetURL(ctx, "docs", "https://example.com/v3", "https://example.com/v3", "admin@example.com", time.Now()))
+	// Setting the current destination again is not a change.
+	require.NoError(t, s.SetURL(ctx, "docs", "https://example.com/v3", "https://example.com/v3", "admin@example.com", time.Now()))
+	require.ErrorIs(t, s.SetURL(ctx, "missing", "https://example.com", "https://example.com", "owner@example.com", time.Now()), storage.ErrURLNotFound)
+
+	link, err := s.Link(ctx, "docs")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/v3", link.URL)
+	require.Equal(t, "https://example.com/v3", link.NormalizedURL)
+	require.Nil(t, link.Health.CheckedAt)
+
+	history, err := s.URLHistory(ctx, "docs")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, "https://example.com/v2", history[0].OldURL)
+	require.Equal(t, "https://example.com/v3", history[0].NewURL)
+	require.Equal(t, "admin@example.com", history[0].ChangedBy)
+	require.Equal(t, "https://example.com/docs", history[1].OldURL)
+	require.True(t, history[1].ChangedAt.Equal(changedAt))
+
+	change, err := s.DestinationChange(ctx, "docs", history[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, history[1], change)
+
+	_, err = s.DestinationChange(ctx, "other", history[1].ID)
+	require.ErrorIs(t, err, storage.ErrChangeNotFound)
+}
+
+func TestUpdateLink(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	saveLink(t, s, "docs", 0)
+
+	newURL := "https://example.com/v2"
+	tags := []string{"docs", "guides"}
+	sticky := true
+	err := s.UpdateLink(ctx, "docs", domain.LinkUpdate{
+		URL:            &newURL,
+		UTM:            &domain.UTMTemplate{Source: "newsletter"},
+		Variants:       &[]domain.Variant{{Name: "a", URL: "https://example.com/a", Weight: 1}},
+		StickyVariants: &sticky,
+		Tags:           &tags,
+	}, newURL, "owner@example.com", time.Now())
+	require.NoError(t, err)
+
+	link, err := s.Link(ctx, "docs")
+	require.NoError(t, err)
+	require.Equal(t, newURL, link.URL)
+	require.Equal(t, 1, link.UTMVersion)
+	require.Len(t, link.Variants, 1)
+	require.True(t, link.StickyVariants)
+	require.Equal(t, tags, link.Tags)
+
+	history, err := s.URLHistory(ctx, "docs")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+
+	// A failing change rolls back the changes applied before it.
+	otherURL := "https://example.com/v3"
+	duplicateTags := []string{"dup", "dup"}
+	err = s.UpdateLink(ctx, "docs", domain.LinkUpdate{
+		URL:  &otherURL,
+		UTM:  &domain.UTMTemplate{Source: "ads"},
+		Tags: &duplicateTags,
+	}, otherURL, "owner@example.com", time.Now())
+	require.Error(t, err)
+
+	link, err = s.Link(ctx, "docs")
+	require.NoError(t, err)
+	require.Equal(t, newURL, link.URL)
+	require.Equal(t, 1, link.UTMVersion)
+	require.Equal(t, tags, link.Tags)
+
+	history, err = s.URLHistory(ctx, "docs")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+
+	err = s.UpdateLink(ctx, "missing", domain.LinkUpdate{URL: &otherURL}, otherURL, "owner@example.com", time.Now())
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+}
+
+func TestAuditLog(t *testing.T) {
+	s, path := newStorageAt(t)
+	ctx := context.Background()
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	entries := []audit.Entry{
+		{CreatedAt: base, Action: audit.ActionCreate, Subject: audit.SubjectLink, Alias: "docs", ActorUID: 1, ActorEmail: "owner@example.com", RequestID: "req-1", ClientIP: "203.0.113.5", After: json.RawMessage(`{"alias":"docs"}`)},
+		{CreatedAt: base.Add(time.Hour), Action: audit.ActionUpdate, Subject: audit.SubjectLink, Alias: "docs", ActorUID: 2, ActorEmail: "admin@example.com", AsAdmin: true, Before: json.RawMessage(`{"alias":"docs"}`), After: json.RawMessage(`{"alias":"docs","title":"Docs"}`)},
+		{CreatedAt: base.Add(2 * time.Hour), Action: audit.ActionCreate, Subject: audit.SubjectLink, Alias: "blog", ActorUID: 1, ActorEmail: "owner@example.com"},
+		{CreatedAt: base.Add(3 * time.Hour), Action: audit.ActionPurge, Subject: audit.SubjectLink, Alias: "old", Before: json.RawMessage(`{"alias":"old"}`)},
+		{CreatedAt: base.Add(4 * time.Hour), Action: audit.ActionSetMember, Subject: audit.SubjectWorkspace, SubjectID: 3, ActorUID: 1, ActorEmail: "owner@example.com", After: json.RawMessage(`{"email":"viewer@example.com"}`)},
+	}
+	for _, e := range entries {
+		require.NoError(t, s.AppendAudit(ctx, e))
+	}
+
+	from, to := base.Add(time.Hour), base.Add(3*time.Hour)
+
+	tests := []struct {
+		name    string
+		filter  audit.Filter
+		wantIDs []int64
+	}{
+		{name: "Everything newest first", filter: audit.Filter{Limit: 10}, wantIDs: []int64{5, 4, 3, 2, 1}},
+		{name: "By actor email", filter: audit.Filter{ActorEmail: "owner@example.com", Limit: 10}, wantIDs: []int64{5, 3, 1}},
+		{name: "By actor uid", filter: audit.Filter{ActorUID: 2, Limit: 10}, wantIDs: []int64{2}},
+		{name: "By alias", filter: audit.Filter{Alias: "docs", Limit: 10}, wantIDs: []int64{2, 1}},
+		{name: "By subject", filter: audit.Filter{Subject: audit.SubjectWorkspace, SubjectID: 3, Limit: 10}, wantIDs: []int64{5}},
+		{name: "Other subject", filter: audit.Filter{Subject: audit.SubjectWorkspace, SubjectID: 4, Limit: 10}, wantIDs: []int64{}},
+		{name: "By time range", filter: audit.Filter{From: &from, To: &to, Limit: 10}, wantIDs: []int64{3, 2}},
+		{name: "Next page", filter: audit.Filter{BeforeID: 3, Limit: 1}, wantIDs: []int64{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.AuditLog(ctx, tt.filter)
+			require.NoError(t, err)
+
+			ids := make([]int64, 0, len(got))
+			for _, e := range got {
+				ids = append(ids, e.ID)
+			}
+			require.Equal(t, tt.wantIDs, ids)
+		})
+	}
+
+	got, err := s.AuditLog(ctx, audit.Filter{ActorUID: 2, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.True(t, got[0].AsAdmin)
+	require.True(t, got[0].CreatedAt.Equal(base.Add(time.Hour)))
+	require.JSONEq(t, `{"alias":"docs"}`, string(got[0].Before))
+	require.JSONEq(t, `{"alias":"docs","title":"Docs"}`, string(got[0].After))
+
+	got, err = s.AuditLog(ctx, audit.Filter{Alias: "blog", Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, audit.SubjectLink, got[0].Subject)
+	require.Nil(t, got[0].Before)
+	require.Nil(t, got[0].After)
+
+	got, err = s.AuditLog(ctx, audit.Filter{Subject: audit.SubjectWorkspace, Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), got[0].SubjectID)
+	require.Empty(t, got[0].Alias)
+
+	// The log is append-only even for direct access to the database.
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.Exec("UPDATE audit_log SET actor_email = 'someone@example.com' WHERE id = 1")
+	require.ErrorContains(t, err, "append-only")
+	_, err = db.Exec("DELETE FROM audit_log WHERE id = 1")
+	require.ErrorContains(t, err, "append-only")
+}
+
+func TestTransferURL(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	saveLink(t, s, "docs", 0)
+
+	now := time.Now()
+	transfer := domain.Transfer{
+		Alias:       "docs",
+		FromEmail:   "owner@example.com",
+		ToEmail:     "new@example.com",
+		RequestedBy: "owner@example.com",
+		RequestedAt: now,
+		ExpiresAt:   now.Add(time.Hour),
+	}
+	require.NoError(t, s.SaveTransfer(ctx, transfer))
+
+	got, err := s.Transfer(ctx, "docs")
+	require.NoError(t, err)
+	require.Equal(t, "new@example.com", got.ToEmail)
+	require.True(t, got.Pending)
+
+	incoming, err := s.IncomingTransfers(ctx, "new@example.com", now)
+	require.NoError(t, err)
+	require.Len(t, incoming, 1)
+	incoming, err = s.IncomingTransfers(ctx, "new@example.com", now.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Empty(t, incoming)
+
+	// The link has to still belong to the user the transfer started from.
+	require.ErrorIs(t, s.TransferURL(ctx, "docs", "someone@example.com", "new@example.com", 42), storage.ErrURLNotFound)
+	require.NoError(t, s.TransferURL(ctx, "docs", "owner@example.com", "new@example.com", 42))
+
+	owner, err := s.UrlOwner(ctx, "docs")
+	require.NoError(t, err)
+	require.Equal(t, domain.Owner{UID: 42, Email: "new@example.com"}, owner)
+
+	_, err = s.Transfer(ctx, "docs")
+	require.ErrorIs(t, err, storage.ErrTransferNotFound)
+	require.ErrorIs(t, s.DeleteTransfer(ctx, "docs"), storage.ErrTransferNotFound)
+}
+
+func TestTransferAllURLs(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	saveLink(t, s, "a", 0)
+	saveLink(t, s, "b", 0)
+	require.NoError(t, s.DeleteURL(ctx, "b", "owner@example.com", time.Now()))
+	require.NoError(t, s.SaveURL(ctx, domain.Link{
+		Alias:         "other",
+		URL:           "https://example.com/other",
+		NormalizedURL: "https://example.com/other",
+		OwnerEmail:    "someone@example.com",
+		RedirectType:  302,
+	}))
+	require.NoError(t, s.SaveTransfer(ctx, domain.Transfer{
+		Alias:       "a",
+		FromEmail:   "owner@example.com",
+		ToEmail:     "someone@example.com",
+		RequestedBy: "owner@example.com",
+		RequestedAt: time.Now(),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}))
+
+	aliases, err := s.TransferAllURLs(ctx, "owner@example.com", "new@example.com")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, aliases)
+
+	live, err := s.ListURLs(ctx, domain.Owner{Email: "new@example.com"}, domain.ListFilter{})
+	require.NoError(t, err)
+	require.Len(t, live, 1)
+	deleted, err := s.ListDeletedURLs(ctx, domain.Owner{Email: "new@example.com"})
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+
+	owner, err := s.UrlOwner(ctx, "other")
+	require.NoError(t, err)
+	require.Equal(t, "someone@example.com", owner.Email)
+
+	_, err = s.Transfer(ctx, "a")
+	require.ErrorIs(t, err, storage.ErrTransferNotFound)
+
+	aliases, err = s.TransferAllURLs(ctx, "owner@example.com", "new@example.com")
+	require.NoError(t, err)
+	require.Empty(t, aliases)
+}
+
+func TestOwnerUID(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	saveLink(t, s, "legacy", 0)
+	saveLink(t, s, "legacy-deleted", 0)
+	require.NoError(t, s.DeleteURL(ctx, "legacy-deleted", "owner@example.com", time.Now()))
+	require.NoError(t, s.SaveURL(ctx, domain.Link{
+		Alias:         "renamed",
+		URL:           "https://example.com/renamed",
+		NormalizedURL: "https://example.com/renamed",
+		OwnerEmail:    "old@example.com",
+		OwnerUID:      7,
+		RedirectType:  302,
+	}))
+
+	link, err := s.Link(ctx, "renamed")
+	require.NoError(t, err)
+	require.Equal(t, int64(7), link.OwnerUID)
+
+	// The owner's email changed to owner@example.com: links recorded under
+	// the ID and legacy links under the new email both belong to them.
+	owner := domain.Owner{UID: 7, Email: "owner@example.com"}
+	live, err := s.ListURLs(ctx, owner, domain.ListFilter{})
+	require.NoError(t, err)
+	require.Len(t, live, 2)
+	alias, err := s.AliasByNormalizedURL(ctx, owner, "https://example.com/renamed", 302)
+	require.NoError(t, err)
+	require.Equal(t, "renamed", alias)
+
+	missing, err := s.OwnersWithoutUID(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{"owner@example.com": 2}, missing)
+
+	backfilled, err := s.BackfillOwnerUID(ctx, "owner@example.com", 7)
+	require.NoError(t, err)
+	require.Equal(t, 2, backfilled)
+
+	got, err := s.UrlOwner(ctx, "legacy")
+	require.NoError(t, err)
+	require.Equal(t, owner, got)
+
+	deleted, err := s.ListDeletedURLs(ctx, domain.Owner{UID: 7, Email: "new@example.com"})
+	require.NoError(t, err)
+	require.Len(t, deleted, 1)
+
+	missing, err = s.OwnersWithoutUID(ctx)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+}
+
+func TestNormalizeURLs(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	owner := domain.Owner{Email: "owner@example.com"}
+	canonicalizer := domain.NewCanonicalizer(domain.CanonicalizeOptions{TrackingParams: []string{"utm_*"}})
+
+	// Links from before the normalized_url migration have their raw URL there.
+	for alias, rawURL := range map[string]string{
+		"legacy":    "HTTPS://Example.com?utm_source=mail",
+		"canonical": "https://example.com/canonical",
+		"broken":    "https://exa mple.com",
+	} {
+		require.NoError(t, s.SaveURL(ctx, domain.Link{
+			Alias:         alias,
+			URL:           rawURL,
+			NormalizedURL: rawURL,
+			OwnerEmail:    owner.Email,
+			RedirectType:  302,
+		}))
+	}
+
+	count, err := s.NormalizeURLs(ctx, canonicalizer.Canonicalize)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	alias, err := s.AliasByNormalizedURL(ctx, owner, "https://example.com/", 302)
+	require.NoError(t, err)
+	require.Equal(t, "legacy", alias)
+	alias, err = s.AliasByNormalizedURL(ctx, owner, "https://example.com/canonical", 302)
+	require.NoError(t, err)
+	require.Equal(t, "canonical", alias)
+
+	count, err = s.NormalizeURLs(ctx, canonicalizer.Canonicalize)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func TestAliasByNormalizedURL(t *testing.T) {
+	const destination = "https://example.com/docs"
+	activation := time.Date(2025, time.May, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name  string
+		link  domain.Link
+		found bool
+	}{
+		{name: "Plain link", link: domain.Link{RedirectType: 302}, found: true},
+		{name: "Other redirect type", link: domain.Link{RedirectType: 301}},
+		{name: "Password protected", link: domain.Link{RedirectType: 302, PasswordHash: "hash"}},
+		{name: "Signed only", link: domain.Link{RedirectType: 302, SignedOnly: true}},
+		{name: "Warn", link: domain.Link{RedirectType: 302, Warn: true}},
+		{name: "Passthrough", link: domain.Link{RedirectType: 302, Passthrough: domain.PassthroughAppend}},
+		{name: "UTM template", link: domain.Link{RedirectType: 302, UTM: &domain.UTMTemplate{Source: "ads"}, UTMVersion: 1}},
+		{name: "Targeting", link: domain.Link{RedirectType: 302, Targeting: []domain.TargetingRule{{Platform: "ios", URL: destination}}}},
+		{name: "Split variants", link: domain.Link{RedirectType: 302, Variants: []domain.Variant{{Name: "a", URL: destination, Weight: 1}}}},
+		{name: "Click limit", link: domain.Link{RedirectType: 302, MaxClicks: 1}},
+		{name: "Not before", link: domain.Link{RedirectType: 302, NotBefore: &activation}},
+		{name: "Not after", link: domain.Link{RedirectType: 302, NotAfter: &activation}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newStorage(t)
+			ctx := context.Background()
+			owner := domain.Owner{Email: "owner@example.com"}
+
+			link := tc.link
+			link.Alias = "docs"
+			link.URL = destination
+			link.NormalizedURL = destination
+			link.OwnerEmail = owner.Email
+			require.NoError(t, s.SaveURL(ctx, link))
+
+			alias, err := s.AliasByNormalizedURL(ctx, owner, destination, 302)
+			if !tc.found {
+				require.ErrorIs(t, err, storage.ErrURLNotFound)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "docs", alias)
+		})
+	}
+}
+
+func TestWorkspaces(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	createdAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	ws, err := s.CreateWorkspace(ctx, workspace.Workspace{Name: "Marketing", CreatedBy: "owner@example.com", CreatedAt: createdAt}, 1)
+	require.NoError(t, err)
+	require.NotZero(t, ws.ID)
+	require.Equal(t, workspace.RoleAdmin, ws.Role)
+
+	admin, err := s.WorkspaceMember(ctx, ws.ID, "owner@example.com")
+	require.NoError(t, err)
+	require.Equal(t, workspace.RoleAdmin, admin.Role)
+	require.Equal(t, int64(1), admin.UID)
+
+	member := workspace.Member{WorkspaceID: ws.ID, Email: "viewer@example.com", Role: workspace.RoleViewer, AddedBy: "owner@example.com", AddedAt: createdAt.Add(time.Hour)}
+	require.NoError(t, s.SaveWorkspaceMember(ctx, member))
+	member.Role = workspace.RoleEditor
+	member.AddedBy = "someone@example.com"
+	require.NoError(t, s.SaveWorkspaceMember(ctx, member))
+
+	got, err := s.WorkspaceMember(ctx, ws.ID, "viewer@example.com")
+	require.NoError(t, err)
+	require.Equal(t, workspace.RoleEditor, got.Role)
+	require.Equal(t, "owner@example.com", got.AddedBy)
+
+	members, err := s.WorkspaceMembers(ctx, ws.ID)
+	require.NoError(t, err)
+	require.Len(t, members, 2)
+
+	workspaces, err := s.Workspaces(ctx, "viewer@example.com", 0)
+	require.NoError(t, err)
+	require.Len(t, workspaces, 1)
+	require.Equal(t, "Marketing", workspaces[0].Name)
+	require.Equal(t, workspace.RoleEditor, workspaces[0].Role)
+
+	err = s.SaveWorkspaceMember(ctx, workspace.Member{WorkspaceID: ws.ID + 1, Email: "viewer@example.com", Role: workspace.RoleViewer, AddedAt: createdAt})
+	require.ErrorIs(t, err, storage.ErrWorkspaceNotFound)
+
+	require.NoError(t, s.DeleteWorkspaceMember(ctx, ws.ID, "viewer@example.com"))
+	_, err = s.WorkspaceMember(ctx, ws.ID, "viewer@example.com")
+	require.ErrorIs(t, err, storage.ErrMemberNotFound)
+	require.ErrorIs(t, s.DeleteWorkspaceMember(ctx, ws.ID, "viewer@example.com"), storage.ErrMemberNotFound)
+
+	_, err = s.Workspace(ctx, ws.ID+1)
+	require.ErrorIs(t, err, storage.ErrWorkspaceNotFound)
+}
+
+func TestWorkspaceKeepsAdmin(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	createdAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	ws, err := s.CreateWorkspace(ctx, workspace.Workspace{Name: "Marketing", CreatedBy: "owner@example.com", CreatedAt: createdAt}, 1)
+	require.NoError(t, err)
+
+	owner := workspace.Member{WorkspaceID: ws.ID, Email: "owner@example.com", Role: workspace.RoleEditor, AddedAt: createdAt}
+	require.ErrorIs(t, s.SaveWorkspaceMember(ctx, owner), storage.ErrLastAdmin)
+	require.ErrorIs(t, s.DeleteWorkspaceMember(ctx, ws.ID, "owner@example.com"), storage.ErrLastAdmin)
+
+	got, err := s.WorkspaceMember(ctx, ws.ID, "owner@example.com")
+	require.NoError(t, err)
+	require.Equal(t, workspace.RoleAdmin, got.Role)
+}
+
+func TestWorkspaceKeepsAdminConcurrent(t *testing.T) {
+	const admins = 10
+
+	s := newStorage(t)
+	ctx := context.Background()
+	createdAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	ws, err := s.CreateWorkspace(ctx, workspace.Workspace{Name: "Marketing", CreatedBy: "admin0@example.com", CreatedAt: createdAt}, 1)
+	require.NoError(t, err)
+	for i := 1; i < admins; i++ {
+		require.NoError(t, s.SaveWorkspaceMember(ctx, workspace.Member{WorkspaceID: ws.ID, Email: fmt.Sprintf("admin%d@example.com", i), Role: workspace.RoleAdmin, AddedAt: createdAt}))
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		left int
+	)
+	start := make(chan struct{})
+	for i := range admins {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			// Half of the admins step down to editor, the others leave.
+			var err error
+			email := fmt.Sprintf("admin%d@example.com", i)
+			if i%2 == 0 {
+				err = s.SaveWorkspaceMember(ctx, workspace.Member{WorkspaceID: ws.ID, Email: email, Role: workspace.RoleEditor, AddedAt: createdAt})
+			} else {
+				err = s.DeleteWorkspaceMember(ctx, ws.ID, email)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				left++
+			case errors.Is(err, storage.ErrLastAdmin):
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	require.Equal(t, admins-1, left)
+
+	members, err := s.WorkspaceMembers(ctx, ws.ID)
+	require.NoError(t, err)
+	remaining := 0
+	for _, m := range members {
+		if m.Role == workspace.RoleAdmin {
+			remaining++
+		}
+	}
+	require.Equal(t, 1, remaining)
+}
+
+func TestWorkspaceMemberByUser(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	createdAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	ws, err := s.CreateWorkspace(ctx, workspace.Workspace{Name: "Marketing", CreatedBy: "owner@example.com", CreatedAt: createdAt}, 1)
+	require.NoError(t, err)
+	require.NoError(t, s.SaveWorkspaceMember(ctx, workspace.Member{WorkspaceID: ws.ID, Email: "viewer@example.com", Role: workspace.RoleViewer, AddedBy: "owner@example.com", AddedAt: createdAt}))
+
+	// Without a recorded user ID the invited email matches.
+	got, err := s.WorkspaceMemberByUser(ctx, ws.ID, "viewer@example.com", 2)
+	require.NoError(t, err)
+	require.Zero(t, got.UID)
+
+	claimed, err := s.BackfillMemberUID(ctx, "viewer@example.com", 2)
+	require.NoError(t, err)
+	require.Equal(t, 1, claimed)
+
+	// Once claimed, the user ID matches under a new email and the old email
+	// no longer matches anyone else.
+	got, err = s.WorkspaceMemberByUser(ctx, ws.ID, "renamed@example.com", 2)
+	require.NoError(t, err)
+	require.Equal(t, "viewer@example.com", got.Email)
+	require.Equal(t, workspace.RoleViewer, got.Role)
+
+	_, err = s.WorkspaceMemberByUser(ctx, ws.ID, "viewer@example.com", 3)
+	require.ErrorIs(t, err, storage.ErrMemberNotFound)
+
+	workspaces, err := s.Workspaces(ctx, "renamed@example.com", 2)
+	require.NoError(t, err)
+	require.Len(t, workspaces, 1)
+
+	workspaces, err = s.Workspaces(ctx, "viewer@example.com", 3)
+	require.NoError(t, err)
+	require.Empty(t, workspaces)
+
+	// A membership under the user ID wins over one invited by the new email.
+	require.NoError(t, s.SaveWorkspaceMember(ctx, workspace.Member{WorkspaceID: ws.ID, Email: "renamed@example.com", Role: workspace.RoleEditor, AddedBy: "owner@example.com", AddedAt: createdAt}))
+
+	got, err = s.WorkspaceMemberByUser(ctx, ws.ID, "renamed@example.com", 2)
+	require.NoError(t, err)
+	require.Equal(t, workspace.RoleViewer, got.Role)
+
+	workspaces, err = s.Workspaces(ctx, "renamed@example.com", 2)
+	require.NoError(t, err)
+	require.Len(t, workspaces, 1)
+	require.Equal(t, workspace.RoleViewer, workspaces[0].Role)
+}
+
+func TestWorkspaceLinks(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	saveLink(t, s, "personal", 0)
+	require.NoError(t, s.SaveURL(ctx, domain.Link{
+		Alias:         "team",
+		URL:           "https://example.com/team",
+		NormalizedURL: "https://example.com/team",
+		OwnerEmail:    "owner@example.com",
+		WorkspaceID:   7,
+		RedirectType:  302,
+	}))
+
+	owner, err := s.UrlOwner(ctx, "team")
+	require.NoError(t, err)
+	require.Equal(t, int64(7), owner.WorkspaceID)
+
+	personal, err := s.ListURLs(ctx, domain.Owner{Email: "owner@example.com"}, domain.ListFilter{})
+	require.NoError(t, err)
+	require.Len(t, personal, 1)
+	require.Equal(t, "personal", personal[0].Alias)
+
+	team, err := s.ListURLs(ctx, domain.Owner{WorkspaceID: 7}, domain.ListFilter{})
+	require.NoError(t, err)
+	require.Len(t, team, 1)
+	require.Equal(t, "team", team[0].Alias)
+	require.Equal(t, int64(7), team[0].WorkspaceID)
+
+	_, err = s.AliasByNormalizedURL(ctx, domain.Owner{Email: "owner@example.com"}, "https://example.com/team", 302)
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+	alias, err := s.AliasByNormalizedURL(ctx, domain.Owner{WorkspaceID: 7}, "https://example.com/team", 302)
+	require.NoError(t, err)
+	require.Equal(t, "team", alias)
+
+	err = s.TransferURL(ctx, "team", "owner@example.com", "new@example.com", 0)
+	require.ErrorIs(t, err, storage.ErrURLNotFound)
+
+	aliases, err := s.TransferAllURLs(ctx, "owner@example.com", "new@example.com")
+	require.NoError(t, err)
+	require.Equal(t, []string{"personal"}, aliases)
+}
+
+func TestTags(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	require.NoError(t, s.SaveURL(ctx, domain.Link{
+		Alias:         "tagged",
+		URL:           "https://example.com/tagged",
+		NormalizedURL: "https://example.com/tagged",
+		OwnerEmail:    "owner@example.com",
+		RedirectType:  302,
+		Tags:          []string{"launch", "promo"},
+	}))
+	saveLink(t, s, "plain", 0)
+
+	link, err := s.Link(ctx, "tagged")
+	require.NoError(t, err)
+	require.Equal(t, []string{"launch", "promo"}, link.Tags)
+
+	owner := domain.Owner{Email: "owner@example.com"}
+	links, err := s.ListURLs(ctx, owner, domain.ListFilter{Tags: []string{"launch", "promo"}})
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	require.Equal(t, "tagged", links[0].Alias)
+
+	links, err = s.ListURLs(ctx, owner, domain.ListFilter{Tags: []string{"launch", "other"}})
+	require.NoError(t, err)
+	require.Empty(t, links)
+
+	require.NoError(t, updateLink(s, "plain", domain.LinkUpdate{Tags: &[]string{"launch"}}))
+	require.NoError(t, updateLink(s, "tagged", domain.LinkUpdate{Tags: &[]string{}}))
+	require.ErrorIs(t, updateLink(s, "missing", domain.LinkUpdate{Tags: &[]string{"launch"}}), storage.ErrURLNotFound)
+
+	links, err = s.ListURLs(ctx, owner, domain.ListFilter{Tags: []string{"launch"}})
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	require.Equal(t, "plain", links[0].Alias)
+	require.Equal(t, []string{"launch"}, links[0].Tags)
+
+	require.NoError(t, s.DeleteURL(ctx, "plain", "owner@example.com", time.Now()))
+	require.NoError(t, s.PurgeURL(ctx, "plain", time.Now()))
+	saveLink(t, s, "plain", 0)
+	link, err = s.Link(ctx, "plain")
+	require.NoError(t, err)
+	require.Empty(t, link.Tags)
+}
+
+func TestFolders(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	createdAt := time.Date(2026, 6, 1, 9, 0, 0, 0, time.UTC)
+
+	personal, err := s.CreateFolder(ctx, domain.Folder{Name: "Campaigns", OwnerEmail: "owner@example.com", OwnerUID: 42, CreatedAt: createdAt})
+	require.NoError(t, err)
+	require.NotZero(t, personal.ID)
+	team, err := s.CreateFolder(ctx, domain.Folder{Name: "Team", OwnerEmail: "owner@example.com", WorkspaceID: 7, CreatedAt: createdAt})
+	require.NoError(t, err)
+
+	got, err := s.Folder(ctx, personal.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Campaigns", got.Name)
+	require.Equal(t, int64(42), got.OwnerUID)
+
+	folders, err := s.Folders(ctx, domain.Owner{UID: 42, Email: "owner@example.com"})
+	require.NoError(t, err)
+	require.Len(t, folders, 1)
+	require.Equal(t, personal.ID, folders[0].ID)
+
+	folders, err = s.Folders(ctx, domain.Owner{WorkspaceID: 7})
+	require.NoError(t, err)
+	require.Len(t, folders, 1)
+	require.Equal(t, team.ID, folders[0].ID)
+
+	saveLink(t, s, "filed", 0)
+	saveLink(t, s, "loose", 0)
+	require.NoError(t, updateLink(s, "filed", domain.LinkUpdate{FolderID: &personal.ID}))
+	require.ErrorIs(t, updateLink(s, "missing", domain.LinkUpdate{FolderID: &personal.ID}), storage.ErrURLNotFound)
+
+	links, err := s.ListURLs(ctx, domain.Owner{Email: "owner@example.com"}, domain.ListFilter{FolderID: personal.ID})
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	require.Equal(t, "filed", links[0].Alias)
+	require.Equal(t, personal.ID, links[0].FolderID)
+
+	require.NoError(t, s.RenameFolder(ctx, personal.ID, "Launches"))
+	got, err = s.Folder(ctx, personal.ID)
+	require.NoError(t, err)
+	require.Equal(t, "Launches", got.Name)
+
+	require.NoError(t, s.DeleteFolder(ctx, personal.ID))
+	_, err = s.Folder(ctx, personal.ID)
+	require.ErrorIs(t, err, storage.ErrFolderNotFound)
+	require.ErrorIs(t, s.DeleteFolder(ctx, personal.ID), storage.ErrFolderNotFound)
+	require.ErrorIs(t, s.RenameFolder(ctx, personal.ID, "Gone"), storage.ErrFolderNotFound)
+
+	link, err := s.Link(ctx, "filed")
+	require.NoError(t, err)
+	require.Zero(t, link.FolderID)
+
+	archive, err := s.CreateFolder(ctx, domain.Folder{Name: "Archive", OwnerEmail: "owner@example.com", CreatedAt: createdAt})
+	require.NoError(t, err)
+	require.NoError(t, updateLink(s, "loose", domain.LinkUpdate{FolderID: &archive.ID}))
+	require.NoError(t, s.TransferURL(ctx, "loose", "owner@example.com", "new@example.com", 0))
+	link, err = s.Link(ctx, "loose")
+	require.NoError(t, err)
+	require.Zero(t, link.FolderID)
+}
+
+func TestTagStats(t *testing.T) {
+	s := newStorage(t)
+	ctx := context.Background()
+	for _, link := range []domain.Link{
+		{Alias: "a", Tags: []string{"launch", "promo"}},
+		{Alias: "b", Tags: []string{"launch"}},
+		{Alias: "c", Tags: []string{"launch"}},
+		{Alias: "team", Tags: []string{"launch"}, WorkspaceID: 7},
+	} {
+		link.URL = "https://example.com/" + link.Alias
+		link.NormalizedURL = link.URL
+		link.OwnerEmail = "owner@example.com"
+		link.RedirectType = 302
+		require.NoError(t, s.SaveURL(ctx, link))
+	}
+
+	for _, alias := range []string{"a", "a", "b", "c", "team"} {
+		require.NoError(t, s.RecordClick(ctx, domain.Click{Alias: alias, ClickedAt: time.Now()}))
+	}
+	require.NoError(t, s.DeleteURL(ctx, "c", "owner@example.com", time.Now()))
+
+	stats, err := s.TagStats(ctx, domain.Owner{Email: "owner@example.com"})
+	require.NoError(t, err)
+	require.Equal(t, []domain.TagStats{
+		{Tag: "launch", Links: 2, Clicks: 3},
+		{Tag: "promo", Links: 1, Clicks: 2},
+	}, stats)
+
+	stats, err = s.TagStats(ctx, domain.Owner{WorkspaceID: 7})
+	require.NoError(t, err)
+	require.Equal(t, []domain.TagStats{{Tag: "launch", Links: 1, Clicks: 1}}, stats)
+}
+
+func TestSearchURLs(t *testing.T) {
+	s, path := newStorageAt(t)
+	ctx := context.Background()
+	for _, link := range []domain.Link{
+		{Alias: "launch", URL: "https://example.com/home", Title: "Home page"},
+		{Alias: "blog", URL: "https://example.com/launch-notes", Title: "Release notes"},
+		{Alias: "promo", URL: "https://shop.example.org/sale", Title: "Spring launch sale", Tags: []string{"q3"}},
+		{Alias: "tagged", URL: "https://example.net/x", Tags: []string{"launch", "spring"}},
+		{Alias: "trashed", URL: "https://example.com/launch-old"},
+		{Alias: "team", URL: "https://example.com/launch-team", WorkspaceID: 7},
+		{Alias: "print", URL: "https://example.org/p", Notes: "Handed out with the brochure"},
+		{Alias: "dessert", URL: "https://example.org/d", Title: "Crème brûlée"},
+	} {
+		link.NormalizedURL = link.URL
+		link.OwnerEmail = "owner@example.com"
+		link.RedirectType = 302
+		require.NoError(t, s.SaveURL(ctx, link))
+	}
+	require.NoError(t, s.DeleteURL(ctx, "trashed", "owner@example.com", time.Now()))
+
+	aliases := func(links []domain.Link) []string {
+		var got []string
+		for _, link := range links {
+			got = append(got, link.Alias)
+		}
+		return got
+	}
+
+	owner := domain.Owner{Email: "owner@example.com"}
+	links, err := s.SearchURLs(ctx, owner, []string{"launch"}, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"launch", "promo", "tagged", "blog"}, aliases(links))
+
+	links, err = s.SearchURLs(ctx, owner, []string{"launch"}, 2, 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"tagged", "blog"}, aliases(links))
+
+	links, err = s.SearchURLs(ctx, owner, []string{"spring", "laun"}, 10, 0)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"promo", "tagged"}, aliases(links))
+
+	// Terms match the start of words only, in every build.
+	links, err = s.SearchURLs(ctx, owner, []string{"aunch"}, 10, 0)
+	require.NoError(t, err)
+	require.Empty(t, links)
+
+	links, err = s.SearchURLs(ctx, owner, []string{"sal"}, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"promo"}, aliases(links))
+
+	links, err = s.SearchURLs(ctx, owner, []string{"notes"}, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"blog"}, aliases(links))
+
+	links, err = s.SearchURLs(ctx, owner, []string{"hop"}, 10, 0)
+	require.NoError(t, err)
+	require.Empty(t, links)
+
+	links, err = s.SearchURLs(ctx, owner, []string{"brû"}, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"dessert"}, aliases(links))
+
+	links, err = s.SearchURLs(ctx, owner, []string{"lée"}, 10, 0)
+	require.NoError(t, err)
+	require.Empty(t, links)
+
+	links, err = s.SearchURLs(ctx, domain.Owner{WorkspaceID: 7}, []string{"launch"}, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"team"}, aliases(links))
+
+	require.NoError(t, updateLink(s, "tagged", domain.LinkUpdate{Tags: &[]string{"autumn"}}))
+	require.NoError(t, s.SetURL(ctx, "launch", "https://example.com/autumn", "https://example.com/autumn", "owner@example.com", time.Now()))
+	links, err = s.SearchURLs(ctx, owner, []string{"autumn"}, 10, 0)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"launch", "tagged"}, aliases(links))
+
+	links, err = s.SearchURLs(ctx, owner, []string{"brochure"}, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"print"}, aliases(links))
+
+	notes, cleared := "Brochure draft", ""
+	require.NoError(t, s.UpdateLink(ctx, "blog", domain.LinkUpdate{Notes: &notes}, "", "owner@example.com", time.Now()))
+	require.NoError(t, s.UpdateLink(ctx, "print", domain.LinkUpdate{Notes: &cleared}, "", "owner@example.com", time.Now()))
+	links, err = s.SearchURLs(ctx, owner, []string{"brochure"}, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"blog"}, aliases(links))
+	require.Equal(t, notes, links[0].Notes)
+
+	require.NoError(t, s.Close())
+	reopened, err := sqlite.New(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = reopened.Close() })
+
+	links, err = reopened.SearchURLs(ctx, owner, []string{"q3"}, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"promo"}, aliases(links))
+}
@@ -3,18 +3,81 @@ package storage
 import (
 	"context"
 	"errors"
+	"time"
+	"url-shortener/internal/domain/audit"
+	domain "url-shortener/internal/domain/url"
+	"url-shortener/internal/domain/workspace"
 )
 
 var (
 	ErrURLNotFound = errors.New("URL not found")
 	ErrURLExists   = errors.New("URL already exists")
+	// ErrURLExhausted is returned when a link has reached its click limit.
+	ErrURLExhausted = errors.New("URL click limit reached")
+	// ErrChangeNotFound is returned when a destination change does not exist.
+	ErrChangeNotFound = errors.New("destination change not found")
+	// ErrTransferNotFound is returned when a link has no pending transfer.
+	ErrTransferNotFound = errors.New("transfer not found")
+	// ErrWorkspaceNotFound is returned when a workspace does not exist.
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	// ErrMemberNotFound is returned when a user is not a member of a workspace.
+	ErrMemberNotFound = errors.New("workspace member not found")
+	// ErrLastAdmin is returned when a change would leave a workspace without
+	// an admin.
+	ErrLastAdmin = errors.New("workspace must keep an admin")
+	// ErrFolderNotFound is returned when a folder does not exist.
+	ErrFolderNotFound = errors.New("folder not found")
 )
 
 // Storage defines the interface for URL storage operations.
 type Storage interface {
-	SaveURL(ctx context.Context, alias, originalURL, ownerEmail string) error
-	Url(ctx context.Context, alias string) (string, error)
-	UrlOwner(ctx context.Context, alias string) (string, error)
-	DeleteURL(ctx context.Context, alias string) error
+	SaveURL(ctx context.Context, link domain.Link) error
+	AliasByNormalizedURL(ctx context.Context, owner domain.Owner, normalizedURL string, redirectType int) (string, error)
+	UrlOwner(ctx context.Context, alias string) (domain.Owner, error)
+	BackfillOwnerUID(ctx context.Context, ownerEmail string, uid int64) (int, error)
+	DeleteURL(ctx context.Context, alias, deletedBy string, deletedAt time.Time) error
+	RestoreURL(ctx context.Context, alias string) error
+	PurgeURL(ctx context.Context, alias string, releasedAt time.Time) error
+	ReleaseAliases(ctx context.Context, now time.Time) (int, error)
+	Link(ctx context.Context, alias string) (domain.Link, error)
+	DeletedLink(ctx context.Context, alias string) (domain.Link, error)
+	ListURLs(ctx context.Context, owner domain.Owner, filter domain.ListFilter) ([]domain.Link, error)
+	SearchURLs(ctx context.Context, owner domain.Owner, terms []string, limit, offset int) ([]domain.Link, error)
+	ListDeletedURLs(ctx context.Context, owner domain.Owner) ([]domain.Link, error)
+	DeletedURLsBefore(ctx context.Context, deletedBefore time.Time, limit int) ([]domain.Link, error)
+	URLsToCheck(ctx context.Context, checkedBefore time.Time, limit int) ([]domain.Link, error)
+	SaveHealthCheck(ctx context.Context, alias string, statusCode int, checkedAt time.Time, checkErr string) error
+	CountBrokenURLs(ctx context.Context) (int, error)
+	RecordClick(ctx context.Context, click domain.Click) error
+	ClicksByUTMVersion(ctx context.Context, alias string) ([]domain.UTMVersion, error)
+	ClicksByVariant(ctx context.Context, alias string) (map[string]int, error)
+	ClaimClick(ctx context.Context, alias string) error
+	SetURL(ctx context.Context, alias, rawURL, normalizedURL, changedBy string, changedAt time.Time) error
+	UpdateLink(ctx context.Context, alias string, upd domain.LinkUpdate, normalizedURL, changedBy string, changedAt time.Time) error
+	URLHistory(ctx context.Context, alias string) ([]domain.DestinationChange, error)
+	DestinationChange(ctx context.Context, alias string, id int64) (domain.DestinationChange, error)
+	SaveTransfer(ctx context.Context, transfer domain.Transfer) error
+	Transfer(ctx context.Context, alias string) (domain.Transfer, error)
+	IncomingTransfers(ctx context.Context, toEmail string, now time.Time) ([]domain.Transfer, error)
+	DeleteTransfer(ctx context.Context, alias string) error
+	TransferURL(ctx context.Context, alias, fromEmail, toEmail string, toUID int64) error
+	TransferAllURLs(ctx context.Context, fromEmail, toEmail string) ([]string, error)
+	AppendAudit(ctx context.Context, entry audit.Entry) error
+	AuditLog(ctx context.Context, filter audit.Filter) ([]audit.Entry, error)
+	CreateWorkspace(ctx context.Context, ws workspace.Workspace, creatorUID int64) (workspace.Workspace, error)
+	Workspace(ctx context.Context, id int64) (workspace.Workspace, error)
+	Workspaces(ctx context.Context, memberEmail string, memberUID int64) ([]workspace.Workspace, error)
+	WorkspaceMember(ctx context.Context, workspaceID int64, email string) (workspace.Member, error)
+	WorkspaceMemberByUser(ctx context.Context, workspaceID int64, email string, uid int64) (workspace.Member, error)
+	BackfillMemberUID(ctx context.Context, email string, uid int64) (int, error)
+	WorkspaceMembers(ctx context.Context, workspaceID int64) ([]workspace.Member, error)
+	SaveWorkspaceMember(ctx context.Context, member workspace.Member) error
+	DeleteWorkspaceMember(ctx context.Context, workspaceID int64, email string) error
+	CreateFolder(ctx context.Context, folder domain.Folder) (domain.Folder, error)
+	Folder(ctx context.Context, id int64) (domain.Folder, error)
+	Folders(ctx context.Context, owner domain.Owner) ([]domain.Folder, error)
+	RenameFolder(ctx context.Context, id int64, name string) error
+	DeleteFolder(ctx context.Context, id int64) error
+	TagStats(ctx context.Context, owner domain.Owner) ([]domain.TagStats, error)
 	Close() error
 }
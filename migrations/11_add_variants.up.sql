@@ -0,0 +1,10 @@
+ALTER TABLE urls ADD COLUMN sticky_variants INTEGER NOT NULL DEFAULT 0;
+CREATE TABLE IF NOT EXISTS variants(
+alias TEXT NOT NULL,
+position INTEGER NOT NULL,
+name TEXT NOT NULL,
+url TEXT NOT NULL,
+weight INTEGER NOT NULL,
+PRIMARY KEY (alias, name));
+ALTER TABLE clicks ADD COLUMN variant TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_clicks_alias_variant ON clicks(alias, variant);
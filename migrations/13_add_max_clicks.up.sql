@@ -0,0 +1,2 @@
+ALTER TABLE urls ADD COLUMN max_clicks INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE urls ADD COLUMN clicks_used INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE urls ADD COLUMN not_before TIMESTAMP;
+ALTER TABLE urls ADD COLUMN not_after TIMESTAMP;
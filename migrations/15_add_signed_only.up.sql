@@ -0,0 +1 @@
+ALTER TABLE urls ADD COLUMN signed_only INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,6 @@
+ALTER TABLE urls ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE urls ADD COLUMN deleted_by TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_urls_deleted_at ON urls(deleted_at);
+CREATE TABLE IF NOT EXISTS alias_quarantine(
+alias TEXT PRIMARY KEY,
+released_at TIMESTAMP NOT NULL);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS url_history(
+id INTEGER PRIMARY KEY,
+alias TEXT NOT NULL,
+old_url TEXT NOT NULL,
+new_url TEXT NOT NULL,
+changed_by TEXT NOT NULL,
+changed_at TIMESTAMP NOT NULL);
+CREATE INDEX IF NOT EXISTS idx_url_history_alias ON url_history(alias, id);
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS audit_log(
+id INTEGER PRIMARY KEY,
+created_at TIMESTAMP NOT NULL,
+action TEXT NOT NULL,
+alias TEXT NOT NULL,
+actor_uid INTEGER NOT NULL DEFAULT 0,
+actor_email TEXT NOT NULL DEFAULT '',
+as_admin BOOLEAN NOT NULL DEFAULT FALSE,
+request_id TEXT NOT NULL DEFAULT '',
+client_ip TEXT NOT NULL DEFAULT '',
+before TEXT,
+after TEXT);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log(created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_alias ON audit_log(alias, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor_email ON audit_log(actor_email, created_at);
+CREATE TRIGGER IF NOT EXISTS audit_log_no_update BEFORE UPDATE ON audit_log
+BEGIN
+SELECT RAISE(ABORT, 'audit log is append-only');
+END;
+CREATE TRIGGER IF NOT EXISTS audit_log_no_delete BEFORE DELETE ON audit_log
+BEGIN
+SELECT RAISE(ABORT, 'audit log is append-only');
+END;
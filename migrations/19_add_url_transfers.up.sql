@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS url_transfers(
+alias TEXT PRIMARY KEY,
+from_email TEXT NOT NULL,
+to_email TEXT NOT NULL,
+requested_by TEXT NOT NULL,
+requested_at TIMESTAMP NOT NULL,
+expires_at TIMESTAMP NOT NULL);
+CREATE INDEX IF NOT EXISTS idx_url_transfers_to_email ON url_transfers(to_email);
@@ -0,0 +1,11 @@
+ALTER TABLE urls ADD COLUMN owner_uid INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_urls_owner_uid ON urls(owner_uid);
+-- Backfill from the audit log, which recorded the user ID next to the email
+-- of everyone who changed a link. Links of owners who never did are left at
+-- zero and matched by email until the owner next manages one of them.
+UPDATE urls SET owner_uid = (
+SELECT a.actor_uid FROM audit_log a
+WHERE a.actor_email = urls.owner_email AND a.actor_uid != 0
+ORDER BY a.id DESC LIMIT 1)
+WHERE owner_uid = 0 AND EXISTS(
+SELECT 1 FROM audit_log a WHERE a.actor_email = urls.owner_email AND a.actor_uid != 0);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS workspaces(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+name TEXT NOT NULL,
+created_by TEXT NOT NULL,
+created_at TIMESTAMP NOT NULL);
+CREATE TABLE IF NOT EXISTS workspace_members(
+workspace_id INTEGER NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+email TEXT NOT NULL,
+role TEXT NOT NULL,
+added_by TEXT NOT NULL,
+added_at TIMESTAMP NOT NULL,
+PRIMARY KEY(workspace_id, email));
+CREATE INDEX IF NOT EXISTS idx_workspace_members_email ON workspace_members(email);
+ALTER TABLE urls ADD COLUMN workspace_id INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_urls_workspace_id ON urls(workspace_id);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS folders(
+id INTEGER PRIMARY KEY AUTOINCREMENT,
+name TEXT NOT NULL,
+owner_email TEXT NOT NULL,
+owner_uid INTEGER NOT NULL DEFAULT 0,
+workspace_id INTEGER NOT NULL DEFAULT 0,
+created_at TIMESTAMP NOT NULL);
+CREATE INDEX IF NOT EXISTS idx_folders_owner_email ON folders(owner_email);
+CREATE INDEX IF NOT EXISTS idx_folders_workspace_id ON folders(workspace_id);
+CREATE TABLE IF NOT EXISTS link_tags(
+alias TEXT NOT NULL,
+tag TEXT NOT NULL,
+PRIMARY KEY (alias, tag));
+CREATE INDEX IF NOT EXISTS idx_link_tags_tag ON link_tags(tag);
+ALTER TABLE urls ADD COLUMN folder_id INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_urls_folder_id ON urls(folder_id);
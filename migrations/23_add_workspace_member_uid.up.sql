@@ -0,0 +1,11 @@
+ALTER TABLE workspace_members ADD COLUMN uid INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_workspace_members_uid ON workspace_members(uid);
+-- Backfill from the audit log like urls.owner_uid. Members who never changed
+-- a link are left at zero and matched by email until they next use the
+-- workspace.
+UPDATE workspace_members SET uid = (
+SELECT a.actor_uid FROM audit_log a
+WHERE a.actor_email = workspace_members.email AND a.actor_uid != 0
+ORDER BY a.id DESC LIMIT 1)
+WHERE uid = 0 AND EXISTS(
+SELECT 1 FROM audit_log a WHERE a.actor_email = workspace_members.email AND a.actor_uid != 0);
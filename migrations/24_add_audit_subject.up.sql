@@ -0,0 +1,5 @@
+-- Entries about workspaces and folders have no alias; they name their subject
+-- by type and ID instead. Earlier entries are all about links.
+ALTER TABLE audit_log ADD COLUMN subject TEXT NOT NULL DEFAULT 'link';
+ALTER TABLE audit_log ADD COLUMN subject_id INTEGER NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_audit_log_subject ON audit_log(subject, subject_id, created_at);
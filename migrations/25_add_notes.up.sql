@@ -0,0 +1 @@
+ALTER TABLE urls ADD COLUMN notes TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+ALTER TABLE urls ADD COLUMN normalized_url TEXT NOT NULL DEFAULT '';
+-- Existing rows get their raw URL here; the migrator replaces it with the
+-- canonical form after migrating up, which SQL alone cannot compute.
+UPDATE urls SET normalized_url = url;
+CREATE INDEX IF NOT EXISTS idx_owner_normalized_url ON urls(owner_email, normalized_url);
@@ -0,0 +1,5 @@
+ALTER TABLE urls ADD COLUMN last_status_code INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE urls ADD COLUMN last_checked_at TIMESTAMP;
+ALTER TABLE urls ADD COLUMN last_check_error TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_last_checked_at ON urls(last_checked_at);
+CREATE INDEX IF NOT EXISTS idx_owner_email ON urls(owner_email);
@@ -0,0 +1 @@
+ALTER TABLE urls ADD COLUMN redirect_type INTEGER NOT NULL DEFAULT 302;
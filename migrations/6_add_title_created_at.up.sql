@@ -0,0 +1,2 @@
+ALTER TABLE urls ADD COLUMN title TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN created_at TIMESTAMP;
@@ -0,0 +1 @@
+ALTER TABLE urls ADD COLUMN warn INTEGER NOT NULL DEFAULT 0;
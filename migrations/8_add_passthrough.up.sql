@@ -0,0 +1 @@
+ALTER TABLE urls ADD COLUMN passthrough TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,22 @@
+ALTER TABLE urls ADD COLUMN utm_source TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN utm_medium TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN utm_campaign TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN utm_content TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN utm_term TEXT NOT NULL DEFAULT '';
+ALTER TABLE urls ADD COLUMN utm_version INTEGER NOT NULL DEFAULT 0;
+CREATE TABLE IF NOT EXISTS utm_templates(
+alias TEXT NOT NULL,
+version INTEGER NOT NULL,
+source TEXT NOT NULL DEFAULT '',
+medium TEXT NOT NULL DEFAULT '',
+campaign TEXT NOT NULL DEFAULT '',
+content TEXT NOT NULL DEFAULT '',
+term TEXT NOT NULL DEFAULT '',
+created_at TIMESTAMP NOT NULL,
+PRIMARY KEY (alias, version));
+CREATE TABLE IF NOT EXISTS clicks(
+id INTEGER PRIMARY KEY,
+alias TEXT NOT NULL,
+utm_version INTEGER NOT NULL DEFAULT 0,
+clicked_at TIMESTAMP NOT NULL);
+CREATE INDEX IF NOT EXISTS idx_clicks_alias ON clicks(alias, utm_version);